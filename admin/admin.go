@@ -0,0 +1,154 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/config"
+	"letovo-computers-server/metrics"
+	"letovo-computers-server/state"
+	"letovo-computers-server/types"
+)
+
+var (
+	errUnauthorized   = errors.New("unauthorized")
+	errUnknownCommand = errors.New("unknown command")
+	errMissingArg     = errors.New("missing argument")
+)
+
+// Command is an admin action sent to the SERVER_COMMAND_TOPIC, the ID being echoed in its response
+type Command struct {
+	ID    string            `json:"id,omitempty"`
+	Cmd   string            `json:"cmd"`
+	Token string            `json:"token"`
+	Args  map[string]string `json:"args"`
+}
+
+// Response is published to the command response topic after a Command is dispatched
+type Response struct {
+	ID    string `json:"id,omitempty"`
+	Cmd   string `json:"cmd"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// ResponseTopic returns the topic command responses are published to
+func ResponseTopic() string {
+	if topic := config.String("SERVER_COMMAND_RESPONSE_TOPIC", ""); topic != "" {
+		return topic
+	}
+
+	return config.String("SERVER_COMMAND_TOPIC", "") + "/response"
+}
+
+// Handle returns the callback dispatching admin commands to the corresponding internal actions. The responses go
+// to the response topic MQTT 5 clients name in the command, echoing its correlation data, or to ResponseTopic
+func Handle(ctx context.Context) func(client mqtt.Client, resp mqtt.Message) {
+	return func(client mqtt.Client, resp mqtt.Message) {
+		command := new(Command)
+
+		err := json.Unmarshal(resp.Payload(), command)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to unmarshal command")
+			metrics.ObserveParseFailure("SERVER_COMMAND_TOPIC")
+			return
+		}
+
+		err = dispatch(ctx, client, command)
+		response := Response{ID: command.ID, Cmd: command.Cmd, OK: err == nil}
+		if err != nil {
+			response.Error = err.Error()
+			log.Warn().Err(err).Str("cmd", command.Cmd).Msg("admin command failed")
+		} else {
+			log.Info().Str("cmd", command.Cmd).Interface("args", command.Args).Msg("admin command succeeded")
+		}
+
+		payload, err := json.Marshal(response)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to marshal command response")
+			return
+		}
+
+		respond(client, resp, payload)
+	}
+}
+
+// respond publishes the response payload to the command, with retries unless the command named a response topic
+// or correlation data of its own, which only matter to the client while it waits for the response
+func respond(client broker.Broker, command mqtt.Message, payload []byte) {
+	delivery := broker.DeliveryFor("SERVER_COMMAND_RESPONSE_TOPIC")
+
+	props, _ := broker.MessageProperties(command)
+	if props.ResponseTopic == "" && props.CorrelationData == nil {
+		var wg sync.WaitGroup
+		broker.Publish(&wg, client, ResponseTopic(), delivery, string(payload))
+		return
+	}
+
+	topic := props.ResponseTopic
+	if topic == "" {
+		topic = ResponseTopic()
+	}
+
+	t := broker.PublishWithProperties(client, topic, delivery.QoS, delivery.Retained, payload, broker.Properties{
+		CorrelationData: props.CorrelationData,
+		ContentType:     "application/json",
+	})
+	go func() {
+		<-t.Done()
+		if t.Error() != nil {
+			log.Error().Err(t.Error()).Str("topic", topic).Msg("failed to publish command response")
+		}
+	}()
+}
+
+func dispatch(ctx context.Context, client broker.Broker, command *Command) error {
+	if !authorized(command.Token) {
+		return errUnauthorized
+	}
+
+	switch command.Cmd {
+	case "force-snapshot":
+		return state.PublishSnapshot(ctx, client)
+
+	case "reset-slot":
+		slotID := types.CanonicalizeSlotID(command.Args["slot"])
+		if slotID == "" {
+			return fmt.Errorf("%w: slot", errMissingArg)
+		}
+
+		if err := state.ReleaseSlot(ctx, slotID); err != nil {
+			return err
+		}
+
+		state.AnnounceRelease(ctx, client, slotID)
+		return nil
+
+	case "reconcile":
+		var wg sync.WaitGroup
+		broker.Publish(&wg, client, config.String("SERVER_STREAM_TOPIC", ""), broker.DeliveryFor("SERVER_STREAM_TOPIC"), "{\"message\":\"resync\"}")
+
+		return state.PublishSnapshot(ctx, client)
+
+	default:
+		return fmt.Errorf("%w: %s", errUnknownCommand, command.Cmd)
+	}
+}
+
+// authorized reports whether the token matches SERVER_COMMAND_TOKEN. Commands are always rejected when no token is configured
+func authorized(token string) bool {
+	expected := config.String("SERVER_COMMAND_TOKEN", "")
+	if expected == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
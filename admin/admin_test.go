@@ -0,0 +1,144 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+
+	"letovo-computers-server/broker/brokertest"
+	"letovo-computers-server/models"
+	"letovo-computers-server/storage/storagetest"
+)
+
+// testDB migrates a fresh sqlite db for the test, holding a single slot, and makes it the global db of sqlboiler
+func testDB(t *testing.T) {
+	t.Helper()
+
+	storagetest.New(t)
+
+	ctx := context.Background()
+	if err := (&models.User{ID: "0001"}).InsertG(ctx, boil.Infer()); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&models.Slot{ID: "A1", TakenBy: "0001", IsTaken: true}).InsertG(ctx, boil.Infer()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// send hands the command to the handler, returning the response it published
+func send(t *testing.T, client *brokertest.Fake, command Command) Response {
+	t.Helper()
+
+	payload, err := json.Marshal(command)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Handle(context.Background())(client, brokertest.NewMessage("server/commands", payload, 1, false))
+
+	published := client.Published(ResponseTopic())
+	if len(published) != 1 {
+		t.Fatalf("published %d responses, want 1", len(published))
+	}
+
+	var response Response
+	if err := json.Unmarshal(published[0].Payload(), &response); err != nil {
+		t.Fatal(err)
+	}
+
+	return response
+}
+
+func TestForceSnapshot(t *testing.T) {
+	testDB(t)
+	t.Setenv("SERVER_COMMAND_TOPIC", "server/commands")
+	t.Setenv("SERVER_COMMAND_TOKEN", "secret")
+	t.Setenv("SERVER_STATE_TOPIC", "server/state")
+
+	client := brokertest.New()
+
+	response := send(t, client, Command{Cmd: "force-snapshot", Token: "secret"})
+	if !response.OK || response.Error != "" {
+		t.Fatalf("response = %+v, want ok", response)
+	}
+
+	snapshots := client.Published("server/state")
+	if len(snapshots) != 1 {
+		t.Fatalf("published %d snapshots, want 1", len(snapshots))
+	}
+
+	var slots models.SlotSlice
+	if err := json.Unmarshal(snapshots[0].Payload(), &slots); err != nil {
+		t.Fatal(err)
+	}
+	if len(slots) != 1 || slots[0].ID != "A1" || !slots[0].IsTaken {
+		t.Errorf("snapshot = %+v, want the taken A1", slots)
+	}
+}
+
+func TestUnauthorizedCommand(t *testing.T) {
+	testDB(t)
+	t.Setenv("SERVER_COMMAND_TOPIC", "server/commands")
+	t.Setenv("SERVER_COMMAND_TOKEN", "secret")
+	t.Setenv("SERVER_STATE_TOPIC", "server/state")
+
+	client := brokertest.New()
+
+	response := send(t, client, Command{Cmd: "force-snapshot", Token: "guess"})
+	if response.OK || response.Error != errUnauthorized.Error() {
+		t.Fatalf("response = %+v, want %q", response, errUnauthorized)
+	}
+
+	if snapshots := client.Published("server/state"); len(snapshots) != 0 {
+		t.Errorf("published %d snapshots, want none", len(snapshots))
+	}
+}
+
+func TestResetSlot(t *testing.T) {
+	testDB(t)
+	t.Setenv("SERVER_COMMAND_TOPIC", "server/commands")
+	t.Setenv("SERVER_COMMAND_TOKEN", "secret")
+	t.Setenv("SERVER_STATE_TOPIC", "server/state")
+
+	client := brokertest.New()
+
+	response := send(t, client, Command{ID: "42", Cmd: "reset-slot", Token: "secret", Args: map[string]string{"slot": "a1"}})
+	if !response.OK || response.ID != "42" {
+		t.Fatalf("response = %+v, want ok echoing id 42", response)
+	}
+
+	slot, err := models.FindSlotG(context.Background(), "A1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slot.IsTaken {
+		t.Error("A1 is still taken after the reset")
+	}
+
+	// the snapshot is queued along with the release, so that it is published once the release commits
+	snapshots, err := models.OutboxMessages(models.OutboxMessageWhere.Topic.EQ("server/state")).AllG(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 {
+		t.Fatalf("queued %d snapshots, want 1", len(snapshots))
+	}
+
+	var slots models.SlotSlice
+	if err := json.Unmarshal(snapshots[0].Payload, &slots); err != nil {
+		t.Fatal(err)
+	}
+	if len(slots) != 1 || slots[0].IsTaken {
+		t.Errorf("snapshot = %+v, want the released A1", slots)
+	}
+
+	states, err := models.OutboxMessages(models.OutboxMessageWhere.Topic.EQ("server/state/A1")).CountG(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if states != 1 {
+		t.Errorf("queued %d states of A1, want 1", states)
+	}
+}
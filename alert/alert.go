@@ -0,0 +1,60 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"letovo-computers-server/config"
+)
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+// Alert is the payload posted to the ALERT_WEBHOOK_URL
+type Alert struct {
+	Source    string    `json:"source"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Enabled reports whether the alert webhook is configured
+func Enabled() bool {
+	return config.String("ALERT_WEBHOOK_URL", "") != ""
+}
+
+// Send posts the alert to the ALERT_WEBHOOK_URL. It is a no-op when the webhook is not configured
+func Send(ctx context.Context, source, message string) error {
+	if !Enabled() {
+		return nil
+	}
+
+	body, err := json.Marshal(Alert{
+		Source:    source,
+		Message:   message,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.String("ALERT_WEBHOOK_URL", ""), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("alert webhook responded with %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package aliases
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"letovo-computers-server/types"
+)
+
+// Aliases translates machine slot IDs to human-friendly names, such as "Row 3 Seat 5", and back
+type Aliases struct {
+	byID    map[string]string
+	byAlias map[string]string
+}
+
+// New creates Aliases from the mapping of slot IDs to their names
+func New(mapping map[string]string) *Aliases {
+	a := &Aliases{
+		byID:    make(map[string]string, len(mapping)),
+		byAlias: make(map[string]string, len(mapping)),
+	}
+
+	for id, alias := range mapping {
+		id, alias = types.CanonicalizeSlotID(id), strings.TrimSpace(alias)
+		a.byID[id] = alias
+		a.byAlias[strings.ToLower(alias)] = id
+	}
+
+	return a
+}
+
+// Load reads the JSON object mapping slot IDs to their names from the file.
+// An empty path yields empty Aliases
+func Load(path string) (*Aliases, error) {
+	mapping := make(map[string]string)
+	if path == "" {
+		return New(mapping), nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read slot aliases: %w", err)
+	}
+
+	err = json.Unmarshal(b, &mapping)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal slot aliases: %w", err)
+	}
+
+	return New(mapping), nil
+}
+
+// Alias returns the name of the slot, or an empty string if it has none
+func (a *Aliases) Alias(id string) string {
+	return a.byID[types.CanonicalizeSlotID(id)]
+}
+
+// ID translates the slot name to its ID. Anything that is not a known name is treated as an ID and canonicalized
+func (a *Aliases) ID(nameOrID string) string {
+	if id, ok := a.byAlias[strings.ToLower(strings.TrimSpace(nameOrID))]; ok {
+		return id
+	}
+
+	return types.CanonicalizeSlotID(nameOrID)
+}
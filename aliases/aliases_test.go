@@ -0,0 +1,43 @@
+package aliases
+
+import "testing"
+
+func TestRoundTrip(t *testing.T) {
+	a := New(map[string]string{"A1": "Row 1 Seat 1", "b-2": " Row 2 Seat 2 "})
+
+	tests := []struct {
+		id    string
+		alias string
+	}{
+		{id: "A1", alias: "Row 1 Seat 1"},
+		{id: "B2", alias: "Row 2 Seat 2"},
+		{id: "C3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			alias := a.Alias(tt.id)
+			if alias != tt.alias {
+				t.Fatalf("Alias(%q) = %q, want %q", tt.id, alias, tt.alias)
+			}
+
+			// an unknown ID has no alias and is taken back as the ID itself
+			if alias == "" {
+				alias = tt.id
+			}
+			if id := a.ID(alias); id != tt.id {
+				t.Errorf("ID(%q) = %q, want %q", alias, id, tt.id)
+			}
+		})
+	}
+}
+
+func TestIDCanonicalizes(t *testing.T) {
+	a := New(map[string]string{"A1": "Row 1 Seat 1"})
+
+	for _, nameOrID := range []string{"row 1 seat 1", " Row 1 Seat 1 ", "a1", "A-1"} {
+		if id := a.ID(nameOrID); id != "A1" {
+			t.Errorf("ID(%q) = %q, want A1", nameOrID, id)
+		}
+	}
+}
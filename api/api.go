@@ -0,0 +1,106 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/aliases"
+	"letovo-computers-server/auth"
+	"letovo-computers-server/broker"
+	"letovo-computers-server/commands"
+	"letovo-computers-server/events"
+	"letovo-computers-server/graph"
+	"letovo-computers-server/health"
+	"letovo-computers-server/recent"
+	"letovo-computers-server/scopes"
+)
+
+// Options are the services backing the HTTP endpoints
+type Options struct {
+	Client   broker.Broker
+	DB       *sql.DB
+	Events   *recent.Buffer
+	Hub      *events.Hub
+	Aliases  *aliases.Aliases
+	Health   *health.Monitor
+	Scopes   *scopes.Scopes
+	Commands *commands.Dispatcher
+}
+
+type server struct {
+	opts Options
+}
+
+//go:generate oapi-codegen -generate types,chi-server,spec -package api -o openapi.gen.go openapi.yaml
+
+// New creates the HTTP server exposing the service endpoints on addr
+func New(addr string, opts Options) *http.Server {
+	s := &server{opts: opts}
+
+	r := chi.NewRouter()
+	r.Use(rateLimit(newRateLimiter()))
+	r.Handle("/metrics", promhttp.Handler())
+	r.Get("/api/v1/openapi.json", s.openAPI)
+	r.Handle("/admin/*", dashboardHandler())
+	r.Get("/admin", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/admin/", http.StatusMovedPermanently)
+	})
+	r.With(requireRole(auth.RoleViewer, scopePersonal)).Handle("/graphql", graph.New(graph.Options{Aliases: opts.Aliases}))
+
+	// the last middleware runs first, so that the audit knows who was authorized
+	options := ChiServerOptions{
+		BaseRouter:       r,
+		Middlewares:      []MiddlewareFunc{audit, authorize},
+		ErrorHandlerFunc: paramError,
+	}
+
+	// the unversioned routes are kept for the existing dashboards and the health checks
+	for _, base := range []string{"/api/v1", ""} {
+		options.BaseURL = base
+		HandlerWithOptions(s, options)
+	}
+
+	return &http.Server{
+		Addr:    addr,
+		Handler: r,
+	}
+}
+
+func (s *server) ListRecentEvents(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.opts.Events.List())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error().Err(err).Msg("failed to write response")
+	}
+}
+
+// openAPI serves the contract of the API, generated from openapi.yaml
+func (s *server) openAPI(w http.ResponseWriter, r *http.Request) {
+	spec, err := GetSwagger()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load openapi spec")
+		writeError(w, http.StatusInternalServerError, "failed to load openapi spec")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, spec)
+}
+
+// paramError reports the parameters the generated handlers failed to bind
+func paramError(w http.ResponseWriter, r *http.Request, err error) {
+	writeError(w, http.StatusBadRequest, err.Error())
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,285 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v4"
+
+	"letovo-computers-server/auth"
+	"letovo-computers-server/broker/brokertest"
+	"letovo-computers-server/health"
+	"letovo-computers-server/recent"
+	"letovo-computers-server/types"
+)
+
+func TestRecentEvents(t *testing.T) {
+	buffer := recent.New(3)
+	for _, slotID := range []string{"A1", "A2", "A3", "A4", "A5"} {
+		buffer.Add(types.Event{RFID: "0001", Slot: slotID, Status: types.Taken, Result: "ok"})
+	}
+
+	srv := New("", Options{Events: buffer})
+
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/recent", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var events []types.Event
+	if err := json.Unmarshal(rec.Body.Bytes(), &events); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"A5", "A4", "A3"}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d", len(events), len(want))
+	}
+	for i, event := range events {
+		if event.Slot != want[i] {
+			t.Errorf("events[%d].Slot = %s, want %s", i, event.Slot, want[i])
+		}
+	}
+}
+
+func TestHealthzDegraded(t *testing.T) {
+	client := brokertest.New()
+	if token := client.Connect(); token.Error() != nil {
+		t.Fatal(token.Error())
+	}
+
+	monitor := health.NewMonitor(time.Hour, 0.5, 2)
+	srv := New("", Options{Client: client, Health: monitor})
+
+	get := func() (int, healthReport) {
+		rec := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		var report healthReport
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+
+		return rec.Code, report
+	}
+
+	monitor.Observe(nil)
+	monitor.Observe(nil)
+	if code, report := get(); code != http.StatusOK || report.Status.Status != health.StatusOK {
+		t.Fatalf("/healthz = %d %+v, want ok", code, report)
+	}
+
+	for i := 0; i < 3; i++ {
+		monitor.Observe(errors.New("failed to upsert"))
+	}
+	code, report := get()
+	if code != http.StatusServiceUnavailable || report.Status.Status != health.StatusDegraded || report.ErrorRatio != 0.6 {
+		t.Errorf("/healthz = %d %+v, want degraded at 0.6", code, report)
+	}
+}
+
+func TestPersonalRequiresToken(t *testing.T) {
+	t.Setenv("JWT_ALG", "")
+	t.Setenv("API_ADMIN_TOKEN", "secret")
+
+	srv := New("", Options{})
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		header string
+		code   int
+	}{
+		{name: "user anonymously", method: http.MethodGet, target: "/api/v1/users/0001", code: http.StatusUnauthorized},
+		{name: "unversioned user anonymously", method: http.MethodGet, target: "/users/0001", code: http.StatusUnauthorized},
+		{name: "graphql anonymously", method: http.MethodPost, target: "/graphql", code: http.StatusUnauthorized},
+		{name: "graphql with a query token", method: http.MethodPost, target: "/graphql?access_token=secret", code: http.StatusUnauthorized},
+		{name: "graphql with a token", method: http.MethodPost, target: "/graphql", header: "Bearer secret", code: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(`{"query":"{__typename}"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			rec := httptest.NewRecorder()
+			srv.Handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.code {
+				t.Errorf("%s %s = %d %s, want %d", tt.method, tt.target, rec.Code, rec.Body, tt.code)
+			}
+		})
+	}
+}
+
+func TestTokenRequiresExpiry(t *testing.T) {
+	t.Setenv("JWT_ALG", jwt.SigningMethodHS256.Alg())
+	t.Setenv("JWT_SECRET", "secret")
+
+	srv := New("", Options{})
+
+	tests := []struct {
+		name    string
+		expires *jwt.NumericDate
+		code    int
+	}{
+		{name: "without expiry", code: http.StatusUnauthorized},
+		{name: "expired", expires: jwt.NewNumericDate(time.Now().Add(-time.Minute)), code: http.StatusUnauthorized},
+		{name: "expiring", expires: jwt.NewNumericDate(time.Now().Add(time.Minute)), code: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := jwt.MapClaims{"role": "admin"}
+			if tt.expires != nil {
+				claims["exp"] = tt.expires
+			}
+
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"{__typename}"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			rec := httptest.NewRecorder()
+			srv.Handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.code {
+				t.Errorf("POST /graphql = %d %s, want %d", rec.Code, rec.Body, tt.code)
+			}
+		})
+	}
+}
+
+func TestBearerTokenQueryOnlyForStreams(t *testing.T) {
+	var token string
+	r := chi.NewRouter()
+	record := func(w http.ResponseWriter, r *http.Request) { token = bearerToken(r) }
+	for _, pattern := range []string{"/api/v1/events", "/ws/events", "/api/v1/users/{rfid}"} {
+		r.Get(pattern, record)
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		header map[string]string
+		token  string
+	}{
+		{name: "sse", target: "/api/v1/events", header: map[string]string{"Accept": "text/event-stream"}, token: "secret"},
+		{name: "event history", target: "/api/v1/events"},
+		{
+			name:   "websocket",
+			target: "/ws/events",
+			header: map[string]string{"Connection": "Upgrade", "Upgrade": "websocket"},
+			token:  "secret",
+		},
+		{name: "user", target: "/api/v1/users/0001"},
+		{name: "bearer header", target: "/api/v1/users/0001", header: map[string]string{"Authorization": "Bearer secret"}, token: "secret"},
+		{name: "bare header", target: "/api/v1/users/0001", header: map[string]string{"Authorization": "secret"}},
+		{name: "basic header", target: "/api/v1/users/0001", header: map[string]string{"Authorization": "Basic c2VjcmV0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token = "unset"
+			req := httptest.NewRequest(http.MethodGet, tt.target+"?access_token=secret", nil)
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			if token != tt.token {
+				t.Errorf("bearerToken() = %q, want %q", token, tt.token)
+			}
+		})
+	}
+}
+
+func TestRateLimitIgnoresMadeUpTokens(t *testing.T) {
+	t.Setenv("JWT_ALG", "")
+	t.Setenv("API_ADMIN_TOKEN", "secret")
+	t.Setenv("API_RATE_LIMIT", "0.001")
+	t.Setenv("API_RATE_BURST", "2")
+
+	srv := New("", Options{Events: recent.New(1)})
+
+	get := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/recent", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		rec := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	// every made-up token draws from the bucket of the address
+	for i, token := range []string{"made-up-1", "made-up-2", "made-up-3"} {
+		want := http.StatusOK
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+		if code := get(token); code != want {
+			t.Errorf("request with token %s = %d, want %d", token, code, want)
+		}
+	}
+
+	if code := get("secret"); code != http.StatusOK {
+		t.Errorf("request with the admin token = %d, want %d from a bucket of its own", code, http.StatusOK)
+	}
+}
+
+func TestRateLimitKeysEventStreamsByQueryToken(t *testing.T) {
+	t.Setenv("JWT_ALG", "")
+	t.Setenv("API_ADMIN_TOKEN", "secret")
+	t.Setenv("API_RATE_LIMIT", "0.001")
+	t.Setenv("API_RATE_BURST", "1")
+
+	// the tokens let through are to be authenticated already
+	handler := rateLimit(newRateLimiter())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(authenticatedKey{}).(*auth.Identity); bearerToken(r) != "" && !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+
+	get := func(target string) int {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("Accept", "text/event-stream")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	tests := []struct {
+		target string
+		code   int
+	}{
+		{target: "/recent", code: http.StatusOK},
+		// the query token is only taken on the event streams, so this one draws from the bucket of the address
+		{target: "/recent?access_token=secret", code: http.StatusTooManyRequests},
+		{target: "/api/v1/events?access_token=secret", code: http.StatusOK},
+		{target: "/api/v1/events?access_token=secret", code: http.StatusTooManyRequests},
+	}
+	for i, tt := range tests {
+		if code := get(tt.target); code != tt.code {
+			t.Errorf("request %d to %s = %d, want %d", i, tt.target, code, tt.code)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/models"
+)
+
+// maxAuditedBody caps the responses kept as the values after the change, the larger ones being left out
+const maxAuditedBody = 64 << 10
+
+type auditKey struct{}
+
+// auditEntry collects the values the handler changed while it serves the request
+type auditEntry struct {
+	before null.JSON
+	after  null.JSON
+	// afterSet tells apart the values after the change set by the handler from the response
+	afterSet bool
+}
+
+// auditRecorder keeps the status and the body of the response as they are written
+type auditRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+	// truncated is set once the body outgrew maxAuditedBody
+	truncated bool
+}
+
+func (a *auditRecorder) WriteHeader(status int) {
+	a.status = status
+	a.ResponseWriter.WriteHeader(status)
+}
+
+func (a *auditRecorder) Write(p []byte) (int, error) {
+	if a.body.Len()+len(p) > maxAuditedBody {
+		a.truncated = true
+	} else if !a.truncated {
+		a.body.Write(p)
+	}
+
+	return a.ResponseWriter.Write(p)
+}
+
+// audit logs the mutating requests to the audit_log table along with who made them, from where, and the values
+// the handlers changed. The values after the change are the successful responses, unless the handler sets them
+// with auditAfter, and the values before it are only known once the handler sets them with auditBefore
+func audit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		entry := new(auditEntry)
+		recorder := &auditRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(context.WithValue(r.Context(), auditKey{}, entry)))
+
+		row := models.AuditLog{
+			Method:   r.Method,
+			Path:     r.URL.Path,
+			Status:   recorder.status,
+			Before:   entry.before,
+			After:    entry.after,
+			SourceIP: clientIP(r),
+		}
+		if identity := identityFrom(r.Context()); identity != nil {
+			row.Subject, row.Role = identity.Subject, identity.Role
+		}
+		if !entry.afterSet && !recorder.truncated && recorder.status < http.StatusMultipleChoices &&
+			json.Valid(recorder.body.Bytes()) {
+			row.After = null.JSONFrom(bytes.TrimSpace(recorder.body.Bytes()))
+		}
+
+		// the request is served by now, so the failure to log it can only be logged
+		if err := row.InsertG(r.Context(), boil.Infer()); err != nil {
+			log.Error().Err(err).Str("method", r.Method).Str("path", r.URL.Path).Msg("failed to log audit entry")
+		}
+	})
+}
+
+// auditBefore sets the values the request is about to change, as they are when passed
+func auditBefore(r *http.Request, v interface{}) {
+	if entry, ok := r.Context().Value(auditKey{}).(*auditEntry); ok {
+		entry.before = auditValue(v)
+	}
+}
+
+// auditAfter sets the values the request changed in place of the response, like to leave its secrets out
+func auditAfter(r *http.Request, v interface{}) {
+	if entry, ok := r.Context().Value(auditKey{}).(*auditEntry); ok {
+		entry.after, entry.afterSet = auditValue(v), true
+	}
+}
+
+func auditValue(v interface{}) null.JSON {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to encode audited value")
+		return null.JSON{}
+	}
+
+	return null.JSONFrom(data)
+}
+
+func (s *server) ListAuditLog(w http.ResponseWriter, r *http.Request, params ListAuditLogParams) {
+	var filters []qm.QueryMod
+	if params.From != nil {
+		filters = append(filters, models.AuditLogWhere.CreatedAt.GTE(*params.From))
+	}
+
+	if params.To != nil {
+		filters = append(filters, models.AuditLogWhere.CreatedAt.LT(*params.To))
+	}
+
+	if params.Subject != nil && *params.Subject != "" {
+		filters = append(filters, models.AuditLogWhere.Subject.EQ(*params.Subject))
+	}
+
+	limit, offset, err := pagination(params.Limit, params.Offset)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	total, err := models.AuditLogs(filters...).CountG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to count audit entries")
+		writeError(w, http.StatusInternalServerError, "failed to fetch audit log")
+		return
+	}
+
+	page := []qm.QueryMod{
+		qm.OrderBy(models.AuditLogColumns.ID + " DESC"),
+		qm.Limit(limit),
+		qm.Offset(offset),
+	}
+
+	entries, err := models.AuditLogs(append(filters, page...)...).AllG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch audit entries")
+		writeError(w, http.StatusInternalServerError, "failed to fetch audit log")
+		return
+	}
+
+	if entries == nil {
+		entries = models.AuditLogSlice{}
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	writeJSON(w, http.StatusOK, entries)
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/models"
+	"letovo-computers-server/state"
+	"letovo-computers-server/types"
+)
+
+// maxBulkSlots bounds the slots provisioned by a single request
+const maxBulkSlots = 1000
+
+type bulkSlot struct {
+	ID        string `json:"id"`
+	CabinetID string `json:"cabinet_id"`
+	Row       int    `json:"row"`
+	Column    int    `json:"column"`
+	Label     string `json:"label"`
+	Notes     string `json:"notes"`
+}
+
+// decodeBulkSlots reads the slots to provision from either a JSON array or a CSV with a header row naming
+// the id, cabinet_id, row, column, label and notes columns, of which only id is required
+func decodeBulkSlots(r *http.Request) ([]bulkSlot, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "text/csv" {
+		var slots []bulkSlot
+		if err := json.NewDecoder(r.Body).Decode(&slots); err != nil {
+			return nil, errors.New("malformed request body")
+		}
+
+		return slots, nil
+	}
+
+	reader := csv.NewReader(r.Body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("malformed csv header")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	if _, ok := columns["id"]; !ok {
+		return nil, errors.New("csv header must name the id column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+
+		return ""
+	}
+
+	number := func(record []string, name string) (int, error) {
+		v := field(record, name)
+		if v == "" {
+			return 0, nil
+		}
+
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("malformed %s %q", name, v)
+		}
+
+		return n, nil
+	}
+
+	var slots []bulkSlot
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("malformed csv: %w", err)
+		}
+
+		slot := bulkSlot{
+			ID:        field(record, "id"),
+			CabinetID: field(record, "cabinet_id"),
+			Label:     field(record, "label"),
+			Notes:     field(record, "notes"),
+		}
+		if slot.Row, err = number(record, "row"); err != nil {
+			return nil, err
+		}
+		if slot.Column, err = number(record, "column"); err != nil {
+			return nil, err
+		}
+
+		slots = append(slots, slot)
+	}
+
+	return slots, nil
+}
+
+// BulkCreateSlots provisions the slots in a single transaction, rejecting the whole request if any of them already exists
+func (s *server) BulkCreateSlots(w http.ResponseWriter, r *http.Request) {
+	requested, err := decodeBulkSlots(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(requested) == 0 || len(requested) > maxBulkSlots {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("must provision between 1 and %d slots", maxBulkSlots))
+		return
+	}
+
+	slots := make(models.SlotSlice, 0, len(requested))
+	ids := make([]string, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, slot := range requested {
+		id := types.CanonicalizeSlotID(slot.ID)
+		if err := types.ValidateSlotID(id); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if seen[id] {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("duplicate slot %s", id))
+			return
+		}
+		seen[id] = true
+
+		ids = append(ids, id)
+		slots = append(slots, &models.Slot{
+			ID:        id,
+			TakenBy:   state.NobodyRFID,
+			CabinetID: strings.TrimSpace(slot.CabinetID),
+			Row:       slot.Row,
+			Column:    slot.Column,
+			Label:     strings.TrimSpace(slot.Label),
+			Notes:     strings.TrimSpace(slot.Notes),
+		})
+	}
+
+	tx, err := boil.BeginTx(r.Context(), nil)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to begin transaction")
+		writeError(w, http.StatusInternalServerError, "failed to provision slots")
+		return
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	existing, err := models.Slots(models.SlotWhere.ID.IN(ids), qm.WithDeleted()).All(r.Context(), tx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch slots")
+		writeError(w, http.StatusInternalServerError, "failed to provision slots")
+		return
+	}
+
+	if len(existing) > 0 {
+		taken := make([]string, 0, len(existing))
+		for _, slot := range existing {
+			taken = append(taken, types.CanonicalizeSlotID(slot.ID))
+		}
+
+		writeError(w, http.StatusConflict, "slots already exist: "+strings.Join(taken, ", "))
+		return
+	}
+
+	for _, slot := range slots {
+		err = slot.Insert(r.Context(), tx, boil.Infer())
+		if err != nil {
+			log.Error().Err(err).Msg("failed to insert slot")
+			writeError(w, http.StatusInternalServerError, "failed to provision slots")
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Error().Err(err).Msg("failed to commit transaction")
+		writeError(w, http.StatusInternalServerError, "failed to provision slots")
+		return
+	}
+
+	log.Info().Int("count", len(slots)).Msgf("provisioned %d slots", len(slots))
+
+	response := make([]slotResponse, 0, len(slots))
+	for _, slot := range slots {
+		response = append(response, s.slotResponse(slot))
+	}
+
+	writeJSON(w, http.StatusCreated, response)
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/commands"
+	"letovo-computers-server/config"
+	"letovo-computers-server/models"
+)
+
+func (s *server) ListCommands(w http.ResponseWriter, r *http.Request, params ListCommandsParams) {
+	var filters []qm.QueryMod
+	if params.Device != nil && *params.Device != "" {
+		filters = append(filters, models.CommandWhere.Device.EQ(*params.Device))
+	}
+	if params.Status != nil {
+		filters = append(filters, models.CommandWhere.Status.EQ(string(*params.Status)))
+	}
+
+	limit, offset, err := pagination(params.Limit, params.Offset)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	total, err := models.Commands(filters...).CountG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to count commands")
+		writeError(w, http.StatusInternalServerError, "failed to fetch commands")
+		return
+	}
+
+	page := []qm.QueryMod{qm.OrderBy(models.CommandColumns.CreatedAt + " DESC"), qm.Limit(limit), qm.Offset(offset)}
+
+	rows, err := models.Commands(append(filters, page...)...).AllG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch commands")
+		writeError(w, http.StatusInternalServerError, "failed to fetch commands")
+		return
+	}
+
+	if rows == nil {
+		rows = models.CommandSlice{}
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	writeJSON(w, http.StatusOK, rows)
+}
+
+type commandRequest struct {
+	Cmd    string `json:"cmd"`
+	Device string `json:"device"`
+	Slot   string `json:"slot"`
+	TTL    *int   `json:"ttl"`
+}
+
+// CreateCommand queues the command, replying before the device does. The command is sent to the device owning
+// the slot unless the device is given, and to every device when neither is
+func (s *server) CreateCommand(w http.ResponseWriter, r *http.Request) {
+	request := new(commandRequest)
+
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "malformed request body")
+		return
+	}
+
+	if !commands.ValidCmd(request.Cmd) {
+		writeError(w, http.StatusBadRequest, "unknown command "+request.Cmd)
+		return
+	}
+
+	ttl := config.Duration("COMMAND_TTL", time.Minute)
+	if request.TTL != nil {
+		if *request.TTL < 1 {
+			writeError(w, http.StatusBadRequest, "ttl must be positive")
+			return
+		}
+
+		ttl = time.Duration(*request.TTL) * time.Second
+	}
+
+	command := commands.Command{Cmd: request.Cmd, Device: request.Device}
+	if request.Slot != "" {
+		command.Slot = s.opts.Aliases.ID(request.Slot)
+
+		_, err = models.FindSlotG(r.Context(), command.Slot)
+		if errors.Is(err, sql.ErrNoRows) {
+			writeError(w, http.StatusNotFound, "slot not found")
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("failed to fetch slot")
+			writeError(w, http.StatusInternalServerError, "failed to fetch slot")
+			return
+		}
+
+		if command.Device == "" {
+			command.Device = s.opts.Scopes.Device(command.Slot)
+		}
+	}
+
+	if command.Cmd == commands.CmdUnlock && command.Slot == "" {
+		writeError(w, http.StatusBadRequest, "unlock needs a slot")
+		return
+	}
+
+	row, err := s.opts.Commands.Enqueue(r.Context(), command, ttl)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to queue command")
+		writeError(w, http.StatusInternalServerError, "failed to queue command")
+		return
+	}
+
+	log.Info().Str("command", row.ID).Str("device", row.Device).Msgf("queued %s command", row.CMD)
+
+	writeJSON(w, http.StatusAccepted, row)
+}
+
+func (s *server) GetCommand(w http.ResponseWriter, r *http.Request, id string) {
+	row, err := models.FindCommandG(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "command not found")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch command")
+		writeError(w, http.StatusInternalServerError, "failed to fetch command")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, row)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/models"
+	"letovo-computers-server/provisioning"
+)
+
+func (s *server) CreateEnrollmentToken(w http.ResponseWriter, r *http.Request, id string) {
+	token, expiresAt, err := provisioning.NewToken(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create enrollment token")
+		writeError(w, http.StatusInternalServerError, "failed to create enrollment token")
+		return
+	}
+
+	log.Info().Str("device", id).Time("expires_at", expiresAt).Msgf("created enrollment token for %s", id)
+
+	// the token is a secret, shown only once
+	auditAfter(r, EnrollmentToken{Device: id, ExpiresAt: expiresAt})
+
+	writeJSON(w, http.StatusCreated, EnrollmentToken{Device: id, Token: token, ExpiresAt: expiresAt})
+}
+
+func (s *server) ListDeviceCredentials(w http.ResponseWriter, r *http.Request, id string) {
+	credentials, err := provisioning.List(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch device credentials")
+		writeError(w, http.StatusInternalServerError, "failed to fetch device credentials")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, credentialList(credentials))
+}
+
+// RotateDeviceCredentials issues the device new credentials and sends them to it, the device reconnecting
+// with them before the current ones expire
+func (s *server) RotateDeviceCredentials(w http.ResponseWriter, r *http.Request, id string) {
+	issued, err := provisioning.Rotate(r.Context(), id)
+	if errors.Is(err, provisioning.ErrNotEnrolled) {
+		writeError(w, http.StatusNotFound, "device is not enrolled")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to rotate device credentials")
+		writeError(w, http.StatusInternalServerError, "failed to rotate device credentials")
+		return
+	}
+
+	provisioning.Deliver(s.opts.Client, issued, broker.Properties{})
+
+	log.Info().Str("device", id).Msgf("rotated credentials of %s", id)
+
+	credentials, err := provisioning.List(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch device credentials")
+		writeError(w, http.StatusInternalServerError, "failed to fetch device credentials")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, credentialList(credentials))
+}
+
+// credentialList converts the credentials to their API representation, which leaves out the secrets
+func credentialList(credentials models.DeviceCredentialSlice) []DeviceCredential {
+	list := make([]DeviceCredential, 0, len(credentials))
+	for _, c := range credentials {
+		credential := DeviceCredential{Id: c.ID, Device: c.Device, CreatedAt: c.CreatedAt}
+		if c.ExpiresAt.Valid {
+			credential.ExpiresAt = &c.ExpiresAt.Time
+		}
+
+		list = append(list, credential)
+	}
+
+	return list
+}
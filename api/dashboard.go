@@ -0,0 +1,22 @@
+package api
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed dashboard
+var dashboard embed.FS
+
+// dashboardHandler serves the admin dashboard. The page itself is public, the data is fetched from the API
+// with the token entered into it
+func dashboardHandler() http.Handler {
+	files, err := fs.Sub(dashboard, "dashboard")
+	if err != nil {
+		// the directory is embedded at build time
+		panic(err)
+	}
+
+	return http.StripPrefix("/admin/", http.FileServer(http.FS(files)))
+}
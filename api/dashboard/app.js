@@ -0,0 +1,136 @@
+"use strict";
+
+const api = "/api/v1";
+const statuses = ["placed", "taken", "scanned", "disconnected"];
+const maxEvents = 50;
+// the websocket skips disconnects, so offline devices are picked up by reloading
+const reloadInterval = 60 * 1000;
+
+let events = [];
+let slots = new Map();
+
+function token() {
+  return localStorage.getItem("token") || "";
+}
+
+async function get(path) {
+  const headers = token() ? {Authorization: "Bearer " + token()} : {};
+  const resp = await fetch(api + path, {headers});
+  if (!resp.ok) {
+    throw new Error(path + ": " + resp.status);
+  }
+  return resp.json();
+}
+
+function el(tag, props, ...children) {
+  const node = Object.assign(document.createElement(tag), props);
+  node.append(...children);
+  return node;
+}
+
+function renderSlots() {
+  const cabinets = new Map();
+  for (const slot of slots.values()) {
+    const cabinet = slot.cabinet_id || slot.id.replace(/\d+$/, "");
+    if (!cabinets.has(cabinet)) {
+      cabinets.set(cabinet, []);
+    }
+    cabinets.get(cabinet).push(slot);
+  }
+
+  const root = document.getElementById("cabinets");
+  root.replaceChildren();
+  for (const [cabinet, list] of [...cabinets].sort()) {
+    const columns = Math.max(0, ...list.map(slot => slot.column || 0)) || 10;
+    const grid = el("div", {className: "grid"});
+    grid.style.gridTemplateColumns = `repeat(${columns}, 1fr)`;
+
+    list.sort((a, b) => (a.row - b.row) || (a.column - b.column) || a.id.localeCompare(b.id));
+    for (const slot of list) {
+      const cell = el("div", {className: "slot" + (slot.is_taken ? " taken" : ""), title: slot.taken_by},
+        slot.alias || slot.id,
+        el("small", {}, slot.is_taken ? slot.taken_by : "free"));
+      if (slot.row && slot.column) {
+        cell.style.gridRow = slot.row;
+        cell.style.gridColumn = slot.column;
+      }
+      grid.append(cell);
+    }
+
+    root.append(el("div", {className: "cabinet"}, el("h3", {}, cabinet || "—"), grid));
+  }
+}
+
+function renderEvents() {
+  const body = document.getElementById("events");
+  body.replaceChildren(...events.slice(0, maxEvents).map(event => el("tr", {},
+    el("td", {}, new Date(event.timestamp).toLocaleTimeString()),
+    el("td", {}, event.device || ""),
+    el("td", {}, event.RFID),
+    el("td", {}, event.slot || ""),
+    el("td", {}, statuses[event.status] || event.status),
+    el("td", {}, event.result),
+  )));
+
+  // a device is offline if the last thing heard from it is its last will
+  const latest = new Map();
+  for (const event of [...events].reverse()) {
+    if (event.device) {
+      latest.set(event.device, event);
+    }
+  }
+
+  const offline = [...latest.values()].filter(event => statuses[event.status] === "disconnected");
+  document.getElementById("offline").replaceChildren(...offline.map(event =>
+    el("li", {}, `${event.device} since ${new Date(event.timestamp).toLocaleString()}`)));
+}
+
+function setStatus(text, ok) {
+  const status = document.getElementById("status");
+  status.textContent = text;
+  status.classList.toggle("offline", !ok);
+}
+
+async function load() {
+  const [list, recent] = await Promise.all([get("/slots?limit=1000"), get("/recent")]);
+  slots = new Map(list.map(slot => [slot.id, slot]));
+  events = recent;
+  renderSlots();
+  renderEvents();
+}
+
+function connect() {
+  const scheme = location.protocol === "https:" ? "wss:" : "ws:";
+  const query = token() ? "?access_token=" + encodeURIComponent(token()) : "";
+  const ws = new WebSocket(`${scheme}//${location.host}${api}/ws/events${query}`);
+
+  ws.onopen = () => setStatus("live", true);
+  ws.onclose = () => {
+    setStatus("reconnecting…", false);
+    setTimeout(() => load().then(connect, connect), 5000);
+  };
+  ws.onmessage = async message => {
+    const event = JSON.parse(message.data);
+    events.unshift(event);
+    events = events.slice(0, maxEvents);
+    renderEvents();
+
+    if (event.slot) {
+      slots.set(event.slot, await get("/slots/" + encodeURIComponent(event.slot)));
+      renderSlots();
+    }
+  };
+}
+
+document.getElementById("token").value = token();
+document.getElementById("token-form").addEventListener("submit", event => {
+  event.preventDefault();
+  localStorage.setItem("token", document.getElementById("token").value);
+  location.reload();
+});
+
+load().then(connect, err => {
+  setStatus(err.message, false);
+  connect();
+});
+setInterval(() => load().catch(err => setStatus(err.message, false)), reloadInterval);
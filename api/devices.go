@@ -0,0 +1,229 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/config"
+	"letovo-computers-server/models"
+)
+
+func (s *server) ListDevices(w http.ResponseWriter, r *http.Request, params ListDevicesParams) {
+	var filters []qm.QueryMod
+	if params.Online != nil {
+		filters = append(filters, models.DeviceWhere.Online.EQ(*params.Online))
+	}
+
+	limit, offset, err := pagination(params.Limit, params.Offset)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	total, err := models.Devices(filters...).CountG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to count devices")
+		writeError(w, http.StatusInternalServerError, "failed to fetch devices")
+		return
+	}
+
+	page := []qm.QueryMod{qm.OrderBy(models.DeviceColumns.ID), qm.Limit(limit), qm.Offset(offset)}
+
+	devices, err := models.Devices(append(filters, page...)...).AllG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch devices")
+		writeError(w, http.StatusInternalServerError, "failed to fetch devices")
+		return
+	}
+
+	if devices == nil {
+		devices = models.DeviceSlice{}
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	writeJSON(w, http.StatusOK, devices)
+}
+
+func (s *server) GetDevice(w http.ResponseWriter, r *http.Request, id string) {
+	device, err := models.FindDeviceG(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "device not found")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch device")
+		writeError(w, http.StatusInternalServerError, "failed to fetch device")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, device)
+}
+
+// ListDeviceMessages returns the latest messages received from the device, kept by the raw message log
+func (s *server) ListDeviceMessages(w http.ResponseWriter, r *http.Request, id string, params ListDeviceMessagesParams) {
+	limit, _, err := pagination(params.Limit, nil)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	messages, err := models.RawMessages(
+		models.RawMessageWhere.Device.EQ(id),
+		qm.OrderBy(models.RawMessageColumns.ID+" DESC"),
+		qm.Limit(limit),
+	).AllG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch device messages")
+		writeError(w, http.StatusInternalServerError, "failed to fetch device messages")
+		return
+	}
+
+	if messages == nil {
+		messages = models.RawMessageSlice{}
+	}
+
+	writeJSON(w, http.StatusOK, messages)
+}
+
+// ListDeviceSessions returns the connections of the device started between from and to, newest first
+func (s *server) ListDeviceSessions(w http.ResponseWriter, r *http.Request, id string, params ListDeviceSessionsParams) {
+	filters := []qm.QueryMod{models.DeviceSessionWhere.Device.EQ(id)}
+	if params.From != nil {
+		filters = append(filters, models.DeviceSessionWhere.ConnectedAt.GTE(*params.From))
+	}
+
+	if params.To != nil {
+		filters = append(filters, models.DeviceSessionWhere.ConnectedAt.LT(*params.To))
+	}
+
+	limit, offset, err := pagination(params.Limit, params.Offset)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	total, err := models.DeviceSessions(filters...).CountG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to count device sessions")
+		writeError(w, http.StatusInternalServerError, "failed to fetch device sessions")
+		return
+	}
+
+	page := []qm.QueryMod{
+		qm.OrderBy(models.DeviceSessionColumns.ID + " DESC"),
+		qm.Limit(limit),
+		qm.Offset(offset),
+	}
+
+	sessions, err := models.DeviceSessions(append(filters, page...)...).AllG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch device sessions")
+		writeError(w, http.StatusInternalServerError, "failed to fetch device sessions")
+		return
+	}
+
+	if sessions == nil {
+		sessions = models.DeviceSessionSlice{}
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	writeJSON(w, http.StatusOK, sessions)
+}
+
+// GetDeviceUptime computes the share of the time the device was connected for from its sessions, over the last day
+// unless from and to are given. The device is flapping once it connected DEVICE_FLAP_THRESHOLD times in the
+// DEVICE_FLAP_WINDOW before to, a zero threshold disabling it
+func (s *server) GetDeviceUptime(w http.ResponseWriter, r *http.Request, id string, params GetDeviceUptimeParams) {
+	now := time.Now()
+
+	to := now
+	if params.To != nil {
+		to = *params.To
+	}
+
+	from := to.Add(-24 * time.Hour)
+	if params.From != nil {
+		from = *params.From
+	}
+
+	if !from.Before(to) {
+		writeError(w, http.StatusBadRequest, "from must be before to")
+		return
+	}
+
+	_, err := models.FindDeviceG(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "device not found")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch device")
+		writeError(w, http.StatusInternalServerError, "failed to fetch device")
+		return
+	}
+
+	sessions, err := models.DeviceSessions(
+		models.DeviceSessionWhere.Device.EQ(id),
+		models.DeviceSessionWhere.ConnectedAt.LT(to),
+		qm.Expr(
+			models.DeviceSessionWhere.DisconnectedAt.IsNull(),
+			qm.Or2(models.DeviceSessionWhere.DisconnectedAt.GT(null.TimeFrom(from))),
+		),
+	).AllG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch device sessions")
+		writeError(w, http.StatusInternalServerError, "failed to fetch device uptime")
+		return
+	}
+
+	var connected time.Duration
+	for _, session := range sessions {
+		// the sessions still open last until now
+		start, end := session.ConnectedAt, now
+		if session.DisconnectedAt.Valid {
+			end = session.DisconnectedAt.Time
+		}
+
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			connected += end.Sub(start)
+		}
+	}
+
+	uptime := DeviceUptime{
+		Device:   id,
+		From:     from,
+		To:       to,
+		Uptime:   100 * float64(connected) / float64(to.Sub(from)),
+		Sessions: len(sessions),
+	}
+
+	if threshold := config.Int("DEVICE_FLAP_THRESHOLD", 5); threshold > 0 {
+		connections, err := models.DeviceSessions(
+			models.DeviceSessionWhere.Device.EQ(id),
+			models.DeviceSessionWhere.ConnectedAt.GTE(to.Add(-config.Duration("DEVICE_FLAP_WINDOW", time.Hour))),
+			models.DeviceSessionWhere.ConnectedAt.LT(to),
+		).CountG(r.Context())
+		if err != nil {
+			log.Error().Err(err).Msg("failed to count device sessions")
+			writeError(w, http.StatusInternalServerError, "failed to fetch device uptime")
+			return
+		}
+
+		uptime.Flapping = connections >= int64(threshold)
+	}
+
+	writeJSON(w, http.StatusOK, uptime)
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"github.com/xuri/excelize/v2"
+
+	"letovo-computers-server/models"
+	"letovo-computers-server/types"
+)
+
+// exportBatch is how many events are fetched at once, so that exports of a whole term are streamed instead of
+// being loaded into memory
+const exportBatch = 1000
+
+var exportHeader = []string{"received_at", "device", "rfid", "login", "name", "class", "slot", "laptop", "status", "result"}
+
+// eachEvent calls fn with the events matching the filters, oldest first, in batches resuming after the last event ID,
+// along with the user holding the tag, nil when unknown
+func eachEvent(ctx context.Context, filters []qm.QueryMod, fn func(event *models.Event, user *models.User) error) error {
+	users := make(map[string]*models.User)
+
+	var lastID int64
+	for {
+		mods := append(filters[:len(filters):len(filters)], models.EventWhere.ID.GT(lastID), qm.OrderBy(models.EventColumns.ID), qm.Limit(exportBatch))
+
+		events, err := models.Events(mods...).AllG(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch events: %w", err)
+		}
+
+		for _, event := range events {
+			user, ok := users[event.Rfid]
+			if !ok {
+				// the departed users are still named in the reports
+				user, _ = models.Users(models.UserWhere.ID.EQ(event.Rfid), qm.WithDeleted()).OneG(ctx)
+				users[event.Rfid] = user
+			}
+
+			if err := fn(event, user); err != nil {
+				return err
+			}
+		}
+
+		if len(events) < exportBatch {
+			return nil
+		}
+		lastID = events[len(events)-1].ID
+	}
+}
+
+func exportRow(event *models.Event, user *models.User) []string {
+	if user == nil {
+		user = new(models.User)
+	}
+
+	return []string{
+		event.ReceivedAt.Format(time.RFC3339),
+		event.Device,
+		event.Rfid,
+		user.Login,
+		user.Name,
+		user.Class,
+		event.Slot,
+		event.Laptop,
+		types.Status(event.Status).String(),
+		event.Result,
+	}
+}
+
+func (s *server) ExportEventsCSV(w http.ResponseWriter, r *http.Request, params ExportEventsCSVParams) {
+	filters := s.eventFilters(params.From, params.To, params.Rfid, params.Slot, params.Laptop)
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="events.csv"`)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportHeader); err != nil {
+		return
+	}
+
+	err := eachEvent(r.Context(), filters, func(event *models.Event, user *models.User) error {
+		return writer.Write(exportRow(event, user))
+	})
+	writer.Flush()
+
+	// the status has been sent along with the first rows, so the export can only be cut short
+	if err == nil {
+		err = writer.Error()
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to export events")
+	}
+}
+
+func (s *server) ExportEventsXLSX(w http.ResponseWriter, r *http.Request, params ExportEventsXLSXParams) {
+	filters := s.eventFilters(params.From, params.To, params.Rfid, params.Slot, params.Laptop)
+
+	file := excelize.NewFile()
+	defer file.Close()
+
+	const sheet = "Sheet1"
+
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create xlsx stream")
+		writeError(w, http.StatusInternalServerError, "failed to export events")
+		return
+	}
+
+	row := 1
+	writeRow := func(values []string) error {
+		cells := make([]interface{}, len(values))
+		for i, v := range values {
+			cells[i] = v
+		}
+
+		cell, err := excelize.CoordinatesToCellName(1, row)
+		if err != nil {
+			return err
+		}
+		row++
+
+		return stream.SetRow(cell, cells)
+	}
+
+	err = writeRow(exportHeader)
+	if err == nil {
+		err = eachEvent(r.Context(), filters, func(event *models.Event, user *models.User) error {
+			return writeRow(exportRow(event, user))
+		})
+	}
+	if err == nil {
+		err = stream.Flush()
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to export events")
+		writeError(w, http.StatusInternalServerError, "failed to export events")
+		return
+	}
+
+	// the stream writer spills the rows to a temporary file, the workbook is only assembled once complete
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", `attachment; filename="events.xlsx"`)
+
+	if _, err := file.WriteTo(w); err != nil {
+		log.Error().Err(err).Msg("failed to write xlsx")
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/config"
+	"letovo-computers-server/firmware"
+	"letovo-computers-server/models"
+)
+
+// firmwareResponse leaves the binary out, it is only ever streamed to the devices
+type firmwareResponse struct {
+	Version   string    `json:"version"`
+	Size      int       `json:"size"`
+	Sha256    string    `json:"sha256"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newFirmwareResponse(release *models.FirmwareRelease) firmwareResponse {
+	return firmwareResponse{
+		Version:   release.Version,
+		Size:      release.Size,
+		Sha256:    release.Sha256,
+		CreatedAt: release.CreatedAt,
+	}
+}
+
+func (s *server) ListFirmware(w http.ResponseWriter, r *http.Request) {
+	releases, err := firmware.List(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch firmware releases")
+		writeError(w, http.StatusInternalServerError, "failed to fetch firmware releases")
+		return
+	}
+
+	response := make([]firmwareResponse, 0, len(releases))
+	for _, release := range releases {
+		response = append(response, newFirmwareResponse(release))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// UploadFirmware stores the binary of at most FIRMWARE_MAX_SIZE bytes as the release of the version
+// and announces it to the devices
+func (s *server) UploadFirmware(w http.ResponseWriter, r *http.Request, params UploadFirmwareParams) {
+	version := strings.TrimSpace(params.Version)
+	if version == "" {
+		writeError(w, http.StatusBadRequest, "version must not be empty")
+		return
+	}
+
+	maxSize := int64(config.Int("FIRMWARE_MAX_SIZE", 4<<20))
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, "firmware is larger than FIRMWARE_MAX_SIZE")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read firmware")
+		return
+	}
+
+	if len(data) == 0 {
+		writeError(w, http.StatusBadRequest, "firmware must not be empty")
+		return
+	}
+
+	release, err := firmware.Upload(r.Context(), version, data)
+	if errors.Is(err, firmware.ErrExists) {
+		writeError(w, http.StatusConflict, "firmware "+version+" already exists")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to store firmware")
+		writeError(w, http.StatusInternalServerError, "failed to store firmware")
+		return
+	}
+
+	log.Info().Str("version", version).Int("size", release.Size).Msgf("uploaded firmware %s", version)
+
+	// the release is stored either way, and devices requesting the latest release still get it
+	if err := firmware.Announce(r.Context(), s.opts.Client, release); err != nil {
+		log.Error().Err(err).Str("version", version).Msg("failed to announce firmware")
+	}
+
+	writeJSON(w, http.StatusCreated, newFirmwareResponse(release))
+}
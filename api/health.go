@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/config"
+	"letovo-computers-server/health"
+)
+
+const (
+	mqttConnected    = "connected"
+	mqttDisconnected = "disconnected"
+)
+
+type dbReport struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type healthReport struct {
+	health.Status
+	MQTT          string            `json:"mqtt"`
+	DB            *dbReport         `json:"db,omitempty"`
+	Subscriptions map[string]string `json:"subscriptions,omitempty"`
+}
+
+func (s *server) mqttStatus() string {
+	if s.opts.Client.IsConnectionOpen() {
+		return mqttConnected
+	}
+
+	return mqttDisconnected
+}
+
+// GetHealthz reports whether the server is alive: it is connected to the broker and persists most of the messages.
+// The broker link is checked, since the client reconnects forever and would otherwise never be restarted
+func (s *server) GetHealthz(w http.ResponseWriter, r *http.Request) {
+	report := healthReport{Status: s.opts.Health.Status(), MQTT: s.mqttStatus()}
+
+	if report.MQTT != mqttConnected {
+		report.Status.Status = health.StatusDegraded
+	}
+
+	if report.Status.Status != health.StatusOK {
+		writeJSON(w, http.StatusServiceUnavailable, report)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// GetReadyz reports whether the server can process messages: it is connected to the broker, the db answers pings
+// and every subscription has been acknowledged
+func (s *server) GetReadyz(w http.ResponseWriter, r *http.Request) {
+	report := healthReport{
+		Status:        s.opts.Health.Status(),
+		MQTT:          s.mqttStatus(),
+		DB:            s.pingDB(r.Context()),
+		Subscriptions: broker.Subscriptions(),
+	}
+
+	ready := report.MQTT == mqttConnected && report.DB.Status == health.StatusOK
+	for _, state := range report.Subscriptions {
+		if state != broker.SubscriptionActive {
+			ready = false
+		}
+	}
+
+	if !ready {
+		report.Status.Status = health.StatusDegraded
+		writeJSON(w, http.StatusServiceUnavailable, report)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+func (s *server) pingDB(ctx context.Context) *dbReport {
+	ctx, cancel := context.WithTimeout(ctx, config.Duration("HEALTH_DB_TIMEOUT", 2*time.Second))
+	defer cancel()
+
+	start := time.Now()
+	err := s.opts.DB.PingContext(ctx)
+	report := &dbReport{
+		Status:    health.StatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		report.Status = health.StatusDegraded
+		report.Error = err.Error()
+	}
+
+	return report
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/models"
+)
+
+// ListEvents serves the SSE stream to EventSource clients and the event history to everyone else
+func (s *server) ListEvents(w http.ResponseWriter, r *http.Request, params ListEventsParams) {
+	if acceptsEventStream(r) {
+		s.sseSlots(w, r)
+		return
+	}
+
+	s.eventHistory(w, r, params)
+}
+
+// eventFilters translates the event filters shared by the history and the exports into query mods
+func (s *server) eventFilters(from *From, to *To, rfid *EventRFID, slot *EventSlot, laptop *EventLaptop) []qm.QueryMod {
+	var filters []qm.QueryMod
+	if from != nil {
+		filters = append(filters, models.EventWhere.ReceivedAt.GTE(*from))
+	}
+
+	if to != nil {
+		filters = append(filters, models.EventWhere.ReceivedAt.LT(*to))
+	}
+
+	if rfid != nil && *rfid != "" {
+		filters = append(filters, models.EventWhere.Rfid.EQ(*rfid))
+	}
+
+	if slot != nil && *slot != "" {
+		filters = append(filters, models.EventWhere.Slot.EQ(s.opts.Aliases.ID(*slot)))
+	}
+
+	if laptop != nil && *laptop != "" {
+		filters = append(filters, models.EventWhere.Laptop.EQ(*laptop))
+	}
+
+	return filters
+}
+
+// historyFilters translates the history parameters into query mods, returning the filters and the pagination apart
+func (s *server) historyFilters(params ListEventsParams) (filters, page []qm.QueryMod, err error) {
+	filters = s.eventFilters(params.From, params.To, params.Rfid, params.Slot, params.Laptop)
+
+	limit, offset, err := pagination(params.Limit, params.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page = []qm.QueryMod{
+		qm.OrderBy(models.EventColumns.ReceivedAt + " DESC, " + models.EventColumns.ID + " DESC"),
+		qm.Limit(limit),
+		qm.Offset(offset),
+	}
+
+	return filters, page, nil
+}
+
+// eventHistory lists the logged events within [from, to), newest first
+func (s *server) eventHistory(w http.ResponseWriter, r *http.Request, params ListEventsParams) {
+	filters, page, err := s.historyFilters(params)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	total, err := models.Events(filters...).CountG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to count events")
+		writeError(w, http.StatusInternalServerError, "failed to fetch events")
+		return
+	}
+
+	events, err := models.Events(append(filters, page...)...).AllG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch events")
+		writeError(w, http.StatusInternalServerError, "failed to fetch events")
+		return
+	}
+
+	if events == nil {
+		events = models.EventSlice{}
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	writeJSON(w, http.StatusOK, events)
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/models"
+	"letovo-computers-server/types"
+)
+
+func (s *server) ListLaptops(w http.ResponseWriter, r *http.Request, params ListLaptopsParams) {
+	var filters []qm.QueryMod
+	if params.Status != nil {
+		filters = append(filters, models.LaptopWhere.Status.EQ(string(*params.Status)))
+	}
+
+	if params.HomeSlot != nil && *params.HomeSlot != "" {
+		filters = append(filters, models.LaptopWhere.HomeSlot.EQ(null.StringFrom(s.opts.Aliases.ID(*params.HomeSlot))))
+	}
+
+	limit, offset, err := pagination(params.Limit, params.Offset)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	total, err := models.Laptops(filters...).CountG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to count laptops")
+		writeError(w, http.StatusInternalServerError, "failed to fetch laptops")
+		return
+	}
+
+	page := []qm.QueryMod{qm.OrderBy(models.LaptopColumns.AssetTag), qm.Limit(limit), qm.Offset(offset)}
+
+	laptops, err := models.Laptops(append(filters, page...)...).AllG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch laptops")
+		writeError(w, http.StatusInternalServerError, "failed to fetch laptops")
+		return
+	}
+
+	if laptops == nil {
+		laptops = models.LaptopSlice{}
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	writeJSON(w, http.StatusOK, laptops)
+}
+
+func (s *server) GetLaptop(w http.ResponseWriter, r *http.Request, tag string) {
+	laptop, err := models.FindLaptopG(r.Context(), tag)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "laptop not found")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch laptop")
+		writeError(w, http.StatusInternalServerError, "failed to fetch laptop")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, laptop)
+}
+
+// PutLaptop registers the laptop or replaces its record, a slot being the home of at most one laptop
+func (s *server) PutLaptop(w http.ResponseWriter, r *http.Request, tag string) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		writeError(w, http.StatusBadRequest, "asset tag must not be empty")
+		return
+	}
+
+	request := new(LaptopRequest)
+
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "malformed request body")
+		return
+	}
+
+	laptop := models.Laptop{AssetTag: tag, Status: types.LaptopAvailable}
+	if request.Serial != nil {
+		laptop.Serial = strings.TrimSpace(*request.Serial)
+	}
+	if request.Model != nil {
+		laptop.Model = strings.TrimSpace(*request.Model)
+	}
+	if request.Status != nil {
+		if err := types.ValidateLaptopStatus(string(*request.Status)); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		laptop.Status = string(*request.Status)
+	}
+
+	if request.HomeSlot != nil && *request.HomeSlot != "" {
+		slotID := s.opts.Aliases.ID(*request.HomeSlot)
+
+		exists, err := models.SlotExistsG(r.Context(), slotID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to fetch slot")
+			writeError(w, http.StatusInternalServerError, "failed to upsert laptop")
+			return
+		}
+		if !exists {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("slot %s not found", slotID))
+			return
+		}
+
+		homed, err := models.Laptops(
+			models.LaptopWhere.HomeSlot.EQ(null.StringFrom(slotID)),
+			models.LaptopWhere.AssetTag.NEQ(tag),
+		).OneG(r.Context())
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Error().Err(err).Msg("failed to fetch laptop")
+			writeError(w, http.StatusInternalServerError, "failed to upsert laptop")
+			return
+		}
+		if homed != nil {
+			writeError(w, http.StatusConflict, fmt.Sprintf("slot %s is the home of laptop %s", slotID, homed.AssetTag))
+			return
+		}
+
+		laptop.HomeSlot = null.StringFrom(slotID)
+	}
+
+	existing, err := models.FindLaptopG(r.Context(), tag)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Error().Err(err).Msg("failed to fetch laptop")
+		writeError(w, http.StatusInternalServerError, "failed to upsert laptop")
+		return
+	}
+	if existing != nil {
+		auditBefore(r, existing)
+	}
+
+	// the record is replaced as a whole
+	columns := []string{
+		models.LaptopColumns.Serial, models.LaptopColumns.Model, models.LaptopColumns.HomeSlot,
+		models.LaptopColumns.Status, models.LaptopColumns.UpdatedAt,
+	}
+
+	err = laptop.UpsertG(r.Context(), true, []string{models.LaptopColumns.AssetTag},
+		boil.Whitelist(columns...), boil.Whitelist(append(columns, models.LaptopColumns.AssetTag, models.LaptopColumns.CreatedAt)...),
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to upsert laptop")
+		writeError(w, http.StatusInternalServerError, "failed to upsert laptop")
+		return
+	}
+
+	log.Info().Str("laptop", tag).Str("slot", laptop.HomeSlot.String).Msgf("registered laptop %s", tag)
+
+	// an existing laptop keeps the time it was created at, which the upsert does not return
+	if err := laptop.ReloadG(r.Context()); err != nil {
+		log.Error().Err(err).Msg("failed to fetch laptop")
+		writeError(w, http.StatusInternalServerError, "failed to fetch laptop")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, laptop)
+}
+
+// DeleteLaptop removes the laptop from the inventory, while its events stay logged
+func (s *server) DeleteLaptop(w http.ResponseWriter, r *http.Request, tag string) {
+	laptop, err := models.FindLaptopG(r.Context(), tag)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "laptop not found")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch laptop")
+		writeError(w, http.StatusInternalServerError, "failed to fetch laptop")
+		return
+	}
+
+	auditBefore(r, laptop)
+
+	_, err = laptop.DeleteG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to delete laptop")
+		writeError(w, http.StatusInternalServerError, "failed to delete laptop")
+		return
+	}
+
+	log.Info().Str("laptop", tag).Msgf("deleted laptop %s", tag)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"context"
+	"net"
+)
+
+// Listen announces the HTTP listener on addr. With reusePort the port is bound with SO_REUSEPORT, so a new process
+// can take over serving while the old one drains its connections during a restart
+func Listen(ctx context.Context, addr string, reusePort bool) (net.Listener, error) {
+	lc := net.ListenConfig{}
+	if reusePort {
+		lc.Control = reusePortControl
+	}
+
+	return lc.Listen(ctx, "tcp", addr)
+}
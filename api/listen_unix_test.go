@@ -0,0 +1,45 @@
+//go:build unix
+
+package api
+
+import (
+	"context"
+	"testing"
+)
+
+func TestListenReusePort(t *testing.T) {
+	ctx := context.Background()
+
+	first, err := Listen(ctx, "127.0.0.1:0", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+
+	// the process taking over binds the port the old one still serves
+	second, err := Listen(ctx, first.Addr().String(), true)
+	if err != nil {
+		t.Fatalf("failed to bind %s a second time: %v", first.Addr(), err)
+	}
+	defer second.Close()
+
+	if first.Addr().String() != second.Addr().String() {
+		t.Errorf("listening on %s and %s, want the same port", first.Addr(), second.Addr())
+	}
+}
+
+func TestListenWithoutReusePort(t *testing.T) {
+	ctx := context.Background()
+
+	first, err := Listen(ctx, "127.0.0.1:0", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+
+	second, err := Listen(ctx, first.Addr().String(), false)
+	if err == nil {
+		second.Close()
+		t.Fatalf("bound %s a second time without SO_REUSEPORT", first.Addr())
+	}
+}
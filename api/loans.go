@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/models"
+)
+
+// loanFilters translates the loan listing parameters into query mods
+func (s *server) loanFilters(params ListLoansParams) []qm.QueryMod {
+	var filters []qm.QueryMod
+	if params.From != nil {
+		filters = append(filters, models.LoanWhere.TakenAt.GTE(*params.From))
+	}
+
+	if params.To != nil {
+		filters = append(filters, models.LoanWhere.TakenAt.LT(*params.To))
+	}
+
+	if params.Rfid != nil && *params.Rfid != "" {
+		filters = append(filters, models.LoanWhere.Rfid.EQ(*params.Rfid))
+	}
+
+	if params.Slot != nil && *params.Slot != "" {
+		filters = append(filters, models.LoanWhere.Slot.EQ(s.opts.Aliases.ID(*params.Slot)))
+	}
+
+	if params.Laptop != nil && *params.Laptop != "" {
+		filters = append(filters, models.LoanWhere.Laptop.EQ(*params.Laptop))
+	}
+
+	if params.Open != nil {
+		if *params.Open {
+			filters = append(filters, models.LoanWhere.ReturnedAt.IsNull())
+		} else {
+			filters = append(filters, models.LoanWhere.ReturnedAt.IsNotNull())
+		}
+	}
+
+	if params.Overdue != nil {
+		overdue := qm.Where(models.LoanColumns.ReturnedAt+" IS NULL AND "+models.LoanColumns.DueAt+" < ?", time.Now())
+		if !*params.Overdue {
+			overdue = qm.Where("NOT ("+models.LoanColumns.ReturnedAt+" IS NULL AND "+models.LoanColumns.DueAt+" < ?)", time.Now())
+		}
+		filters = append(filters, overdue)
+	}
+
+	return filters
+}
+
+func (s *server) ListLoans(w http.ResponseWriter, r *http.Request, params ListLoansParams) {
+	filters := s.loanFilters(params)
+
+	limit, offset, err := pagination(params.Limit, params.Offset)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	total, err := models.Loans(filters...).CountG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to count loans")
+		writeError(w, http.StatusInternalServerError, "failed to fetch loans")
+		return
+	}
+
+	page := []qm.QueryMod{
+		qm.OrderBy(models.LoanColumns.TakenAt + " DESC, " + models.LoanColumns.ID + " DESC"),
+		qm.Limit(limit),
+		qm.Offset(offset),
+	}
+
+	loans, err := models.Loans(append(filters, page...)...).AllG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch loans")
+		writeError(w, http.StatusInternalServerError, "failed to fetch loans")
+		return
+	}
+
+	if loans == nil {
+		loans = models.LoanSlice{}
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	writeJSON(w, http.StatusOK, loans)
+}
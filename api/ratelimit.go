@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"letovo-computers-server/auth"
+	"letovo-computers-server/config"
+	"letovo-computers-server/metrics"
+)
+
+// limiterIdle is how long a client's limiter is kept after its last request
+const limiterIdle = 10 * time.Minute
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiter keeps a token bucket per client, refilled at API_RATE_LIMIT requests per second up to API_RATE_BURST
+type rateLimiter struct {
+	mu        sync.Mutex
+	clients   map[string]*clientLimiter
+	lastSwept time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{clients: make(map[string]*clientLimiter), lastSwept: time.Now()}
+}
+
+func (l *rateLimiter) allow(key string, limit rate.Limit, burst int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSwept) > limiterIdle {
+		for k, c := range l.clients {
+			if now.Sub(c.lastSeen) > limiterIdle {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSwept = now
+	}
+
+	c, ok := l.clients[key]
+	if !ok {
+		c = &clientLimiter{limiter: rate.NewLimiter(limit, burst)}
+		l.clients[key] = c
+	}
+
+	// pick up reloaded settings
+	if c.limiter.Limit() != limit || c.limiter.Burst() != burst {
+		c.limiter.SetLimit(limit)
+		c.limiter.SetBurst(burst)
+	}
+
+	c.lastSeen = now
+
+	return c.limiter.Allow()
+}
+
+// forget drops the bucket of the key
+func (l *rateLimiter) forget(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.clients, key)
+}
+
+// rateLimit rejects the requests of clients exceeding their rate with 429. Clients are told apart by a digest of
+// their bearer token, so that tokens are not kept around in memory, or by their address without one. The bucket of
+// the token is drawn from before the token is authenticated, so that the clients over their rate do not get to
+// hit the db. A token failing to authenticate loses its bucket and draws from the bucket of the address instead,
+// so that made-up tokens neither get a bucket of their own nor pile up. The identity of the token is passed on to
+// authorize, sparing it authenticating the token again. A zero API_RATE_LIMIT disables limiting
+func rateLimit(limiter *rateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := config.Float("API_RATE_LIMIT", 10)
+			if limit <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			burst := config.Int("API_RATE_BURST", 20)
+
+			reject := func(kind string) {
+				metrics.APIRateLimited.WithLabelValues(kind).Inc()
+
+				w.Header().Set("Retry-After", strconv.Itoa(int(1/limit)+1))
+				writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			}
+
+			if token := bearerToken(r); token != "" {
+				sum := sha256.Sum256([]byte(token))
+				key := "token:" + hex.EncodeToString(sum[:])
+
+				if !limiter.allow(key, rate.Limit(limit), burst) {
+					reject("token")
+					return
+				}
+
+				identity, err := auth.Authenticate(r.Context(), token)
+				if err == nil {
+					next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authenticatedKey{}, identity)))
+					return
+				}
+
+				limiter.forget(key)
+			}
+
+			if !limiter.allow("ip:"+clientIP(r), rate.Limit(limit), burst) {
+				reject("ip")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the address of the client. X-Forwarded-For is only trusted when API_TRUST_PROXY is set
+func clientIP(r *http.Request) string {
+	if config.Bool("API_TRUST_PROXY", false) {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			ip, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(ip)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return host
+}
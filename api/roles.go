@@ -0,0 +1,94 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/auth"
+	"letovo-computers-server/models"
+)
+
+func (s *server) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := models.Roles(qm.OrderBy(models.RoleColumns.Subject)).AllG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch roles")
+		writeError(w, http.StatusInternalServerError, "failed to fetch roles")
+		return
+	}
+
+	if roles == nil {
+		roles = models.RoleSlice{}
+	}
+
+	writeJSON(w, http.StatusOK, roles)
+}
+
+func (s *server) PutRole(w http.ResponseWriter, r *http.Request, subject Subject) {
+	request := new(RoleRequest)
+
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "malformed request body")
+		return
+	}
+
+	if !auth.ValidRole(string(request.Role)) {
+		writeError(w, http.StatusBadRequest, "unknown role")
+		return
+	}
+
+	existing, err := models.FindRoleG(r.Context(), subject)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Error().Err(err).Msg("failed to fetch role")
+		writeError(w, http.StatusInternalServerError, "failed to upsert role")
+		return
+	}
+	if existing != nil {
+		auditBefore(r, existing)
+	}
+
+	role := models.Role{Subject: subject, Role: string(request.Role)}
+
+	err = role.UpsertG(r.Context(), true, []string{"subject"}, boil.Whitelist("role"), boil.Infer())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to upsert role")
+		writeError(w, http.StatusInternalServerError, "failed to upsert role")
+		return
+	}
+
+	log.Info().Str("subject", role.Subject).Str("role", role.Role).Msgf("assigned %s to %s", role.Role, role.Subject)
+
+	writeJSON(w, http.StatusOK, role)
+}
+
+func (s *server) DeleteRole(w http.ResponseWriter, r *http.Request, subject Subject) {
+	role, err := models.FindRoleG(r.Context(), subject)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "role not found")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch role")
+		writeError(w, http.StatusInternalServerError, "failed to fetch role")
+		return
+	}
+
+	auditBefore(r, role)
+
+	_, err = role.DeleteG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to delete role")
+		writeError(w, http.StatusInternalServerError, "failed to delete role")
+		return
+	}
+
+	log.Info().Str("subject", subject).Msgf("revoked the role of %s", subject)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,278 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/models"
+	"letovo-computers-server/state"
+	"letovo-computers-server/storage"
+)
+
+type slotResponse struct {
+	*models.Slot
+	Alias string `json:"alias,omitempty"`
+}
+
+func (s *server) slotResponse(slot *models.Slot) slotResponse {
+	return slotResponse{Slot: slot, Alias: s.opts.Aliases.Alias(slot.ID)}
+}
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// pagination validates the limit and offset parameters, defaulting them when omitted
+func pagination(limitParam *Limit, offsetParam *Offset) (limit, offset int, err error) {
+	limit = defaultLimit
+	if limitParam != nil {
+		limit = *limitParam
+		if limit < 1 || limit > maxLimit {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+		}
+	}
+
+	if offsetParam != nil {
+		offset = *offsetParam
+		if offset < 0 {
+			return 0, 0, errors.New("offset must not be negative")
+		}
+	}
+
+	return limit, offset, nil
+}
+
+// slotFilters translates the listing parameters into query mods, returning the filters and the pagination apart,
+// so that the filters alone can be used to count the matching slots
+func slotFilters(params ListSlotsParams) (filters, page []qm.QueryMod, err error) {
+	if params.Taken != nil {
+		filters = append(filters, models.SlotWhere.IsTaken.EQ(*params.Taken))
+	}
+
+	if params.TakenBy != nil && *params.TakenBy != "" {
+		filters = append(filters, models.SlotWhere.TakenBy.EQ(*params.TakenBy))
+	}
+
+	if params.Cabinet != nil && *params.Cabinet != "" {
+		filters = append(filters, state.InCabinet(*params.Cabinet))
+	}
+
+	if params.IncludeDeleted != nil && *params.IncludeDeleted {
+		filters = append(filters, qm.WithDeleted())
+	}
+
+	limit, offset, err := pagination(params.Limit, params.Offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page = []qm.QueryMod{qm.OrderBy(models.SlotColumns.ID), qm.Limit(limit), qm.Offset(offset)}
+
+	return filters, page, nil
+}
+
+func (s *server) ListSlots(w http.ResponseWriter, r *http.Request, params ListSlotsParams) {
+	filters, page, err := slotFilters(params)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	total, err := models.Slots(filters...).CountG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to count slots")
+		writeError(w, http.StatusInternalServerError, "failed to fetch slots")
+		return
+	}
+
+	slots, err := models.Slots(append(filters, page...)...).AllG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch slots")
+		writeError(w, http.StatusInternalServerError, "failed to fetch slots")
+		return
+	}
+
+	response := make([]slotResponse, 0, len(slots))
+	for _, slot := range slots {
+		response = append(response, s.slotResponse(slot))
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *server) GetSlot(w http.ResponseWriter, r *http.Request, id SlotID) {
+	slot, err := models.FindSlotG(r.Context(), s.opts.Aliases.ID(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "slot not found")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch slot")
+		writeError(w, http.StatusInternalServerError, "failed to fetch slot")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.slotResponse(slot))
+}
+
+// UpdateSlot changes the metadata of the slot, refreshing the snapshot of its cabinet
+func (s *server) UpdateSlot(w http.ResponseWriter, r *http.Request, id SlotID) {
+	request := new(SlotMetadata)
+
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "malformed request body")
+		return
+	}
+
+	if (request.Row != nil && *request.Row < 0) || (request.Column != nil && *request.Column < 0) {
+		writeError(w, http.StatusBadRequest, "row and column must not be negative")
+		return
+	}
+
+	slot, err := models.FindSlotG(r.Context(), s.opts.Aliases.ID(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "slot not found")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch slot")
+		writeError(w, http.StatusInternalServerError, "failed to fetch slot")
+		return
+	}
+
+	auditBefore(r, s.slotResponse(slot))
+
+	var columns []string
+	if request.CabinetId != nil {
+		slot.CabinetID = strings.TrimSpace(*request.CabinetId)
+		columns = append(columns, models.SlotColumns.CabinetID)
+	}
+	if request.Row != nil {
+		slot.Row = *request.Row
+		columns = append(columns, models.SlotColumns.Row)
+	}
+	if request.Column != nil {
+		slot.Column = *request.Column
+		columns = append(columns, models.SlotColumns.Column)
+	}
+	if request.Label != nil {
+		slot.Label = strings.TrimSpace(*request.Label)
+		columns = append(columns, models.SlotColumns.Label)
+	}
+	if request.Notes != nil {
+		slot.Notes = strings.TrimSpace(*request.Notes)
+		columns = append(columns, models.SlotColumns.Notes)
+	}
+
+	if len(columns) > 0 {
+		err := storage.NewRepos(s.opts.DB).Slots.Update(r.Context(), slot, columns...)
+		if errors.Is(err, storage.ErrStaleSlot) {
+			writeError(w, http.StatusConflict, "slot changed while updating, try again")
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("failed to update slot")
+			writeError(w, http.StatusInternalServerError, "failed to update slot")
+			return
+		}
+
+		log.Info().Str("slot", slot.ID).Strs("columns", columns).Msgf("updated %s", slot.ID)
+
+		if err := state.PublishCabinets(r.Context(), s.opts.Client, []string{slot.ID}); err != nil {
+			log.Error().Err(err).Msg("failed to publish cabinet snapshot")
+		}
+	}
+
+	writeJSON(w, http.StatusOK, s.slotResponse(slot))
+}
+
+func (s *server) ReleaseSlot(w http.ResponseWriter, r *http.Request, id SlotID) {
+	slotID := s.opts.Aliases.ID(id)
+
+	// the slot as it is before the release, unless it changes in the meantime
+	if before, err := models.FindSlotG(r.Context(), slotID); err == nil {
+		auditBefore(r, s.slotResponse(before))
+	}
+
+	err := state.ReleaseSlot(r.Context(), slotID)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "slot not found")
+		return
+	}
+	if errors.Is(err, storage.ErrStaleSlot) {
+		writeError(w, http.StatusConflict, "slot changed while releasing, try again")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to release slot")
+		writeError(w, http.StatusInternalServerError, "failed to release slot")
+		return
+	}
+
+	log.Info().Str("slot", slotID).Msgf("force-released %s", slotID)
+
+	state.AnnounceRelease(r.Context(), s.opts.Client, slotID)
+
+	slot, err := models.FindSlotG(r.Context(), slotID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch slot")
+		writeError(w, http.StatusInternalServerError, "failed to fetch slot")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.slotResponse(slot))
+}
+
+// DeleteSlot decommissions the slot, leaving it out of the listings and the snapshots while its events and loans
+// are kept. A taken slot is to be released first
+func (s *server) DeleteSlot(w http.ResponseWriter, r *http.Request, id SlotID) {
+	slot, err := models.FindSlotG(r.Context(), s.opts.Aliases.ID(id))
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "slot not found")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch slot")
+		writeError(w, http.StatusInternalServerError, "failed to fetch slot")
+		return
+	}
+
+	if slot.IsTaken {
+		writeError(w, http.StatusConflict, "slot is taken, release it first")
+		return
+	}
+
+	auditBefore(r, s.slotResponse(slot))
+
+	slot.DeletedAt = null.TimeFrom(time.Now())
+
+	err = storage.NewRepos(s.opts.DB).Slots.Update(r.Context(), slot, models.SlotColumns.DeletedAt)
+	if errors.Is(err, storage.ErrStaleSlot) {
+		writeError(w, http.StatusConflict, "slot changed while deleting, try again")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to delete slot")
+		writeError(w, http.StatusInternalServerError, "failed to delete slot")
+		return
+	}
+
+	log.Info().Str("slot", slot.ID).Msgf("decommissioned %s", slot.ID)
+
+	state.AnnounceDeletion(r.Context(), s.opts.Client, slot.ID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/config"
+	"letovo-computers-server/models"
+	"letovo-computers-server/types"
+)
+
+// sseBuffer is how many slot updates a slow SSE client may fall behind before being dropped
+const sseBuffer = 64
+
+// acceptsEventStream reports whether the client asks for the SSE stream rather than for the event history
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// sseSlots replays the current slot assignments as a snapshot event, then streams the slot updates as they are persisted.
+// Clients that fall behind are disconnected, so that their EventSource reconnects and starts over from a fresh snapshot
+func (s *server) sseSlots(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+
+	// subscribe before reading the snapshot, so that no update falls in between
+	events, cancel := s.opts.Hub.Subscribe(sseBuffer)
+	defer cancel()
+
+	slots, err := models.Slots(qm.OrderBy(models.SlotColumns.ID)).AllG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch slots")
+		writeError(w, http.StatusInternalServerError, "failed to fetch slots")
+		return
+	}
+
+	snapshot := make([]slotResponse, 0, len(slots))
+	for _, slot := range slots {
+		snapshot = append(snapshot, s.slotResponse(slot))
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeSSE(w, "snapshot", snapshot); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	keepalive := time.NewTicker(config.Duration("SSE_KEEPALIVE_INTERVAL", 15*time.Second))
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			if event.Slot == "" || !applied(event) {
+				continue
+			}
+
+			slot, err := models.FindSlotG(r.Context(), event.Slot)
+			if err != nil {
+				log.Error().Err(err).Msg("failed to fetch slot")
+				continue
+			}
+
+			if err := writeSSE(w, "slot", s.slotResponse(slot)); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// applied reports whether the event changed its slot, either processed successfully or changed in the db directly
+func applied(event types.Event) bool {
+	return event.Result == types.ResultOK || event.Result == types.ResultChanged
+}
+
+func writeSSE(w http.ResponseWriter, event string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to marshal sse event")
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
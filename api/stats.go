@@ -0,0 +1,206 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/config"
+	"letovo-computers-server/models"
+	"letovo-computers-server/types"
+)
+
+const (
+	occupancyQuery = `SELECT cabinet_id, count(*) AS slots, count(*) FILTER (WHERE is_taken) AS taken
+FROM slots WHERE deleted_at IS NULL GROUP BY cabinet_id ORDER BY cabinet_id`
+
+	todayQuery = `SELECT count(*) FILTER (WHERE status = $1) AS takes, count(*) FILTER (WHERE status = $2) AS returns
+FROM events WHERE received_at >= $3 AND slot <> '' AND result = $4`
+
+	// the hours are counted in the STATS_TIME_ZONE by computeStats, which sqlite has no notion of
+	activityQuery = `SELECT received_at FROM events
+WHERE received_at >= $1 AND status IN ($2, $3) AND slot <> '' AND result = $4`
+
+	topUsersQuery = `SELECT e.rfid, coalesce(u.login, '') AS login, count(*) AS takes
+FROM events e LEFT JOIN users u ON u.id = e.rfid
+WHERE e.received_at >= $1 AND e.status = $2 AND e.slot <> '' AND e.result = $3
+GROUP BY e.rfid, u.login ORDER BY takes DESC, e.rfid LIMIT $4`
+
+	loansQuery = `SELECT count(*) AS open, count(*) FILTER (WHERE due_at < $1) AS overdue
+FROM loans WHERE returned_at IS NULL`
+)
+
+type occupancyRow struct {
+	CabinetID string `boil:"cabinet_id"`
+	Slots     int    `boil:"slots"`
+	Taken     int    `boil:"taken"`
+}
+
+type todayRow struct {
+	Takes   int `boil:"takes"`
+	Returns int `boil:"returns"`
+}
+
+type loansRow struct {
+	Open    int `boil:"open"`
+	Overdue int `boil:"overdue"`
+}
+
+type activityRow struct {
+	ReceivedAt time.Time `boil:"received_at"`
+}
+
+type topUserRow struct {
+	Rfid  string `boil:"rfid"`
+	Login string `boil:"login"`
+	Takes int    `boil:"takes"`
+}
+
+func (s *server) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := computeStats(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to compute stats")
+		writeError(w, http.StatusInternalServerError, "failed to compute stats")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// computeStats aggregates the occupancy of the cabinets and the activity since the start of the day in the STATS_TIME_ZONE
+func computeStats(ctx context.Context) (*Stats, error) {
+	zone := config.String("STATS_TIME_ZONE", "UTC")
+
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load time zone %s: %w", zone, err)
+	}
+
+	now := time.Now().In(loc)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	taken, placed := int(types.Taken), int(types.Placed)
+
+	var occupancy []occupancyRow
+	if err := queries.Raw(occupancyQuery).BindG(ctx, &occupancy); err != nil {
+		return nil, fmt.Errorf("failed to aggregate occupancy: %w", err)
+	}
+
+	var today todayRow
+	if err := queries.Raw(todayQuery, taken, placed, midnight, types.ResultOK).BindG(ctx, &today); err != nil {
+		return nil, fmt.Errorf("failed to aggregate activity: %w", err)
+	}
+
+	var loans loansRow
+	if err := queries.Raw(loansQuery, now).BindG(ctx, &loans); err != nil {
+		return nil, fmt.Errorf("failed to aggregate loans: %w", err)
+	}
+
+	stats := &Stats{
+		Cabinets:     make([]CabinetOccupancy, 0, len(occupancy)),
+		TakesToday:   today.Takes,
+		ReturnsToday: today.Returns,
+		TopUsers:     []TopUser{},
+		OpenLoans:    loans.Open,
+		OverdueLoans: loans.Overdue,
+	}
+
+	for _, row := range occupancy {
+		stats.Cabinets = append(stats.Cabinets, CabinetOccupancy{CabinetId: row.CabinetID, Slots: row.Slots, Taken: row.Taken})
+	}
+
+	var activity []activityRow
+	err = queries.Raw(activityQuery, midnight, taken, placed, types.ResultOK).BindG(ctx, &activity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate busiest hour: %w", err)
+	}
+
+	stats.BusiestHour = busiestHour(activity, loc)
+
+	since := now.Add(-config.Duration("STATS_TOP_USERS_WINDOW", 30*24*time.Hour))
+
+	var top []topUserRow
+	err = queries.Raw(topUsersQuery, since, taken, types.ResultOK, config.Int("STATS_TOP_USERS", 5)).BindG(ctx, &top)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate top users: %w", err)
+	}
+
+	for _, row := range top {
+		stats.TopUsers = append(stats.TopUsers, TopUser{Rfid: row.Rfid, Login: row.Login, Takes: row.Takes})
+	}
+
+	return stats, nil
+}
+
+// busiestHour returns the hour of the day in loc with the most activity, the earliest of the tied ones, or nil
+// without any
+func busiestHour(activity []activityRow, loc *time.Location) *int {
+	var counts [24]int
+	for _, row := range activity {
+		counts[row.ReceivedAt.In(loc).Hour()]++
+	}
+
+	var busiest *int
+	for hour := range counts {
+		if counts[hour] > 0 && (busiest == nil || counts[hour] > counts[*busiest]) {
+			h := hour
+			busiest = &h
+		}
+	}
+
+	return busiest
+}
+
+// ListOccupancySamples returns the occupancy of the cabinets over time, as sampled by package occupancy
+func (s *server) ListOccupancySamples(w http.ResponseWriter, r *http.Request, params ListOccupancySamplesParams) {
+	var filters []qm.QueryMod
+	if params.From != nil {
+		filters = append(filters, models.OccupancySampleWhere.SampledAt.GTE(*params.From))
+	}
+
+	if params.To != nil {
+		filters = append(filters, models.OccupancySampleWhere.SampledAt.LT(*params.To))
+	}
+
+	if params.Cabinet != nil && *params.Cabinet != "" {
+		filters = append(filters, models.OccupancySampleWhere.CabinetID.EQ(*params.Cabinet))
+	}
+
+	limit, offset, err := pagination(params.Limit, params.Offset)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	total, err := models.OccupancySamples(filters...).CountG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to count occupancy samples")
+		writeError(w, http.StatusInternalServerError, "failed to fetch occupancy samples")
+		return
+	}
+
+	page := []qm.QueryMod{
+		qm.OrderBy(models.OccupancySampleColumns.SampledAt + ", " + models.OccupancySampleColumns.CabinetID),
+		qm.Limit(limit),
+		qm.Offset(offset),
+	}
+
+	samples, err := models.OccupancySamples(append(filters, page...)...).AllG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch occupancy samples")
+		writeError(w, http.StatusInternalServerError, "failed to fetch occupancy samples")
+		return
+	}
+
+	if samples == nil {
+		samples = models.OccupancySampleSlice{}
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	writeJSON(w, http.StatusOK, samples)
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/commands"
+	"letovo-computers-server/config"
+	"letovo-computers-server/models"
+)
+
+// UnlockSlot asks the device owning the slot to unlock it, replying with 504 if the device does not acknowledge
+// the command within UNLOCK_TIMEOUT
+func (s *server) UnlockSlot(w http.ResponseWriter, r *http.Request, id SlotID) {
+	slotID := s.opts.Aliases.ID(id)
+
+	_, err := models.FindSlotG(r.Context(), slotID)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "slot not found")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch slot")
+		writeError(w, http.StatusInternalServerError, "failed to fetch slot")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), config.Duration("UNLOCK_TIMEOUT", 5*time.Second))
+	defer cancel()
+
+	device := s.opts.Scopes.Device(slotID)
+
+	reply, err := s.opts.Commands.Send(ctx, commands.Command{Cmd: commands.CmdUnlock, Device: device, Slot: slotID})
+	if errors.Is(err, commands.ErrNoReply) {
+		log.Warn().Str("slot", slotID).Str("device", device).Msgf("device did not acknowledge unlocking %s", slotID)
+		writeError(w, http.StatusGatewayTimeout, "device did not acknowledge the command")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to send unlock command")
+		writeError(w, http.StatusInternalServerError, "failed to send unlock command")
+		return
+	}
+
+	if !reply.OK {
+		writeError(w, http.StatusBadGateway, "device failed to unlock: "+reply.Error)
+		return
+	}
+
+	log.Info().Str("slot", slotID).Str("device", device).Msgf("unlocked %s", slotID)
+
+	writeJSON(w, http.StatusOK, reply)
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/models"
+	"letovo-computers-server/types"
+)
+
+type userResponse struct {
+	*models.User
+	Slots []slotResponse `json:"slots"`
+}
+
+func (s *server) GetUser(w http.ResponseWriter, r *http.Request, rfid RFID) {
+	user, err := models.FindUserG(r.Context(), rfid)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch user")
+		writeError(w, http.StatusInternalServerError, "failed to fetch user")
+		return
+	}
+
+	slots, err := user.TakenBySlots(models.SlotWhere.IsTaken.EQ(true)).AllG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch slots taken by user")
+		writeError(w, http.StatusInternalServerError, "failed to fetch user")
+		return
+	}
+
+	response := userResponse{User: user, Slots: make([]slotResponse, 0, len(slots))}
+	for _, slot := range slots {
+		response.Slots = append(response.Slots, s.slotResponse(slot))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+type userRequest struct {
+	Login  string `json:"login"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Class  string `json:"class"`
+	Role   string `json:"role"`
+	Active *bool  `json:"active"`
+}
+
+func (s *server) PutUser(w http.ResponseWriter, r *http.Request, rfid RFID) {
+	if err := types.ValidateRFID(rfid); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	request := new(userRequest)
+
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "malformed request body")
+		return
+	}
+
+	user := models.User{
+		ID:     rfid,
+		Login:  strings.TrimSpace(request.Login),
+		Name:   strings.TrimSpace(request.Name),
+		Email:  strings.TrimSpace(request.Email),
+		Class:  strings.TrimSpace(request.Class),
+		Role:   types.UserStudent,
+		Active: request.Active == nil || *request.Active,
+	}
+
+	if request.Role != "" {
+		if err := types.ValidateUserRole(request.Role); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		user.Role = request.Role
+	}
+
+	// binding the tag of a departed user again restores them
+	existing, err := models.Users(models.UserWhere.ID.EQ(rfid), qm.WithDeleted()).OneG(r.Context())
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Error().Err(err).Msg("failed to fetch user")
+		writeError(w, http.StatusInternalServerError, "failed to upsert user")
+		return
+	}
+	if existing != nil {
+		auditBefore(r, existing)
+	}
+
+	// the profile is replaced as a whole
+	columns := []string{
+		models.UserColumns.Login, models.UserColumns.Name, models.UserColumns.Email, models.UserColumns.Class,
+		models.UserColumns.Role, models.UserColumns.Active, models.UserColumns.UpdatedAt, models.UserColumns.DeletedAt,
+	}
+
+	err = user.UpsertG(r.Context(), true, []string{models.UserColumns.ID},
+		boil.Whitelist(columns...), boil.Whitelist(append(columns, models.UserColumns.ID, models.UserColumns.CreatedAt)...),
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to upsert user")
+		writeError(w, http.StatusInternalServerError, "failed to upsert user")
+		return
+	}
+
+	log.Info().Str("RFID", rfid).Str("login", user.Login).Msgf("bound %s to %s", rfid, user.Login)
+
+	// an existing user keeps the time it was created at, which the upsert does not return
+	if err := user.ReloadG(r.Context()); err != nil {
+		log.Error().Err(err).Msg("failed to fetch user")
+		writeError(w, http.StatusInternalServerError, "failed to fetch user")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+// DeleteUser deactivates the departed user, leaving them out of the lookups while their events and loans are kept.
+// A user holding slots is to have them released first
+func (s *server) DeleteUser(w http.ResponseWriter, r *http.Request, rfid RFID) {
+	user, err := models.FindUserG(r.Context(), rfid)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch user")
+		writeError(w, http.StatusInternalServerError, "failed to fetch user")
+		return
+	}
+
+	taken, err := user.TakenBySlots(models.SlotWhere.IsTaken.EQ(true)).CountG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to count slots taken by user")
+		writeError(w, http.StatusInternalServerError, "failed to delete user")
+		return
+	}
+	if taken > 0 {
+		writeError(w, http.StatusConflict, fmt.Sprintf("user holds %d slots, release them first", taken))
+		return
+	}
+
+	auditBefore(r, user)
+
+	user.Active = false
+	user.DeletedAt = null.TimeFrom(time.Now())
+
+	columns := boil.Whitelist(models.UserColumns.Active, models.UserColumns.DeletedAt, models.UserColumns.UpdatedAt)
+	if _, err := user.UpdateG(r.Context(), columns); err != nil {
+		log.Error().Err(err).Msg("failed to delete user")
+		writeError(w, http.StatusInternalServerError, "failed to delete user")
+		return
+	}
+
+	log.Info().Str("RFID", rfid).Str("login", user.Login).Msgf("deactivated %s", rfid)
+
+	w.WriteHeader(http.StatusNoContent)
+}
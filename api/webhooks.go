@@ -0,0 +1,121 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/models"
+	"letovo-computers-server/webhooks"
+)
+
+// webhookResponse leaves the secret out, it is only ever shown to the admin registering the webhook
+type webhookResponse struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newWebhookResponse(hook *models.Webhook) webhookResponse {
+	events := []string(hook.Events)
+	if events == nil {
+		events = []string{}
+	}
+
+	return webhookResponse{ID: hook.ID, URL: hook.URL, Events: events, CreatedAt: hook.CreatedAt}
+}
+
+func (s *server) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := models.Webhooks(qm.OrderBy(models.WebhookColumns.ID)).AllG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch webhooks")
+		writeError(w, http.StatusInternalServerError, "failed to fetch webhooks")
+		return
+	}
+
+	response := make([]webhookResponse, 0, len(hooks))
+	for _, hook := range hooks {
+		response = append(response, newWebhookResponse(hook))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+type webhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret"`
+}
+
+func (s *server) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	request := new(webhookRequest)
+
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "malformed request body")
+		return
+	}
+
+	u, err := url.Parse(request.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		writeError(w, http.StatusBadRequest, "url must be an absolute http(s) url")
+		return
+	}
+
+	for _, event := range request.Events {
+		if !webhooks.ValidEvent(event) {
+			writeError(w, http.StatusBadRequest, "unknown event "+event)
+			return
+		}
+	}
+
+	hook := models.Webhook{URL: request.URL, Events: request.Events, Secret: request.Secret}
+	if hook.Events == nil {
+		hook.Events = []string{}
+	}
+
+	err = hook.InsertG(r.Context(), boil.Whitelist("url", "events", "secret"))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to insert webhook")
+		writeError(w, http.StatusInternalServerError, "failed to insert webhook")
+		return
+	}
+
+	log.Info().Int("webhook", hook.ID).Msgf("registered webhook %s", hook.URL)
+
+	writeJSON(w, http.StatusCreated, newWebhookResponse(&hook))
+}
+
+func (s *server) DeleteWebhook(w http.ResponseWriter, r *http.Request, id int) {
+	hook, err := models.FindWebhookG(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, "webhook not found")
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch webhook")
+		writeError(w, http.StatusInternalServerError, "failed to fetch webhook")
+		return
+	}
+
+	auditBefore(r, newWebhookResponse(hook))
+
+	_, err = hook.DeleteG(r.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to delete webhook")
+		writeError(w, http.StatusInternalServerError, "failed to delete webhook")
+		return
+	}
+
+	log.Info().Int("webhook", id).Msgf("deleted webhook %s", hook.URL)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/types"
+)
+
+const (
+	// wsBuffer is how many events a slow WebSocket client may fall behind before being dropped
+	wsBuffer = 64
+
+	wsWriteTimeout = 10 * time.Second
+	wsPingInterval = 30 * time.Second
+	wsPongTimeout  = 2 * wsPingInterval
+)
+
+var upgrader = websocket.Upgrader{
+	// the wall displays are served from elsewhere, and the stream only carries what /recent already exposes
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// StreamEvents pushes the successfully processed events, and the slots changed in the db directly, to the WebSocket
+// client as they are persisted
+func (s *server) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has already replied with the error
+		log.Debug().Err(err).Msg("failed to upgrade to websocket")
+		return
+	}
+	defer conn.Close()
+
+	events, cancel := s.opts.Hub.Subscribe(wsBuffer)
+	defer cancel()
+
+	// the read loop only serves to process the control frames and notice the client going away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+
+		conn.SetReadLimit(512)
+		_ = conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+		})
+
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+
+		case <-ping.C:
+			err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout))
+			if err != nil {
+				return
+			}
+
+		case event, ok := <-events:
+			if !ok {
+				// the client fell behind, let it reconnect and catch up from /recent
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "fell behind"), time.Now().Add(wsWriteTimeout),
+				)
+				return
+			}
+
+			if event.Status == types.Disconnected || !applied(event) {
+				continue
+			}
+
+			_ = conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}
+}
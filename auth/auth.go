@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"letovo-computers-server/config"
+	"letovo-computers-server/models"
+)
+
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+// ranks orders the roles, each role being allowed everything the lower ones are
+var ranks = map[string]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// ValidRole reports whether the role is one of the known roles
+func ValidRole(role string) bool {
+	_, ok := ranks[role]
+	return ok
+}
+
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Identity is the authenticated caller
+type Identity struct {
+	Subject string
+	Role    string
+}
+
+// Allowed reports whether the identity has at least the role
+func (i *Identity) Allowed(role string) bool {
+	rank, ok := ranks[i.Role]
+	return ok && rank >= ranks[role]
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+	Role string `json:"role"`
+}
+
+// JWTEnabled reports whether JWT_ALG is configured. Until it is, the read-only endpoints stay public
+func JWTEnabled() bool {
+	return config.String("JWT_ALG", "") != ""
+}
+
+// Authenticate validates the bearer token: either a JWT signed with the configured JWT_ALG and key, which has
+// to expire, or the static API_ADMIN_TOKEN, which grants the admin role. The role assigned to the subject of the JWT
+// in the roles table takes precedence over the role claimed by the token
+func Authenticate(ctx context.Context, token string) (*Identity, error) {
+	if token == "" {
+		return nil, ErrUnauthorized
+	}
+
+	if expected := config.String("API_ADMIN_TOKEN", ""); expected != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+		return &Identity{Subject: "admin-token", Role: RoleAdmin}, nil
+	}
+
+	if !JWTEnabled() {
+		return nil, ErrUnauthorized
+	}
+
+	c := new(claims)
+	_, err := jwt.ParseWithClaims(token, c, keyFunc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnauthorized, err)
+	}
+
+	// the tokens without an expiry would stay valid forever once leaked
+	if c.ExpiresAt == nil {
+		return nil, fmt.Errorf("%w: token has no expiry", ErrUnauthorized)
+	}
+
+	if issuer := config.String("JWT_ISSUER", ""); issuer != "" && !c.VerifyIssuer(issuer, true) {
+		return nil, fmt.Errorf("%w: unexpected issuer", ErrUnauthorized)
+	}
+
+	if audience := config.String("JWT_AUDIENCE", ""); audience != "" && !c.VerifyAudience(audience, true) {
+		return nil, fmt.Errorf("%w: unexpected audience", ErrUnauthorized)
+	}
+
+	identity := &Identity{Subject: c.Subject, Role: c.Role}
+	if c.Subject != "" {
+		assigned, err := models.FindRoleG(ctx, c.Subject)
+		switch {
+		case err == nil:
+			identity.Role = assigned.Role
+		case !errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("failed to fetch role of %s: %w", c.Subject, err)
+		}
+	}
+
+	if !ValidRole(identity.Role) {
+		return nil, fmt.Errorf("%w: unknown role %q", ErrUnauthorized, identity.Role)
+	}
+
+	return identity, nil
+}
+
+// keyFunc returns the key of the configured JWT_ALG, rejecting tokens signed with any other algorithm
+func keyFunc(token *jwt.Token) (interface{}, error) {
+	alg := config.String("JWT_ALG", "")
+	if token.Method.Alg() != alg {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+
+	switch alg {
+	case jwt.SigningMethodHS256.Alg():
+		secret := config.String("JWT_SECRET", "")
+		if secret == "" {
+			return nil, errors.New("JWT_SECRET is not configured")
+		}
+
+		return []byte(secret), nil
+
+	case jwt.SigningMethodRS256.Alg():
+		return publicKey(config.String("JWT_PUBLIC_KEY_FILE", ""))
+
+	default:
+		return nil, fmt.Errorf("unsupported JWT_ALG %s", alg)
+	}
+}
+
+var (
+	keyMu   sync.Mutex
+	keyPath string
+	key     *rsa.PublicKey
+)
+
+// publicKey loads the PEM-encoded RSA public key, caching it until the path changes
+func publicKey(path string) (*rsa.PublicKey, error) {
+	keyMu.Lock()
+	defer keyMu.Unlock()
+
+	if key != nil && path == keyPath {
+		return key, nil
+	}
+
+	if path == "" {
+		return nil, errors.New("JWT_PUBLIC_KEY_FILE is not configured")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	parsed, err := jwt.ParseRSAPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	key, keyPath = parsed, path
+
+	return key, nil
+}
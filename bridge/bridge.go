@@ -0,0 +1,124 @@
+// Package bridge mirrors the processed events to a secondary broker, like the central IoT platform of the school
+package bridge
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/config"
+	"letovo-computers-server/events"
+	"letovo-computers-server/types"
+)
+
+// Bridge publishes the events to the secondary broker over a connection of its own, so that the secondary broker
+// being down never holds up the primary one. It only publishes and never subscribes, so nothing comes back through it
+type Bridge struct {
+	client    mqtt.Client
+	topic     string
+	publisher *events.Publisher
+}
+
+// New creates the bridge to BRIDGE_BROKERS, a comma-separated list of URLs like tls://iot.example.com:8883 tried
+// in order, mirroring the events to BRIDGE_TOPIC, batched like the SERVER_EVENTS_TOPIC ones. The bridge is nil
+// when BRIDGE_BROKERS is not set
+func New() (*Bridge, error) {
+	brokers := config.String("BRIDGE_BROKERS", "")
+	if brokers == "" {
+		return nil, nil
+	}
+
+	topic := config.String("BRIDGE_TOPIC", config.String("SERVER_EVENTS_TOPIC", ""))
+	if topic == "" {
+		return nil, errors.New("BRIDGE_TOPIC is not set")
+	}
+
+	tlsCfg, err := broker.TLSConfig("BRIDGE")
+	if err != nil {
+		return nil, err
+	}
+
+	opts := mqtt.NewClientOptions()
+	for _, address := range strings.Split(brokers, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			opts.AddBroker(address)
+		}
+	}
+
+	opts.
+		SetClientID(config.String("BRIDGE_CLIENT_ID", os.Getenv("MQTT_CLIENT_ID")+"-bridge")).
+		SetUsername(os.Getenv("BRIDGE_USER")).
+		SetPassword(os.Getenv("BRIDGE_PASS")).
+		SetTLSConfig(tlsCfg).
+		SetConnectionAttemptHandler(func(broker *url.URL, tlsCfg *tls.Config) *tls.Config {
+			log.Debug().Str("broker", broker.Redacted()).Msg("Connecting to bridged broker")
+
+			return tlsCfg
+		}).
+		SetConnectionLostHandler(func(client mqtt.Client, err error) {
+			log.Warn().Err(err).Msg("Connection lost to bridged broker")
+		}).
+		SetOnConnectHandler(func(client mqtt.Client) {
+			log.Info().Msg("Connected to bridged broker")
+		}).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(config.Duration("BRIDGE_RECONNECT_INTERVAL", 5*time.Second)).
+		SetAutoReconnect(true).
+		SetMaxReconnectInterval(config.Duration("BRIDGE_MAX_RECONNECT_INTERVAL", 10*time.Minute))
+
+	client := mqtt.NewClient(opts)
+
+	return &Bridge{
+		client: client,
+		topic:  topic,
+		publisher: events.NewPublisher(client, topic, broker.DeliveryFor("BRIDGE_TOPIC"),
+			config.Duration("EVENTS_BATCH_WINDOW", 0), config.Int("EVENTS_BATCH_SIZE", 100),
+		),
+	}, nil
+}
+
+// Start connects to the secondary broker in the background, retrying until it is reachable. Events mirrored
+// in the meantime are published once connected. Mirroring to a topic one of the routes subscribes to is refused,
+// as a secondary broker forwarding it back to the primary one would have the server process its own events
+// over and over
+func (b *Bridge) Start(router *broker.Router) error {
+	if setting, ok := router.Route(b.topic); ok {
+		return fmt.Errorf("BRIDGE_TOPIC %s matches the topic of %s, mirrored events could loop back", b.topic, setting)
+	}
+
+	b.client.Connect()
+
+	return nil
+}
+
+// Mirror publishes the event to the secondary broker
+func (b *Bridge) Mirror(event types.Event) {
+	b.publisher.Publish(event)
+}
+
+// Close publishes the current batch and disconnects from the secondary broker, abandoning the events not yet
+// published once ctx is done
+func (b *Bridge) Close(ctx context.Context) {
+	closed := make(chan struct{})
+	go func() {
+		b.publisher.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-ctx.Done():
+		log.Warn().Msg("abandoning the events not yet mirrored to the bridged broker")
+	}
+
+	b.client.Disconnect(250)
+}
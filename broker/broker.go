@@ -1,57 +1,413 @@
 package broker
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"math/rand"
+	"net"
+	"net/url"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/config"
+	"letovo-computers-server/metrics"
+)
+
+// Broker is the connection to the MQTT broker the server relies on. It is implemented by the paho clients of
+// both protocol versions, and by brokertest.Fake for running the handlers without a live broker
+type Broker interface {
+	Connect() mqtt.Token
+	Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token
+	Subscribe(topic string, qos byte, callback mqtt.MessageHandler) mqtt.Token
+	Unsubscribe(topics ...string) mqtt.Token
+	Disconnect(quiesce uint)
+	IsConnectionOpen() bool
+}
+
+var (
+	rnd   = rand.New(rand.NewSource(time.Now().UnixNano()))
+	rndMu sync.Mutex
 )
 
-func Init() mqtt.Client {
-	opts := mqtt.NewClientOptions().
-		AddBroker(fmt.Sprintf("tls://%s:%s", os.Getenv("MQTT_HOST"), os.Getenv("MQTT_PORT"))).
+// reconnectDelay randomly spreads the interval by up to ±jitter of its length, so that clients
+// losing the connection at the same time do not reconnect in lockstep
+func reconnectDelay(interval time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return interval
+	}
+	if jitter > 1 {
+		jitter = 1
+	}
+
+	rndMu.Lock()
+	f := rnd.Float64()
+	rndMu.Unlock()
+
+	return interval + time.Duration((f*2-1)*jitter*float64(interval))
+}
+
+// sleep waits before the reconnect attempts, recorded by the tests
+var sleep = time.Sleep
+
+// jitteredReconnect returns the handler paho calls before every reconnect attempt. Paho makes its first attempt
+// right away and then backs off deterministically, so the handler delays every attempt by the jittered interval
+// and spreads the backoff cap anew
+func jitteredReconnect(interval, maxInterval time.Duration, jitter float64) mqtt.ReconnectHandler {
+	return func(client mqtt.Client, opts *mqtt.ClientOptions) {
+		opts.MaxReconnectInterval = reconnectDelay(maxInterval, jitter)
+		delay := reconnectDelay(interval, jitter)
+
+		log.Debug().Dur("delay", delay).Msg("Reconnecting to broker")
+		sleep(delay)
+	}
+}
+
+// brokerAddresses builds the URLs of the brokers from MQTT_HOST, a comma-separated list of hosts which may carry
+// their own port, falling back to MQTT_PORT. Paho tries them in order, failing over to the next one. MQTT_TRANSPORT
+// picks the scheme: tls by default, wss or ws for networks only letting HTTP(S) through, in which case MQTT_WS_PATH
+// is the path of the endpoint, or plain tcp. With the embedded broker they default to its loopback address, over tcp
+// unless it serves TLS
+func brokerAddresses() ([]string, error) {
+	hosts, transport := os.Getenv("MQTT_HOST"), "tls"
+	if Embedded() {
+		if !embeddedTLS() {
+			transport = "tcp"
+		}
+
+		if hosts == "" {
+			host, err := embeddedHost()
+			if err != nil {
+				return nil, err
+			}
+
+			hosts = host
+		}
+	}
+	transport = config.String("MQTT_TRANSPORT", transport)
+
+	var suffix string
+	switch transport {
+	case "tls", "tcp":
+	case "ws", "wss":
+		suffix = "/" + strings.TrimPrefix(config.String("MQTT_WS_PATH", "/mqtt"), "/")
+	default:
+		return nil, fmt.Errorf("unknown MQTT_TRANSPORT %q, expected tls, wss, ws or tcp", transport)
+	}
+
+	var addresses []string
+	for _, host := range strings.Split(hosts, ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(host, os.Getenv("MQTT_PORT"))
+		}
+
+		addresses = append(addresses, transport+"://"+host+suffix)
+	}
+
+	if len(addresses) == 0 {
+		return nil, errors.New("MQTT_HOST is not set")
+	}
+
+	return addresses, nil
+}
+
+// attempted is the broker paho last tried to connect to, which is the active one once connected
+var attempted atomic.Value
+
+// ActiveBroker returns the URL of the broker the client is connected or connecting to
+func ActiveBroker() string {
+	u, _ := attempted.Load().(string)
+
+	return u
+}
+
+// Init creates the client, calling onConnect every time the connection to the broker is (re)established.
+// If OUTGOING_BUFFER_FILE is set, messages published while the broker is unreachable are kept in it
+// and published once the connection is back instead of being dropped
+func Init(onConnect func(client Broker)) (Broker, error) {
+	addresses, err := brokerAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := newBuffer()
+	if err != nil {
+		return nil, err
+	}
+	if buf != nil {
+		handler := onConnect
+		onConnect = func(Broker) {
+			go buf.flush()
+			handler(buf)
+		}
+	}
+
+	tlsCfg, err := TLSConfig("MQTT")
+	if err != nil {
+		return nil, err
+	}
+
+	// MQTT 5 brings response topics, correlation data, reason codes and message expiry, which paho.mqtt.golang
+	// lacks, so it is spoken through paho.golang behind the same client interface
+	switch version := config.Int("MQTT_PROTOCOL_VERSION", 4); version {
+	case 4:
+	case 5:
+		c, err := newV5Client(addresses, tlsCfg, onConnect)
+		if err != nil {
+			return nil, err
+		}
+
+		return buf.wrap(c), nil
+	default:
+		return nil, fmt.Errorf("unknown MQTT_PROTOCOL_VERSION %d, expected 4 or 5", version)
+	}
+
+	reconnectInterval := config.Duration("MQTT_RECONNECT_INTERVAL", time.Second)
+	maxReconnectInterval := config.Duration("MQTT_MAX_RECONNECT_INTERVAL", 10*time.Minute)
+	jitter := config.Float("MQTT_RECONNECT_JITTER", 0.2)
+	will := DeliveryFor("SERVER_WILL_TOPIC")
+
+	opts := mqtt.NewClientOptions()
+	for _, address := range addresses {
+		opts.AddBroker(address)
+	}
+
+	opts.
 		SetClientID(os.Getenv("MQTT_CLIENT_ID")).
 		SetUsername(os.Getenv("MQTT_USER")).
 		SetPassword(os.Getenv("MQTT_PASS")).
+		SetTLSConfig(tlsCfg).
+		SetConnectionAttemptHandler(func(broker *url.URL, tlsCfg *tls.Config) *tls.Config {
+			attempted.Store(broker.Redacted())
+			log.Debug().Str("broker", broker.Redacted()).Msg("Connecting to broker")
+
+			return tlsCfg
+		}).
 		SetConnectionLostHandler(func(client mqtt.Client, err error) {
-			log.Warn().Err(err).Msg("Connection lost to broker")
+			log.Warn().Err(err).Str("broker", ActiveBroker()).Msg("Connection lost to broker")
 		}).
 		SetOnConnectHandler(func(client mqtt.Client) {
-			log.Debug().Msg("Connected to broker")
+			log.Info().Str("broker", ActiveBroker()).Msg("Connected to broker")
+			onConnect(client)
 		}).
+		SetAutoAckDisabled(ManualAck()).
+		SetAutoReconnect(true).
+		SetMaxReconnectInterval(reconnectDelay(maxReconnectInterval, jitter)).
+		SetReconnectingHandler(jitteredReconnect(reconnectInterval, maxReconnectInterval, jitter)).
 		SetBinaryWill(
-			os.Getenv("SERVER_WILL_TOPIC"), []byte("{\"message\":\"server disconnected\"}"), 2, true,
+			os.Getenv("SERVER_WILL_TOPIC"), []byte(willPayload), will.QoS, will.Retained,
 		)
 
-	return mqtt.NewClient(opts)
+	// a resumed session delivers the messages queued for the server before the routes are subscribed
+	warnSessionClientID(os.Getenv("MQTT_CLIENT_ID"))
+	if !CleanSession() {
+		opts.
+			SetCleanSession(false).
+			SetDefaultPublishHandler(func(_ mqtt.Client, msg mqtt.Message) {
+				held.hold(msg)
+			})
+	}
+
+	return buf.wrap(mqtt.NewClient(opts)), nil
 }
 
-func Subscribe(wg *sync.WaitGroup, client mqtt.Client, topic string, qos byte, callback func(client mqtt.Client, resp mqtt.Message)) {
-	wg.Add(1)
+const (
+	// willPayload is published by the broker to the SERVER_WILL_TOPIC once the server is gone without a word
+	willPayload = `{"message":"server disconnected"}`
+	// goodbyePayload is published by the server itself to the SERVER_WILL_TOPIC when it shuts down cleanly
+	goodbyePayload = `{"message":"server shutting down"}`
+)
+
+// Goodbye tells the SERVER_WILL_TOPIC that the server is shutting down cleanly, taking the place of the will
+// the broker discards on a clean disconnect
+func Goodbye(ctx context.Context, client Broker) error {
+	topic := os.Getenv("SERVER_WILL_TOPIC")
+	if topic == "" {
+		return nil
+	}
+
+	will := DeliveryFor("SERVER_WILL_TOPIC")
+
+	return PublishSync(ctx, client, topic, will.QoS, will.Retained, goodbyePayload)
+}
+
+// ManualAck reports whether MQTT_MANUAL_ACK is set. In this mode messages are only acknowledged once they are
+// processed, so that a message whose processing failed is redelivered by the broker. This requires the message to be
+// published with QoS 1 or 2 and the session to outlive the connection, otherwise the broker discards it
+func ManualAck() bool {
+	return config.Bool("MQTT_MANUAL_ACK", false)
+}
+
+// Acked wraps the callback to acknowledge every message once it returns, for handlers which never retry
+func Acked(callback func(client mqtt.Client, resp mqtt.Message)) func(client mqtt.Client, resp mqtt.Message) {
+	return func(client mqtt.Client, resp mqtt.Message) {
+		defer resp.Ack()
+
+		callback(client, resp)
+	}
+}
+
+// ErrConnectTimeout is returned by Connect when the broker does not accept the connection in time
+var ErrConnectTimeout = errors.New("timed out connecting to broker")
+
+// Connect connects the client to the broker, giving up after the timeout
+func Connect(client Broker, timeout time.Duration) error {
+	t := client.Connect()
+	if !t.WaitTimeout(timeout) {
+		return fmt.Errorf("%w after %s", ErrConnectTimeout, timeout)
+	}
+
+	return t.Error()
+}
+
+// ConnectWithRetry keeps connecting the client until the broker accepts the connection, backing off from
+// MQTT_CONNECT_BACKOFF doubling up to MQTT_CONNECT_MAX_BACKOFF, spread by MQTT_RECONNECT_JITTER. It gives up after
+// MQTT_CONNECT_ATTEMPTS, retrying forever when zero, or once the context is done
+func ConnectWithRetry(ctx context.Context, client Broker, timeout time.Duration) error {
+	attempts := config.Int("MQTT_CONNECT_ATTEMPTS", 0)
+	backoff := config.Duration("MQTT_CONNECT_BACKOFF", time.Second)
+	maxBackoff := config.Duration("MQTT_CONNECT_MAX_BACKOFF", time.Minute)
+	jitter := config.Float("MQTT_RECONNECT_JITTER", 0.2)
+
+	for attempt := 1; ; attempt++ {
+		err := Connect(client, timeout)
+		if err == nil {
+			return nil
+		}
+
+		if attempts > 0 && attempt >= attempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		delay := reconnectDelay(backoff, jitter)
+		log.Warn().Err(err).Int("attempt", attempt).Dur("delay", delay).Msg("failed to connect to broker, retrying")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// SubscribeSync subscribes to the topic, waiting for the broker to grant the subscription until ctx is done
+func SubscribeSync(ctx context.Context, client Broker, topic string, qos byte, callback mqtt.MessageHandler) error {
+	setSubscription(topic, SubscriptionPending)
 	t := client.Subscribe(topic, qos, callback)
 
-	go func() {
-		defer wg.Done()
+	select {
+	case <-t.Done():
+	case <-ctx.Done():
+		setSubscription(topic, SubscriptionFailed)
+		metrics.SubscribeFailures.WithLabelValues(topic).Inc()
+		return fmt.Errorf("failed to subscribe to %s: %w", topic, ctx.Err())
+	}
 
-		<-t.Done()
-		if t.Error() != nil {
-			log.Error().Err(t.Error()).Msgf("failed to subscribe to %s", topic)
+	if err := subscribeError(t, topic); err != nil {
+		setSubscription(topic, SubscriptionFailed)
+		metrics.SubscribeFailures.WithLabelValues(topic).Inc()
+		return fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+	}
+
+	setSubscription(topic, SubscriptionActive)
+
+	return nil
+}
+
+// subscribeFailure is the return code of MQTT 3.1.1 brokers rejecting a subscription
+const subscribeFailure = 0x80
+
+// subscribeError returns the error of the completed subscription, including its rejection by the broker,
+// which paho does not report as an error
+func subscribeError(t mqtt.Token, topic string) error {
+	if t.Error() != nil {
+		return t.Error()
+	}
+
+	if st, ok := t.(*mqtt.SubscribeToken); ok {
+		if code, ok := st.Result()[topic]; ok && code >= subscribeFailure {
+			return fmt.Errorf("broker rejected the subscription with code 0x%02x", code)
 		}
+	}
+
+	return nil
+}
+
+// PublishSync publishes the payload, waiting for the broker to acknowledge it until ctx is done
+func PublishSync(ctx context.Context, client Broker, topic string, qos byte, retained bool, payload interface{}) error {
+	t := observePublish(topic, client.Publish(topic, qos, retained, payload))
+
+	select {
+	case <-t.Done():
+	case <-ctx.Done():
+		return fmt.Errorf("failed to publish to %s: %w", topic, ctx.Err())
+	}
+
+	if t.Error() != nil {
+		return fmt.Errorf("failed to publish to %s: %w", topic, t.Error())
+	}
+
+	return nil
+}
+
+// observePublish records the outcome of the publish and how long it took once the token completes, under the
+// route of the topic rather than the topic itself, which names devices and cabinets
+func observePublish(topic string, t mqtt.Token) mqtt.Token {
+	route, start := routeOf(topic), time.Now()
+	go func() {
+		<-t.Done()
+		metrics.ObservePublish(route, time.Since(start), t.Error())
 	}()
+
+	return t
 }
 
-func Publish(wg *sync.WaitGroup, client mqtt.Client, topic string, payload string) {
+// Publish publishes the payload with the delivery in the background, retrying with a doubling backoff up to
+// MQTT_PUBLISH_ATTEMPTS times. Payloads failing every attempt are spooled to the PUBLISH_SPOOL_FILE
+func Publish(wg *sync.WaitGroup, client Broker, topic string, delivery Delivery, payload string) {
+	attempts := config.Int("MQTT_PUBLISH_ATTEMPTS", 3)
+	backoff := config.Duration("MQTT_PUBLISH_BACKOFF", 500*time.Millisecond)
+
 	wg.Add(1)
-	t := client.Publish(topic, 2, true, payload)
+	t := observePublish(topic, client.Publish(topic, delivery.QoS, delivery.Retained, payload))
 
 	go func() {
 		defer wg.Done()
 
-		<-t.Done()
-		if t.Error() != nil {
-			log.Error().Err(t.Error()).Msg("failed to publish message")
+		for attempt := 1; ; attempt++ {
+			<-t.Done()
+			if t.Error() == nil {
+				return
+			}
+
+			if attempt >= attempts {
+				log.Error().Err(t.Error()).Str("topic", topic).Msgf("failed to publish message after %d attempts", attempt)
+				spool(topic, payload, t.Error())
+				return
+			}
+
+			log.Warn().Err(t.Error()).Str("topic", topic).Dur("backoff", backoff).Msg("failed to publish message, retrying")
+			time.Sleep(backoff)
+			backoff *= 2
+
+			t = observePublish(topic, client.Publish(topic, delivery.QoS, delivery.Retained, payload))
 		}
 	}()
 }
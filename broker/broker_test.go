@@ -0,0 +1,196 @@
+package broker
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReconnectDelayJitter(t *testing.T) {
+	const interval = 10 * time.Second
+
+	tests := []struct {
+		name     string
+		jitter   float64
+		min, max time.Duration
+	}{
+		{name: "spread", jitter: 0.2, min: 8 * time.Second, max: 12 * time.Second},
+		{name: "capped at the interval", jitter: 5, min: 0, max: 2 * interval},
+		{name: "disabled", jitter: 0, min: interval, max: interval},
+		{name: "negative", jitter: -1, min: interval, max: interval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := make(map[time.Duration]bool)
+			for i := 0; i < 1000; i++ {
+				delay := reconnectDelay(interval, tt.jitter)
+				if delay < tt.min || delay > tt.max {
+					t.Fatalf("reconnectDelay() = %v, want within [%v, %v]", delay, tt.min, tt.max)
+				}
+				seen[delay] = true
+			}
+
+			if tt.min != tt.max && len(seen) < 2 {
+				t.Error("reconnectDelay() returned the same delay every time, want it spread")
+			}
+		})
+	}
+}
+
+func TestReconnectSleepsJitteredDelay(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	t.Setenv("MQTT_EMBEDDED", "true")
+	t.Setenv("MQTT_EMBEDDED_ADDRESS", address)
+	t.Setenv("MQTT_CLIENT_ID", "test-reconnect")
+	t.Setenv("SERVER_WILL_TOPIC", "server/will")
+	t.Setenv("MQTT_RECONNECT_INTERVAL", "100ms")
+	t.Setenv("MQTT_RECONNECT_JITTER", "0.5")
+
+	var (
+		mu     sync.Mutex
+		delays []time.Duration
+	)
+	sleep = func(d time.Duration) {
+		mu.Lock()
+		delays = append(delays, d)
+		mu.Unlock()
+
+		time.Sleep(d)
+	}
+	t.Cleanup(func() { sleep = time.Sleep })
+
+	server, err := StartEmbedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := Init(func(Broker) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Connect(client, 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect(0)
+
+	// the broker going away makes paho reconnect
+	if err := server.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		mu.Lock()
+		n := len(delays)
+		mu.Unlock()
+
+		if n > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the client did not wait before reconnecting")
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if delay := delays[0]; delay < 50*time.Millisecond || delay > 150*time.Millisecond {
+		t.Errorf("waited %v before reconnecting, want within [50ms, 150ms]", delay)
+	}
+}
+
+func TestInitCallsOnConnectOnReconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	t.Setenv("MQTT_EMBEDDED", "true")
+	t.Setenv("MQTT_EMBEDDED_ADDRESS", address)
+	t.Setenv("MQTT_CLIENT_ID", "test-on-connect")
+	t.Setenv("SERVER_WILL_TOPIC", "server/will")
+	t.Setenv("MQTT_RECONNECT_INTERVAL", "10ms")
+	t.Setenv("MQTT_MAX_RECONNECT_INTERVAL", "100ms")
+
+	connects := make(chan struct{}, 2)
+	client, err := Init(func(Broker) { connects <- struct{}{} })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wait := func(what string) {
+		t.Helper()
+
+		select {
+		case <-connects:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("onConnect was not called after %s", what)
+		}
+	}
+
+	server, err := StartEmbedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Connect(client, 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect(0)
+	wait("connecting")
+
+	// the broker restarting makes paho reconnect, which must call onConnect again
+	if err := server.Close(); err != nil {
+		t.Fatal(err)
+	}
+	server, err = StartEmbedded()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	wait("reconnecting")
+}
+
+func TestRouteOf(t *testing.T) {
+	t.Setenv("SERVER_STATE_TOPIC", "server/state")
+	t.Setenv("SERVER_CABINET_STATE_TOPIC", "server/state/cabinets")
+	t.Setenv("ARDUINO_COMMAND_TOPIC", "arduino/+/command")
+
+	tests := []struct {
+		topic string
+		want  string
+	}{
+		{"server/state", "SERVER_STATE_TOPIC"},
+		{"server/state/A1", "SERVER_STATE_TOPIC"},
+		{"server/state/cabinets/C1", "SERVER_CABINET_STATE_TOPIC"},
+		{"arduino/C1/command", "ARDUINO_COMMAND_TOPIC"},
+		{"arduino/C1/reply", "other"},
+		{"elsewhere", "other"},
+	}
+	for _, tt := range tests {
+		if got := routeOf(tt.topic); got != tt.want {
+			t.Errorf("routeOf(%q) = %q, want %q", tt.topic, got, tt.want)
+		}
+	}
+}
+
+func TestEmbeddedListensOnLoopbackByDefault(t *testing.T) {
+	t.Setenv("MQTT_EMBEDDED_ADDRESS", "")
+
+	host, _, err := net.SplitHostPort(embeddedAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		t.Fatalf("embedded broker listens at %q by default, want a loopback address", embeddedAddress())
+	}
+}
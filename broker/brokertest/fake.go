@@ -0,0 +1,299 @@
+// Package brokertest provides an in-memory broker for running the message handlers without a live broker
+package brokertest
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"letovo-computers-server/broker"
+)
+
+// Message is a message published through or delivered by the Fake
+type Message struct {
+	topic    string
+	payload  []byte
+	qos      byte
+	retained bool
+
+	mu    sync.Mutex
+	acked bool
+}
+
+// NewMessage creates a message as the broker would deliver it
+func NewMessage(topic string, payload []byte, qos byte, retained bool) *Message {
+	return &Message{topic: topic, payload: payload, qos: qos, retained: retained}
+}
+
+func (m *Message) Duplicate() bool   { return false }
+func (m *Message) Qos() byte         { return m.qos }
+func (m *Message) Retained() bool    { return m.retained }
+func (m *Message) Topic() string     { return m.topic }
+func (m *Message) MessageID() uint16 { return 0 }
+func (m *Message) Payload() []byte   { return m.payload }
+
+func (m *Message) Ack() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.acked = true
+}
+
+// Acked reports whether the handler acknowledged the message
+func (m *Message) Acked() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.acked
+}
+
+// token is an already completed mqtt.Token
+type token struct {
+	err error
+}
+
+func done(err error) mqtt.Token {
+	return token{err: err}
+}
+
+func (t token) Wait() bool                     { return true }
+func (t token) WaitTimeout(time.Duration) bool { return true }
+func (t token) Error() error                   { return t.err }
+
+func (t token) Done() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+
+	return ch
+}
+
+// Fake is an in-memory broker. Messages published through it are recorded and delivered to its own matching
+// subscriptions, so that a handler publishing to a topic another handler subscribes to behaves as with a broker
+type Fake struct {
+	mu            sync.Mutex
+	connected     bool
+	subscriptions map[string]mqtt.MessageHandler
+	published     []*Message
+	retained      map[string]*Message
+	errs          map[string]error
+	onConnect     func(client broker.Broker)
+}
+
+var _ broker.Broker = (*Fake)(nil)
+
+// New creates a disconnected Fake
+func New() *Fake {
+	return &Fake{
+		subscriptions: make(map[string]mqtt.MessageHandler),
+		retained:      make(map[string]*Message),
+		errs:          make(map[string]error),
+	}
+}
+
+// Fail makes the operations on the topic fail with err, or succeed again when err is nil.
+// The empty topic stands for Connect
+func (f *Fake) Fail(topic string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err == nil {
+		delete(f.errs, topic)
+		return
+	}
+
+	f.errs[topic] = err
+}
+
+// OnConnect makes every successful Connect call handler, as the client created by broker.Init calls the onConnect
+// passed to it every time the connection is (re)established
+func (f *Fake) OnConnect(handler func(client broker.Broker)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.onConnect = handler
+}
+
+func (f *Fake) Connect() mqtt.Token {
+	f.mu.Lock()
+	if err := f.errs[""]; err != nil {
+		f.mu.Unlock()
+		return done(err)
+	}
+
+	f.connected = true
+	handler := f.onConnect
+	f.mu.Unlock()
+
+	if handler != nil {
+		handler(f)
+	}
+
+	return done(nil)
+}
+
+// Restart simulates the broker restarting without persistence: the retained messages are lost and the client
+// reconnects, calling the OnConnect handler again
+func (f *Fake) Restart() mqtt.Token {
+	f.mu.Lock()
+	f.connected = false
+	f.retained = make(map[string]*Message)
+	f.mu.Unlock()
+
+	return f.Connect()
+}
+
+func (f *Fake) Disconnect(uint) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.connected = false
+}
+
+func (f *Fake) IsConnected() bool {
+	return f.IsConnectionOpen()
+}
+
+func (f *Fake) IsConnectionOpen() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.connected
+}
+
+func (f *Fake) Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token {
+	var b []byte
+	switch v := payload.(type) {
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	}
+
+	f.mu.Lock()
+	if err := f.errs[topic]; err != nil {
+		f.mu.Unlock()
+		return done(err)
+	}
+
+	msg := NewMessage(topic, b, qos, retained)
+	f.published = append(f.published, msg)
+	if retained {
+		f.retained[topic] = msg
+	}
+	f.mu.Unlock()
+
+	f.Deliver(NewMessage(topic, b, qos, false))
+
+	return done(nil)
+}
+
+func (f *Fake) Subscribe(topic string, qos byte, callback mqtt.MessageHandler) mqtt.Token {
+	return f.SubscribeMultiple(map[string]byte{topic: qos}, callback)
+}
+
+// SubscribeMultiple subscribes to the topics, delivering the retained messages matching them
+func (f *Fake) SubscribeMultiple(filters map[string]byte, callback mqtt.MessageHandler) mqtt.Token {
+	f.mu.Lock()
+	var retained []*Message
+	for topic := range filters {
+		if err := f.errs[topic]; err != nil {
+			f.mu.Unlock()
+			return done(err)
+		}
+
+		f.subscriptions[topic] = callback
+		for _, msg := range f.retained {
+			if matches(topic, msg.topic) {
+				retained = append(retained, NewMessage(msg.topic, msg.payload, msg.qos, true))
+			}
+		}
+	}
+	f.mu.Unlock()
+
+	for _, msg := range retained {
+		callback(f, msg)
+	}
+
+	return done(nil)
+}
+
+func (f *Fake) Unsubscribe(topics ...string) mqtt.Token {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, topic := range topics {
+		delete(f.subscriptions, topic)
+	}
+
+	return done(nil)
+}
+
+func (f *Fake) AddRoute(topic string, callback mqtt.MessageHandler) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.subscriptions[topic] = callback
+}
+
+func (f *Fake) OptionsReader() mqtt.ClientOptionsReader {
+	return mqtt.ClientOptionsReader{}
+}
+
+// Deliver hands the message to the handlers of the subscriptions matching its topic, returning once they return
+func (f *Fake) Deliver(msg mqtt.Message) {
+	f.mu.Lock()
+	var handlers []mqtt.MessageHandler
+	for topic, handler := range f.subscriptions {
+		if matches(topic, msg.Topic()) {
+			handlers = append(handlers, handler)
+		}
+	}
+	f.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(f, msg)
+	}
+}
+
+// Published returns the messages published to the topic so far, oldest first
+func (f *Fake) Published(topic string) []*Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var messages []*Message
+	for _, msg := range f.published {
+		if msg.topic == topic {
+			messages = append(messages, msg)
+		}
+	}
+
+	return messages
+}
+
+// Subscriptions returns the topics subscribed to
+func (f *Fake) Subscriptions() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	topics := make([]string, 0, len(f.subscriptions))
+	for topic := range f.subscriptions {
+		topics = append(topics, topic)
+	}
+
+	return topics
+}
+
+// matches reports whether the subscription, shared or not, covers the topic
+func matches(subscription, topic string) bool {
+	if strings.HasPrefix(subscription, "$share/") {
+		if parts := strings.SplitN(subscription, "/", 3); len(parts) == 3 {
+			subscription = parts[2]
+		}
+	}
+
+	_, ok := broker.Match(subscription, topic)
+
+	return ok
+}
@@ -0,0 +1,296 @@
+package broker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/config"
+)
+
+// bufferedMessage is a line of the OUTGOING_BUFFER_FILE
+type bufferedMessage struct {
+	Topic           string     `json:"topic"`
+	Payload         []byte     `json:"payload"`
+	QoS             byte       `json:"qos"`
+	Retained        bool       `json:"retained"`
+	ResponseTopic   string     `json:"response_topic,omitempty"`
+	CorrelationData []byte     `json:"correlation_data,omitempty"`
+	ContentType     string     `json:"content_type,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	BufferedAt      time.Time  `json:"buffered_at"`
+}
+
+// buffer keeps the messages published while the broker is unreachable in the OUTGOING_BUFFER_FILE,
+// publishing them once the connection is back. Messages published while the buffer is not empty are buffered too,
+// so that they reach the broker in order and a stale retained message does not overwrite a newer one
+type buffer struct {
+	mqtt.Client
+
+	path    string
+	timeout time.Duration
+
+	mu       sync.Mutex
+	pending  bool
+	flushing sync.Mutex
+}
+
+// newBuffer creates the buffer if the OUTGOING_BUFFER_FILE is configured, returning nil otherwise
+func newBuffer() (*buffer, error) {
+	path := config.String("OUTGOING_BUFFER_FILE", "")
+	if path == "" {
+		return nil, nil
+	}
+
+	b := &buffer{
+		path:    path,
+		timeout: config.Duration("MQTT_PUBLISH_TIMEOUT", 30*time.Second),
+	}
+
+	// messages left over by a previous run are flushed on the first connection
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		b.pending = info.Size() > 0
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("failed to open outgoing buffer: %w", err)
+	}
+
+	return b, nil
+}
+
+// wrap makes the buffer publish through the client, returning the client as is when there is no buffer
+func (b *buffer) wrap(client mqtt.Client) Broker {
+	if b == nil {
+		return client
+	}
+
+	b.Client = client
+
+	return b
+}
+
+// handler hands the buffer to the callback in place of the client, so that the handlers publish through it
+func (b *buffer) handler(callback mqtt.MessageHandler) mqtt.MessageHandler {
+	return func(_ mqtt.Client, msg mqtt.Message) {
+		callback(b, msg)
+	}
+}
+
+func (b *buffer) Subscribe(topic string, qos byte, callback mqtt.MessageHandler) mqtt.Token {
+	return b.Client.Subscribe(topic, qos, b.handler(callback))
+}
+
+func (b *buffer) SubscribeMultiple(filters map[string]byte, callback mqtt.MessageHandler) mqtt.Token {
+	return b.Client.SubscribeMultiple(filters, b.handler(callback))
+}
+
+func (b *buffer) AddRoute(topic string, callback mqtt.MessageHandler) {
+	b.Client.AddRoute(topic, b.handler(callback))
+}
+
+func (b *buffer) Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token {
+	return b.publish(topic, qos, retained, payload, nil)
+}
+
+func (b *buffer) publish(topic string, qos byte, retained bool, payload interface{}, props *Properties) mqtt.Token {
+	b.mu.Lock()
+	if !b.pending && b.Client.IsConnectionOpen() {
+		b.mu.Unlock()
+		return publish(b.Client, topic, qos, retained, payload, props)
+	}
+
+	err := b.store(topic, qos, retained, payload, props)
+	if err == nil {
+		b.pending = true
+	}
+	b.mu.Unlock()
+
+	if err != nil {
+		log.Error().Err(err).Str("topic", topic).Msg("failed to buffer outgoing message")
+	}
+
+	return newToken(func() error { return err })
+}
+
+// store appends the message to the file, b.mu being held
+func (b *buffer) store(topic string, qos byte, retained bool, payload interface{}, props *Properties) error {
+	msg := bufferedMessage{Topic: topic, QoS: qos, Retained: retained, BufferedAt: time.Now()}
+	switch v := payload.(type) {
+	case string:
+		msg.Payload = []byte(v)
+	case []byte:
+		msg.Payload = v
+	default:
+		return fmt.Errorf("unsupported payload type %T", payload)
+	}
+
+	if props != nil {
+		msg.ResponseTopic = props.ResponseTopic
+		msg.CorrelationData = props.CorrelationData
+		msg.ContentType = props.ContentType
+		if props.MessageExpiry > 0 {
+			expiresAt := msg.BufferedAt.Add(props.MessageExpiry)
+			msg.ExpiresAt = &expiresAt
+		}
+	}
+
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal buffered message: %w", err)
+	}
+
+	f, err := os.OpenFile(b.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+
+	return err
+}
+
+// load reads the buffered messages, b.mu being held
+func (b *buffer) load() ([]bufferedMessage, error) {
+	data, err := os.ReadFile(b.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []bufferedMessage
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var msg bufferedMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			log.Error().Err(err).Msg("dropping corrupt line of the outgoing buffer")
+			continue
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages, scanner.Err()
+}
+
+// save replaces the buffered messages, b.mu being held
+func (b *buffer) save(messages []bufferedMessage) error {
+	var data []byte
+	for _, msg := range messages {
+		line, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+
+		data = append(append(data, line...), '\n')
+	}
+
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, b.path)
+}
+
+// flush publishes the buffered messages in order, stopping at the first one the broker does not take,
+// which is kept along with the rest for the next connection. Messages older than OUTGOING_BUFFER_MAX_AGE
+// or past their expiry are dropped
+func (b *buffer) flush() {
+	b.flushing.Lock()
+	defer b.flushing.Unlock()
+
+	for {
+		b.mu.Lock()
+		messages, err := b.load()
+		if err == nil && len(messages) == 0 {
+			b.pending = false
+			err = os.Remove(b.path)
+			if errors.Is(err, os.ErrNotExist) {
+				err = nil
+			}
+		}
+		b.mu.Unlock()
+
+		if err != nil {
+			log.Error().Err(err).Msg("failed to flush outgoing buffer")
+			return
+		}
+		if len(messages) == 0 {
+			return
+		}
+
+		sent, err := b.send(messages)
+
+		// messages buffered meanwhile were appended, so the sent ones are still the first lines
+		b.mu.Lock()
+		current, loadErr := b.load()
+		if loadErr == nil {
+			if sent > len(current) {
+				sent = len(current)
+			}
+			loadErr = b.save(current[sent:])
+		}
+		b.mu.Unlock()
+
+		if loadErr != nil {
+			log.Error().Err(loadErr).Msg("failed to update outgoing buffer")
+			return
+		}
+		if err != nil {
+			log.Warn().Err(err).Int("left", len(current)-sent).Msg("failed to flush outgoing buffer, retrying on reconnect")
+			return
+		}
+
+		log.Info().Int("messages", sent).Msg("Flushed outgoing buffer")
+	}
+}
+
+// send publishes the messages, returning how many of them were either published or dropped
+func (b *buffer) send(messages []bufferedMessage) (int, error) {
+	maxAge := config.Duration("OUTGOING_BUFFER_MAX_AGE", 0)
+	now := time.Now()
+
+	for i, msg := range messages {
+		if msg.ExpiresAt != nil && now.After(*msg.ExpiresAt) || maxAge > 0 && now.Sub(msg.BufferedAt) > maxAge {
+			log.Debug().Str("topic", msg.Topic).Msg("dropping expired buffered message")
+			continue
+		}
+
+		var props *Properties
+		if msg.ResponseTopic != "" || msg.CorrelationData != nil || msg.ContentType != "" || msg.ExpiresAt != nil {
+			props = &Properties{
+				ResponseTopic: msg.ResponseTopic, CorrelationData: msg.CorrelationData, ContentType: msg.ContentType,
+			}
+			if msg.ExpiresAt != nil {
+				props.MessageExpiry = msg.ExpiresAt.Sub(now)
+			}
+		}
+
+		t := publish(b.Client, msg.Topic, msg.QoS, msg.Retained, msg.Payload, props)
+		if !t.WaitTimeout(b.timeout) {
+			return i, fmt.Errorf("timed out publishing to %s", msg.Topic)
+		}
+		if t.Error() != nil {
+			return i, fmt.Errorf("failed to publish to %s: %w", msg.Topic, t.Error())
+		}
+	}
+
+	return len(messages), nil
+}
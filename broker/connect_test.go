@@ -0,0 +1,65 @@
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/broker/brokertest"
+)
+
+// pendingToken is the token of a connection the broker never answers
+type pendingToken struct {
+	done chan struct{}
+}
+
+func (t pendingToken) Wait() bool            { <-t.done; return true }
+func (t pendingToken) Error() error          { return nil }
+func (t pendingToken) Done() <-chan struct{} { return t.done }
+
+func (t pendingToken) WaitTimeout(d time.Duration) bool {
+	select {
+	case <-t.done:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
+
+// unreachable is a client whose connection attempts never complete
+type unreachable struct {
+	*brokertest.Fake
+}
+
+func (unreachable) Connect() mqtt.Token {
+	return pendingToken{done: make(chan struct{})}
+}
+
+func TestConnectTimeout(t *testing.T) {
+	client := unreachable{brokertest.New()}
+
+	start := time.Now()
+	err := broker.Connect(client, 50*time.Millisecond)
+	if !errors.Is(err, broker.ErrConnectTimeout) {
+		t.Fatalf("Connect() error = %v, want %v", err, broker.ErrConnectTimeout)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Connect() returned after %v, want about the timeout", elapsed)
+	}
+}
+
+func TestConnectWithRetryGivesUp(t *testing.T) {
+	t.Setenv("MQTT_CONNECT_ATTEMPTS", "2")
+	t.Setenv("MQTT_CONNECT_BACKOFF", "1ms")
+
+	client := unreachable{brokertest.New()}
+
+	err := broker.ConnectWithRetry(context.Background(), client, 10*time.Millisecond)
+	if !errors.Is(err, broker.ErrConnectTimeout) {
+		t.Fatalf("ConnectWithRetry() error = %v, want %v", err, broker.ErrConnectTimeout)
+	}
+}
@@ -0,0 +1,87 @@
+package broker
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/config"
+)
+
+// Delivery is the QoS and retain flag the messages of a topic are published with
+type Delivery struct {
+	QoS      byte
+	Retained bool
+}
+
+// deliveries are the defaults of the topics the server publishes to, keyed by the setting naming the topic.
+// State is retained so that late subscribers get it, while acknowledgements and other transient messages
+// only matter to whoever is listening at the time
+var deliveries = map[string]Delivery{
+	"SERVER_STATE_TOPIC":            {QoS: 2, Retained: true},
+	"SERVER_CABINET_STATE_TOPIC":    {QoS: 2, Retained: true},
+	"SERVER_HELLO_TOPIC":            {QoS: 2, Retained: true},
+	"SERVER_WILL_TOPIC":             {QoS: 2, Retained: true},
+	"SERVER_STREAM_TOPIC":           {QoS: 2},
+	"SERVER_EVENTS_TOPIC":           {QoS: 1},
+	"SERVER_ACK_TOPIC":              {QoS: 1},
+	"SERVER_NACK_TOPIC":             {QoS: 1},
+	"SERVER_COMMAND_RESPONSE_TOPIC": {QoS: 1},
+	"ARDUINO_COMMAND_TOPIC":         {QoS: 1},
+	"DEAD_LETTER_TOPIC":             {QoS: 1},
+	"SERVER_FIRMWARE_TOPIC":         {QoS: 1, Retained: true},
+	"SERVER_FIRMWARE_CHUNK_TOPIC":   {QoS: 1},
+	"BRIDGE_TOPIC":                  {QoS: 1},
+	"DEVICE_CREDENTIALS_TOPIC":      {QoS: 1},
+}
+
+// DeliveryFor returns how to publish to the topic named by the setting, <SETTING>_QOS and <SETTING>_RETAINED
+// overriding its defaults. Topics without defaults are published with QoS 1 and not retained
+func DeliveryFor(setting string) Delivery {
+	d, ok := deliveries[setting]
+	if !ok {
+		d = Delivery{QoS: 1}
+	}
+
+	qos := config.Int(setting+"_QOS", int(d.QoS))
+	if qos < 0 || qos > 2 {
+		log.Warn().Int("qos", qos).Msgf("malformed %s_QOS, falling back to %d", setting, d.QoS)
+	} else {
+		d.QoS = byte(qos)
+	}
+
+	d.Retained = config.Bool(setting+"_RETAINED", d.Retained)
+
+	return d
+}
+
+// settings are the keys of deliveries in order, so that routeOf settles ties the same way every time
+var settings = func() []string {
+	keys := make([]string, 0, len(deliveries))
+	for setting := range deliveries {
+		keys = append(keys, setting)
+	}
+	sort.Strings(keys)
+
+	return keys
+}()
+
+// routeOf returns the setting naming the topic published to, as a label bounded whatever the devices and cabinets
+// filled into it. The topic belongs to the setting whose topic matches it or one of its subtopics, the most specific
+// setting winning, and to "other" when there is none
+func routeOf(topic string) string {
+	route, levels := "other", 0
+	for _, setting := range settings {
+		pattern := config.String(setting, "")
+		if pattern == "" {
+			continue
+		}
+
+		if _, ok := Match(pattern+"/#", topic); ok && strings.Count(pattern, "/")+1 > levels {
+			route, levels = setting, strings.Count(pattern, "/")+1
+		}
+	}
+
+	return route
+}
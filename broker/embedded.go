@@ -0,0 +1,113 @@
+package broker
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/config"
+)
+
+// Embedded reports whether the server runs the broker in-process, set by MQTT_EMBEDDED
+func Embedded() bool {
+	return config.Bool("MQTT_EMBEDDED", false)
+}
+
+// embeddedAddress is where the embedded broker listens for the devices. It defaults to the loopback interface,
+// so that the broker only takes connections from the outside once MQTT_EMBEDDED_ADDRESS says so
+func embeddedAddress() string {
+	return config.String("MQTT_EMBEDDED_ADDRESS", "127.0.0.1:1883")
+}
+
+// embeddedTLS reports whether the embedded broker serves TLS
+func embeddedTLS() bool {
+	return config.String("MQTT_EMBEDDED_CERT_FILE", "") != ""
+}
+
+// embeddedHost is the loopback address of the embedded broker the server connects to unless MQTT_HOST says otherwise
+func embeddedHost() (string, error) {
+	host, port, err := net.SplitHostPort(embeddedAddress())
+	if err != nil {
+		return "", fmt.Errorf("invalid MQTT_EMBEDDED_ADDRESS: %w", err)
+	}
+
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+// StartEmbedded starts the in-process broker if MQTT_EMBEDDED is set, so that small single-cabinet installations
+// do not need to run Mosquitto next to the server. The devices connect to its listener at MQTT_EMBEDDED_ADDRESS,
+// the loopback interface unless set otherwise, which serves TLS with MQTT_EMBEDDED_CERT_FILE and MQTT_EMBEDDED_KEY_FILE. Anyone may connect and use any topic,
+// unless MQTT_EMBEDDED_AUTH_FILE holds the mochi-mqtt auth ledger restricting it. The hooks let in the clients
+// the ledger does not know of, like the devices issued their own credentials. The server is nil when the broker
+// is not embedded, and is to be closed after the client has disconnected from it
+func StartEmbedded(hooks ...mochi.Hook) (*mochi.Server, error) {
+	if !Embedded() {
+		return nil, nil
+	}
+
+	logger := log.With().Str("component", "embedded broker").Logger()
+	server := mochi.New(&mochi.Options{Logger: &logger})
+
+	for _, hook := range hooks {
+		if err := server.AddHook(hook, nil); err != nil {
+			return nil, fmt.Errorf("failed to add embedded broker hook %s: %w", hook.ID(), err)
+		}
+	}
+
+	var err error
+	if path := config.String("MQTT_EMBEDDED_AUTH_FILE", ""); path != "" {
+		ledger, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read auth ledger: %w", readErr)
+		}
+
+		err = server.AddHook(new(auth.Hook), &auth.Options{Data: ledger})
+	} else {
+		log.Warn().Str("address", embeddedAddress()).
+			Msg("embedded broker lets anyone connect, set MQTT_EMBEDDED_AUTH_FILE to restrict it")
+		err = server.AddHook(new(auth.AllowHook), nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure embedded broker auth: %w", err)
+	}
+
+	var listenerCfg listeners.Config
+	certFile, keyFile := config.String("MQTT_EMBEDDED_CERT_FILE", ""), config.String("MQTT_EMBEDDED_KEY_FILE", "")
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("MQTT_EMBEDDED_CERT_FILE and MQTT_EMBEDDED_KEY_FILE must be set together")
+	}
+
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load embedded broker certificate: %w", err)
+		}
+
+		listenerCfg.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{cert}}
+	}
+
+	err = server.AddListener(listeners.NewTCP("tcp", embeddedAddress(), &listenerCfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen at %s: %w", embeddedAddress(), err)
+	}
+
+	// Serve only starts the listeners and the event loop in the background
+	if err := server.Serve(); err != nil {
+		return nil, fmt.Errorf("failed to start embedded broker: %w", err)
+	}
+
+	log.Info().Str("address", embeddedAddress()).Bool("tls", embeddedTLS()).Msg("Started embedded broker")
+
+	return server, nil
+}
@@ -0,0 +1,118 @@
+package broker
+
+import (
+	"hash/fnv"
+	"sync"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/metrics"
+)
+
+const (
+	// OverflowBlock makes the client wait for room in a full queue, applying backpressure to the broker
+	OverflowBlock = "block"
+	// OverflowDrop drops messages finding their queue full, leaving them unacknowledged for a redelivery.
+	// It requires MQTT_MANUAL_ACK, as otherwise the client acknowledges the dropped messages and they are lost
+	OverflowDrop = "drop"
+)
+
+type task struct {
+	route   string
+	client  mqtt.Client
+	msg     mqtt.Message
+	handler mqtt.MessageHandler
+}
+
+// Pool moves the handling of messages off the client goroutine onto a fixed set of workers, each with a bounded
+// queue. Messages with the same key always go to the same worker, so they are handled in the order they arrived
+type Pool struct {
+	mu       sync.RWMutex
+	closed   bool
+	queues   []chan task
+	overflow string
+	wg       sync.WaitGroup
+}
+
+// NewPool starts the workers, each queueing up to size messages, which overflow according to mode.
+// Without manual acks the messages are never dropped, see OverflowDrop
+func NewPool(workers, size int, mode string) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+
+	if mode == OverflowDrop && !ManualAck() {
+		log.Warn().Msgf("overflow mode %s requires MQTT_MANUAL_ACK, blocking on full queues instead", OverflowDrop)
+		mode = OverflowBlock
+	}
+
+	p := &Pool{queues: make([]chan task, workers), overflow: mode}
+	for i := range p.queues {
+		p.queues[i] = make(chan task, size)
+
+		p.wg.Add(1)
+		go p.work(p.queues[i])
+	}
+
+	return p
+}
+
+func (p *Pool) work(queue <-chan task) {
+	defer p.wg.Done()
+
+	for t := range queue {
+		metrics.WorkerQueued.WithLabelValues(t.route).Dec()
+		t.handler(t.client, t.msg)
+	}
+}
+
+// Middleware queues the messages of the route to the worker picked by their key. It has to wrap the recovery,
+// which only catches panics of the goroutine running the handler
+func (p *Pool) Middleware(key func(msg mqtt.Message) string) Middleware {
+	return func(route string, next mqtt.MessageHandler) mqtt.MessageHandler {
+		return func(client mqtt.Client, msg mqtt.Message) {
+			p.mu.RLock()
+			defer p.mu.RUnlock()
+
+			if p.closed {
+				log.Warn().Str("route", route).Str("topic", msg.Topic()).Msg("dropped message received while draining")
+				return
+			}
+
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(key(msg)))
+			queue := p.queues[h.Sum32()%uint32(len(p.queues))]
+
+			queued := metrics.WorkerQueued.WithLabelValues(route)
+			queued.Inc()
+
+			t := task{route: route, client: client, msg: msg, handler: next}
+			select {
+			case queue <- t:
+			default:
+				metrics.WorkerOverflows.WithLabelValues(route, p.overflow).Inc()
+
+				if p.overflow == OverflowDrop {
+					queued.Dec()
+					log.Warn().Str("route", route).Str("topic", msg.Topic()).Msg("dropped message, worker queue is full")
+					return
+				}
+
+				queue <- t
+			}
+		}
+	}
+}
+
+// Close stops accepting messages and waits for the workers to handle the queued ones
+func (p *Pool) Close() {
+	p.mu.Lock()
+	p.closed = true
+	for _, queue := range p.queues {
+		close(queue)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
@@ -0,0 +1,80 @@
+package broker_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/broker/brokertest"
+	"letovo-computers-server/metrics"
+)
+
+func TestPoolDropRequiresManualAck(t *testing.T) {
+	tests := []struct {
+		name      string
+		manualAck string
+		mode      string
+		handled   int32
+	}{
+		{name: "manual ack", manualAck: "true", mode: broker.OverflowDrop, handled: 2},
+		{name: "auto ack", manualAck: "false", mode: broker.OverflowBlock, handled: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MQTT_MANUAL_ACK", tt.manualAck)
+			route := "test/pool/" + tt.name
+
+			pool := broker.NewPool(1, 1, broker.OverflowDrop)
+
+			started := make(chan struct{}, 3)
+			release := make(chan struct{})
+			var handled int32
+			handler := pool.Middleware(func(mqtt.Message) string { return "" })(route, func(mqtt.Client, mqtt.Message) {
+				started <- struct{}{}
+				<-release
+				atomic.AddInt32(&handled, 1)
+			})
+
+			client := brokertest.New()
+			deliver := func() { handler(client, brokertest.NewMessage(route, nil, 1, false)) }
+
+			// the first message keeps the worker busy and the second fills its queue
+			deliver()
+			<-started
+			deliver()
+
+			overflowed := make(chan struct{})
+			go func() {
+				deliver()
+				close(overflowed)
+			}()
+
+			select {
+			case <-overflowed:
+				if tt.mode != broker.OverflowDrop {
+					t.Error("the overflowing message did not wait for room in the queue")
+				}
+			case <-time.After(50 * time.Millisecond):
+				if tt.mode == broker.OverflowDrop {
+					t.Error("the overflowing message was not dropped")
+				}
+			}
+
+			close(release)
+			<-overflowed
+			pool.Close()
+
+			if got := atomic.LoadInt32(&handled); got != tt.handled {
+				t.Errorf("handled %d messages, want %d", got, tt.handled)
+			}
+			if got := testutil.ToFloat64(metrics.WorkerOverflows.WithLabelValues(route, tt.mode)); got != 1 {
+				t.Errorf("overflows in %s mode = %g, want 1", tt.mode, got)
+			}
+		})
+	}
+}
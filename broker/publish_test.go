@@ -0,0 +1,110 @@
+package broker_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/broker/brokertest"
+)
+
+// failedToken is the token of a publish the broker refused
+type failedToken struct {
+	pendingToken
+	err error
+}
+
+func (t failedToken) Error() error { return t.err }
+
+// flaky is a client refusing its first few publishes, as many as its failures
+type flaky struct {
+	*brokertest.Fake
+
+	mu       sync.Mutex
+	failures int
+	attempts int
+}
+
+func (f *flaky) Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token {
+	f.mu.Lock()
+	f.attempts++
+	fail := f.attempts <= f.failures
+	f.mu.Unlock()
+
+	if fail {
+		done := make(chan struct{})
+		close(done)
+
+		return failedToken{pendingToken: pendingToken{done: done}, err: errors.New("connection lost")}
+	}
+
+	return f.Fake.Publish(topic, qos, retained, payload)
+}
+
+func TestPublishRetries(t *testing.T) {
+	t.Setenv("MQTT_PUBLISH_ATTEMPTS", "3")
+	t.Setenv("MQTT_PUBLISH_BACKOFF", "1ms")
+	spool := filepath.Join(t.TempDir(), "spool.jsonl")
+	t.Setenv("PUBLISH_SPOOL_FILE", spool)
+
+	client := &flaky{Fake: brokertest.New(), failures: 1}
+
+	var wg sync.WaitGroup
+	broker.Publish(&wg, client, "server/stream", broker.Delivery{QoS: 1}, "hi")
+	wg.Wait()
+
+	published := client.Published("server/stream")
+	if client.attempts != 2 || len(published) != 1 || string(published[0].Payload()) != "hi" {
+		t.Errorf("published %d messages in %d attempts, want hi published on the second", len(published), client.attempts)
+	}
+
+	if _, err := os.Stat(spool); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("spooled the published message, stat error = %v", err)
+	}
+}
+
+func TestPublishSpoolsAfterAttempts(t *testing.T) {
+	t.Setenv("MQTT_PUBLISH_ATTEMPTS", "3")
+	t.Setenv("MQTT_PUBLISH_BACKOFF", "1ms")
+	spool := filepath.Join(t.TempDir(), "spool.jsonl")
+	t.Setenv("PUBLISH_SPOOL_FILE", spool)
+
+	client := &flaky{Fake: brokertest.New(), failures: 3}
+
+	var wg sync.WaitGroup
+	broker.Publish(&wg, client, "server/stream", broker.Delivery{QoS: 1}, "hi")
+	wg.Wait()
+
+	if client.attempts != 3 || len(client.Published("server/stream")) != 0 {
+		t.Fatalf("published %d messages in %d attempts, want none in 3", len(client.Published("server/stream")), client.attempts)
+	}
+
+	b, err := os.ReadFile(spool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("spooled %d messages, want 1", len(lines))
+	}
+
+	var spooled struct {
+		Topic   string `json:"topic"`
+		Payload string `json:"payload"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &spooled); err != nil {
+		t.Fatal(err)
+	}
+	if spooled.Topic != "server/stream" || spooled.Payload != "hi" || spooled.Error != "connection lost" {
+		t.Errorf("spooled %+v, want hi to server/stream failing with connection lost", spooled)
+	}
+}
@@ -0,0 +1,210 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrNoResponse is returned by Request when no response matches the request before the context is done
+var ErrNoResponse = errors.New("no response")
+
+// Response is the message answering a Request
+type Response struct {
+	Topic         string
+	Payload       []byte
+	CorrelationID string
+}
+
+// responder is the subscription to a response topic, shared by the requests waiting on it
+type responder struct {
+	refs    int
+	pending map[string]chan Response
+}
+
+var (
+	// respondersMu serializes subscribing to and unsubscribing from the response topics,
+	// while pendingMu only guards the waiting requests, so that the responses are matched meanwhile
+	respondersMu sync.Mutex
+	pendingMu    sync.Mutex
+	responders   = make(map[string]*responder)
+)
+
+// Request publishes the payload to the request topic and waits for the response on the response topic until ctx
+// is done, returning ErrNoResponse then. The request carries a correlation ID, as the MQTT 5 correlation data
+// along with the response topic, and as the id field of a JSON object payload for the older protocol, which
+// the response must echo the same way.
+//
+// The response topic is subscribed to for as long as requests wait on it, so it must not be one of the routes,
+// whose subscription it would replace
+func Request(ctx context.Context, client Broker, reqTopic, respTopic string, payload []byte) (Response, error) {
+	id, err := correlationID()
+	if err != nil {
+		return Response{}, err
+	}
+
+	payload = withID(payload, id)
+
+	responses := make(chan Response, 1)
+	if err := await(ctx, client, respTopic, id, responses); err != nil {
+		return Response{}, err
+	}
+	defer release(client, respTopic, id)
+
+	props := Properties{CorrelationData: []byte(id)}
+	if !strings.ContainsAny(respTopic, "+#") {
+		props.ResponseTopic = respTopic
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		props.MessageExpiry = time.Until(deadline)
+	}
+
+	// requests are pointless once nobody waits for their response, so they are never retained
+	t := PublishWithProperties(client, reqTopic, 1, false, payload, props)
+	select {
+	case <-t.Done():
+		if t.Error() != nil {
+			return Response{}, fmt.Errorf("failed to publish to %s: %w", reqTopic, t.Error())
+		}
+	case <-ctx.Done():
+		return Response{}, fmt.Errorf("%w on %s: %s", ErrNoResponse, respTopic, ctx.Err())
+	}
+
+	select {
+	case response := <-responses:
+		return response, nil
+	case <-ctx.Done():
+		return Response{}, fmt.Errorf("%w on %s: %s", ErrNoResponse, respTopic, ctx.Err())
+	}
+}
+
+// await registers the request, subscribing to the response topic for the first request waiting on it
+func await(ctx context.Context, client Broker, respTopic, id string, responses chan Response) error {
+	respondersMu.Lock()
+	defer respondersMu.Unlock()
+
+	r, ok := responders[respTopic]
+	if !ok {
+		r = &responder{pending: make(map[string]chan Response)}
+
+		pendingMu.Lock()
+		responders[respTopic] = r
+		pendingMu.Unlock()
+
+		if err := SubscribeSync(ctx, client, respTopic, 1, respond(respTopic)); err != nil {
+			pendingMu.Lock()
+			delete(responders, respTopic)
+			pendingMu.Unlock()
+
+			return err
+		}
+	}
+
+	pendingMu.Lock()
+	r.refs++
+	r.pending[id] = responses
+	pendingMu.Unlock()
+
+	return nil
+}
+
+// release unregisters the request, unsubscribing from the response topic once no request waits on it
+func release(client Broker, respTopic, id string) {
+	respondersMu.Lock()
+	defer respondersMu.Unlock()
+
+	pendingMu.Lock()
+	r := responders[respTopic]
+	r.refs--
+	delete(r.pending, id)
+	last := r.refs == 0
+	if last {
+		delete(responders, respTopic)
+	}
+	pendingMu.Unlock()
+
+	if last {
+		Unsubscribe(client, respTopic)
+	}
+}
+
+// respond hands the responses on the topic over to the requests they correlate with
+func respond(respTopic string) mqtt.MessageHandler {
+	return func(_ mqtt.Client, msg mqtt.Message) {
+		id := responseID(msg)
+
+		pendingMu.Lock()
+		var responses chan Response
+		if r, ok := responders[respTopic]; ok {
+			responses = r.pending[id]
+		}
+		pendingMu.Unlock()
+
+		if responses == nil {
+			log.Debug().Str("topic", msg.Topic()).Str("id", id).Msg("received response to unknown or expired request")
+			return
+		}
+
+		select {
+		case responses <- Response{Topic: msg.Topic(), Payload: msg.Payload(), CorrelationID: id}:
+		default:
+			// a duplicate delivery of the response
+		}
+	}
+}
+
+// responseID returns the correlation ID of the response, from its MQTT 5 correlation data or its JSON id field
+func responseID(msg mqtt.Message) string {
+	if props, ok := MessageProperties(msg); ok && len(props.CorrelationData) > 0 {
+		return string(props.CorrelationData)
+	}
+
+	var response struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(msg.Payload(), &response); err != nil {
+		return ""
+	}
+
+	return response.ID
+}
+
+// withID sets the id field of a JSON object payload, leaving any other payload as it is
+func withID(payload []byte, id string) []byte {
+	if !bytes.HasPrefix(bytes.TrimSpace(payload), []byte("{")) {
+		return payload
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return payload
+	}
+
+	fields["id"], _ = json.Marshal(id)
+
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return payload
+	}
+
+	return b
+}
+
+func correlationID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate correlation id: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,274 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/config"
+	"letovo-computers-server/metrics"
+)
+
+// Middleware wraps the handler of a route, named after the setting holding its topic pattern
+type Middleware func(route string, next mqtt.MessageHandler) mqtt.MessageHandler
+
+type route struct {
+	handler mqtt.MessageHandler
+	shared  bool
+}
+
+// Router subscribes handlers to the topic patterns named by settings, so that they can be resubscribed
+// when the settings are reloaded
+type Router struct {
+	middleware []Middleware
+	routes     map[string]route
+
+	mu       sync.Mutex
+	inflight int
+	// idle is closed once the handlers running when draining started have returned
+	idle chan struct{}
+}
+
+// NewRouter creates a router wrapping every handler in the middleware, the first one being the outermost
+func NewRouter(middleware ...Middleware) *Router {
+	return &Router{middleware: middleware, routes: make(map[string]route)}
+}
+
+// SharedTopic returns the shared subscription to the topic of the MQTT_SHARE_GROUP, for the broker to split
+// the messages between the server instances of the group. It is the topic itself when no group is configured
+func SharedTopic(topic string) string {
+	group := config.String("MQTT_SHARE_GROUP", "")
+	if group == "" {
+		return topic
+	}
+
+	return "$share/" + group + "/" + topic
+}
+
+func (r route) topic(topic string) string {
+	if r.shared {
+		return SharedTopic(topic)
+	}
+
+	return topic
+}
+
+// Handle registers the handler for the topic pattern held by the setting, like ARDUINO_STREAM_TOPIC.
+// The middleware of the route wraps the middleware of the router
+func (r *Router) Handle(setting string, handler mqtt.MessageHandler, middleware ...Middleware) {
+	r.handle(setting, handler, false, middleware)
+}
+
+// HandleShared registers the handler like Handle, subscribing to the topic through the MQTT_SHARE_GROUP.
+// Each message then reaches a single instance, whichever the broker picks, so the handler must not rely
+// on having seen the previous messages of a device
+func (r *Router) HandleShared(setting string, handler mqtt.MessageHandler, middleware ...Middleware) {
+	r.handle(setting, handler, true, middleware)
+}
+
+func (r *Router) handle(setting string, handler mqtt.MessageHandler, shared bool, middleware []Middleware) {
+	middleware = append(middleware, r.middleware...)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](setting, handler)
+	}
+
+	r.routes[setting] = route{handler: r.track(handler), shared: shared}
+}
+
+// Route returns the setting of the route whose topic pattern matches the topic, if any
+func (r *Router) Route(topic string) (string, bool) {
+	for setting := range r.routes {
+		pattern := config.String(setting, "")
+		if pattern == "" {
+			continue
+		}
+
+		if _, ok := Match(pattern, topic); ok {
+			return setting, true
+		}
+	}
+
+	return "", false
+}
+
+// track counts the running handlers for Drain to wait for
+func (r *Router) track(next mqtt.MessageHandler) mqtt.MessageHandler {
+	return func(client mqtt.Client, msg mqtt.Message) {
+		r.mu.Lock()
+		r.inflight++
+		r.mu.Unlock()
+
+		defer func() {
+			r.mu.Lock()
+			r.inflight--
+			if r.inflight == 0 && r.idle != nil {
+				close(r.idle)
+				r.idle = nil
+			}
+			r.mu.Unlock()
+		}()
+
+		next(client, msg)
+	}
+}
+
+// Subscribe subscribes the routes whose settings name a topic, skipping the others. It fails on the first
+// subscription the broker does not grant before ctx is done. The messages a resumed session delivered
+// before subscribing are handled first, in the order they arrived
+func (r *Router) Subscribe(ctx context.Context, client Broker) error {
+	r.handleHeld(client, false)
+
+	settings := make([]string, 0, len(r.routes))
+	for setting := range r.routes {
+		settings = append(settings, setting)
+	}
+	sort.Strings(settings)
+
+	for _, setting := range settings {
+		if topic := config.String(setting, ""); topic != "" {
+			rt := r.routes[setting]
+			if err := SubscribeSync(ctx, client, rt.topic(topic), 2, rt.handler); err != nil {
+				return err
+			}
+		}
+	}
+
+	// messages may have slipped in while the subscriptions were being made
+	r.handleHeld(client, true)
+
+	return nil
+}
+
+// handleHeld passes the messages delivered before subscribing to their routes. Once released, the messages
+// matching no route are dropped instead of being held
+func (r *Router) handleHeld(client Broker, release bool) {
+	c, ok := client.(mqtt.Client)
+
+	for _, msg := range held.take(release) {
+		setting, routed := r.Route(msg.Topic())
+		if !ok || !routed {
+			log.Warn().Str("topic", msg.Topic()).Msg("dropped message delivered before subscribing, no route matches")
+			continue
+		}
+
+		r.routes[setting].handler(c, msg)
+	}
+}
+
+// Resubscribe moves the route of the changed setting from its old topic to the new one, ignoring settings
+// which belong to no route
+func (r *Router) Resubscribe(ctx context.Context, client Broker, change config.Change) error {
+	rt, ok := r.routes[change.Key]
+	if !ok {
+		return nil
+	}
+
+	if change.Old != "" {
+		Unsubscribe(client, rt.topic(change.Old))
+	}
+
+	if change.New != "" {
+		return SubscribeSync(ctx, client, rt.topic(change.New), 2, rt.handler)
+	}
+
+	return nil
+}
+
+// Drain unsubscribes the routes and waits for the running handlers to return until ctx is done. Handlers handing
+// the messages over to a Pool return once they are queued, so the pool is to be closed after draining
+func (r *Router) Drain(ctx context.Context, client Broker) error {
+	for setting, rt := range r.routes {
+		topic := config.String(setting, "")
+		if topic == "" {
+			continue
+		}
+
+		t := Unsubscribe(client, rt.topic(topic))
+		select {
+		case <-t.Done():
+			if t.Error() != nil {
+				log.Warn().Err(t.Error()).Str("topic", topic).Msg("failed to unsubscribe while draining")
+			}
+		case <-ctx.Done():
+			return fmt.Errorf("failed to unsubscribe from %s: %w", topic, ctx.Err())
+		}
+	}
+
+	r.mu.Lock()
+	if r.inflight == 0 {
+		r.mu.Unlock()
+		return nil
+	}
+
+	idle := make(chan struct{})
+	r.idle = idle
+	r.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		r.mu.Lock()
+		n := r.inflight
+		r.mu.Unlock()
+
+		return fmt.Errorf("%d handlers still running: %w", n, ctx.Err())
+	}
+}
+
+// Recovery keeps a panicking handler from taking down the client, logging the panic instead
+func Recovery(route string, next mqtt.MessageHandler) mqtt.MessageHandler {
+	return func(client mqtt.Client, msg mqtt.Message) {
+		defer func() {
+			if p := recover(); p != nil {
+				metrics.MessagePanics.WithLabelValues(route).Inc()
+				log.Error().
+					Str("route", route).
+					Str("topic", msg.Topic()).
+					Interface("panic", p).
+					Bytes("stack", debug.Stack()).
+					Msg("recovered from panic in message handler")
+			}
+		}()
+
+		next(client, msg)
+	}
+}
+
+// Metrics counts the received messages and observes how long they take to handle
+func Metrics(route string, next mqtt.MessageHandler) mqtt.MessageHandler {
+	return func(client mqtt.Client, msg mqtt.Message) {
+		metrics.ObserveMessage(route, msg.Retained())
+
+		start := time.Now()
+		defer func() {
+			metrics.MessageDuration.WithLabelValues(route).Observe(time.Since(start).Seconds())
+		}()
+
+		next(client, msg)
+	}
+}
+
+// Logging logs every handled message at the debug level
+func Logging(route string, next mqtt.MessageHandler) mqtt.MessageHandler {
+	return func(client mqtt.Client, msg mqtt.Message) {
+		start := time.Now()
+		next(client, msg)
+
+		log.Debug().
+			Str("route", route).
+			Str("topic", msg.Topic()).
+			Uint16("id", msg.MessageID()).
+			Uint8("qos", msg.Qos()).
+			Bool("retained", msg.Retained()).
+			Bool("duplicate", msg.Duplicate()).
+			Dur("took", time.Since(start)).
+			Msg("handled message")
+	}
+}
@@ -0,0 +1,118 @@
+package broker
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/eclipse/paho.golang/paho"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/config"
+)
+
+// CleanSession reports whether the broker discards the session of the server once it disconnects, set by
+// MQTT_CLEAN_SESSION. Turning it off has the broker queue the QoS 1 and 2 messages matching the subscriptions
+// of the server while it is restarting, and deliver them once it reconnects with the same MQTT_CLIENT_ID
+func CleanSession() bool {
+	return config.Bool("MQTT_CLEAN_SESSION", true)
+}
+
+// sessionExpiry is how long an MQTT 5 broker keeps the session once the server disconnects, MQTT_SESSION_EXPIRY.
+// MQTT 3.1.1 brokers keep it for as long as they are configured to
+func sessionExpiry() time.Duration {
+	return config.Duration("MQTT_SESSION_EXPIRY", time.Hour)
+}
+
+// held keeps the messages a resumed session delivers as soon as the server connects, before the routes
+// are subscribed and the clients know where to send them, so that the router can handle them once ready
+var held = &holder{}
+
+type holder struct {
+	mu       sync.Mutex
+	messages []mqtt.Message
+	released bool
+}
+
+// hold keeps the message until the routes are ready, dropping it once they are or the backlog is full
+func (h *holder) hold(msg mqtt.Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch {
+	case h.released:
+		log.Debug().Str("topic", msg.Topic()).Msg("dropped message matching no route")
+	case len(h.messages) >= config.Int("MQTT_SESSION_BACKLOG", 1000):
+		log.Warn().Str("topic", msg.Topic()).Msg("dropped message delivered before subscribing, the backlog is full")
+	default:
+		h.messages = append(h.messages, msg)
+	}
+}
+
+// take removes the messages held so far, marking the routes ready when released is set
+func (h *holder) take(release bool) []mqtt.Message {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	messages := h.messages
+	h.messages = nil
+	h.released = h.released || release
+
+	return messages
+}
+
+// warnSessionClientID warns about persistent sessions without a client ID, as brokers assign a new one
+// on every connection, starting a new session each time
+func warnSessionClientID(clientID string) {
+	if !CleanSession() && strings.TrimSpace(clientID) == "" {
+		log.Warn().Msg("MQTT_CLEAN_SESSION is off but MQTT_CLIENT_ID is not set, so the session cannot be resumed")
+	}
+}
+
+// sessionRouter hands the messages of a resumed session arriving before their subscription over to held,
+// where the standard router would drop them
+type sessionRouter struct {
+	*paho.StandardRouter
+	client *v5Client
+}
+
+func (r sessionRouter) Route(pb *packets.Publish) {
+	if pb.Properties == nil || pb.Properties.TopicAlias == nil {
+		if !r.client.subscribed(pb.Topic) {
+			held.hold(&v5Message{publish: paho.PublishFromPacketPublish(pb)})
+			return
+		}
+	}
+
+	r.StandardRouter.Route(pb)
+}
+
+// subscribed reports whether one of the subscriptions of the client matches the topic
+func (c *v5Client) subscribed(topic string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for subscription := range c.subscriptions {
+		if _, ok := Match(unshared(subscription), topic); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unshared strips the $share/<group>/ prefix of a shared subscription
+func unshared(subscription string) string {
+	if !strings.HasPrefix(subscription, "$share/") {
+		return subscription
+	}
+
+	parts := strings.SplitN(subscription, "/", 3)
+	if len(parts) < 3 {
+		return subscription
+	}
+
+	return parts[2]
+}
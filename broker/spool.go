@@ -0,0 +1,56 @@
+package broker
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/config"
+)
+
+var spoolMu sync.Mutex
+
+// spooledMessage is a line of the PUBLISH_SPOOL_FILE
+type spooledMessage struct {
+	Topic     string    `json:"topic"`
+	Payload   string    `json:"payload"`
+	Error     string    `json:"error"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// spool appends the message that could not be published to the PUBLISH_SPOOL_FILE, if it is configured
+func spool(topic, payload string, publishErr error) {
+	path := config.String("PUBLISH_SPOOL_FILE", "")
+	if path == "" {
+		return
+	}
+
+	line, err := json.Marshal(spooledMessage{
+		Topic:     topic,
+		Payload:   payload,
+		Error:     publishErr.Error(),
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to marshal spooled message")
+		return
+	}
+
+	spoolMu.Lock()
+	defer spoolMu.Unlock()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to open publish spool")
+		return
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to spool message")
+	}
+}
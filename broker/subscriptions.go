@@ -0,0 +1,47 @@
+package broker
+
+import (
+	"sync"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+const (
+	SubscriptionPending = "pending"
+	SubscriptionActive  = "active"
+	SubscriptionFailed  = "failed"
+)
+
+var (
+	subscriptions   = make(map[string]string)
+	subscriptionsMu sync.Mutex
+)
+
+func setSubscription(topic, state string) {
+	subscriptionsMu.Lock()
+	defer subscriptionsMu.Unlock()
+
+	subscriptions[topic] = state
+}
+
+// Subscriptions reports the state of every topic subscribed to with SubscribeSync
+func Subscriptions() map[string]string {
+	subscriptionsMu.Lock()
+	defer subscriptionsMu.Unlock()
+
+	states := make(map[string]string, len(subscriptions))
+	for topic, state := range subscriptions {
+		states[topic] = state
+	}
+
+	return states
+}
+
+// Unsubscribe unsubscribes from the topic and stops reporting its state, returning the token of the request
+func Unsubscribe(client Broker, topic string) mqtt.Token {
+	subscriptionsMu.Lock()
+	delete(subscriptions, topic)
+	subscriptionsMu.Unlock()
+
+	return client.Unsubscribe(topic)
+}
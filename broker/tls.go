@@ -0,0 +1,55 @@
+package broker
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/config"
+)
+
+// TLSConfig builds the TLS settings of the connection to a broker from the settings starting with the prefix,
+// MQTT for the broker the server relies on. <prefix>_CA_FILE replaces the system roots with the CA bundle the broker
+// certificate is verified against, and <prefix>_CERT_FILE with <prefix>_KEY_FILE present a client certificate
+// to brokers requiring mutual TLS. <prefix>_INSECURE_SKIP_VERIFY disables the verification of the broker
+// certificate for lab setups
+func TLSConfig(prefix string) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.Bool(prefix+"_INSECURE_SKIP_VERIFY", false),
+	}
+	if cfg.InsecureSkipVerify {
+		log.Warn().Str("prefix", prefix).Msg("not verifying the broker certificate")
+	}
+
+	if path := os.Getenv(prefix + "_CA_FILE"); path != "" {
+		bundle, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificates found in %s", path)
+		}
+	}
+
+	certFile, keyFile := os.Getenv(prefix+"_CERT_FILE"), os.Getenv(prefix+"_KEY_FILE")
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("%s_CERT_FILE and %s_KEY_FILE must be set together", prefix, prefix)
+	}
+
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
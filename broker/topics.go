@@ -0,0 +1,60 @@
+package broker
+
+import "strings"
+
+// Match reports whether the topic matches the subscription pattern, returning the levels of the topic matched by
+// the single-level wildcards of the pattern, in order. A multi-level wildcard matches the rest of the topic
+func Match(pattern, topic string) ([]string, bool) {
+	patternLevels := strings.Split(pattern, "/")
+	topicLevels := strings.Split(topic, "/")
+
+	var wildcards []string
+	for i, level := range patternLevels {
+		if level == "#" {
+			return wildcards, true
+		}
+
+		if i >= len(topicLevels) {
+			return nil, false
+		}
+
+		switch level {
+		case "+":
+			wildcards = append(wildcards, topicLevels[i])
+		case topicLevels[i]:
+		default:
+			return nil, false
+		}
+	}
+
+	if len(patternLevels) != len(topicLevels) {
+		return nil, false
+	}
+
+	return wildcards, true
+}
+
+// CabinetID returns the level of the topic matched by the first single-level wildcard of the pattern, so that
+// the devices of many cabinets can publish to topics like cabinets/+/stream. It is empty for plain topics
+func CabinetID(pattern, topic string) string {
+	wildcards, ok := Match(pattern, topic)
+	if !ok || len(wildcards) == 0 {
+		return ""
+	}
+
+	return wildcards[0]
+}
+
+// Fill returns the topic of the pattern whose first single-level wildcard is the level, the reverse of CabinetID.
+// The level is appended to patterns without a wildcard
+func Fill(pattern, level string) string {
+	levels := strings.Split(pattern, "/")
+	for i := range levels {
+		if levels[i] == "+" {
+			levels[i] = level
+			return strings.Join(levels, "/")
+		}
+	}
+
+	return strings.TrimSuffix(pattern, "/") + "/" + level
+}
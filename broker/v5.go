@@ -0,0 +1,426 @@
+package broker
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/config"
+)
+
+// Properties are the MQTT 5 properties of a message. They are dropped when connected with an older protocol version
+type Properties struct {
+	// ResponseTopic is where the receiver should publish its response to
+	ResponseTopic string
+	// CorrelationData is echoed back in the response, matching it with the request
+	CorrelationData []byte
+	// MessageExpiry makes the broker discard the message if it is not delivered in time, zero meaning never
+	MessageExpiry time.Duration
+	// ContentType is the MIME type of the payload, like application/json
+	ContentType string
+}
+
+// PublishWithProperties publishes the payload with the MQTT 5 properties if the client speaks MQTT 5,
+// falling back to a plain publish otherwise
+func PublishWithProperties(client Broker, topic string, qos byte, retained bool, payload interface{}, props Properties) mqtt.Token {
+	if b, ok := client.(*buffer); ok {
+		return observePublish(topic, b.publish(topic, qos, retained, payload, &props))
+	}
+
+	return observePublish(topic, publish(client, topic, qos, retained, payload, &props))
+}
+
+// publish publishes the payload with the properties, if any, through the MQTT 5 client
+func publish(client Broker, topic string, qos byte, retained bool, payload interface{}, props *Properties) mqtt.Token {
+	if c, ok := client.(*v5Client); ok {
+		return c.publish(topic, qos, retained, payload, props)
+	}
+
+	return client.Publish(topic, qos, retained, payload)
+}
+
+// MessageProperties returns the MQTT 5 properties of a received message, reporting whether it has any
+func MessageProperties(msg mqtt.Message) (Properties, bool) {
+	m, ok := msg.(*v5Message)
+	if !ok || m.publish.Properties == nil {
+		return Properties{}, false
+	}
+
+	props := Properties{
+		ResponseTopic:   m.publish.Properties.ResponseTopic,
+		CorrelationData: m.publish.Properties.CorrelationData,
+		ContentType:     m.publish.Properties.ContentType,
+	}
+	if expiry := m.publish.Properties.MessageExpiry; expiry != nil {
+		props.MessageExpiry = time.Duration(*expiry) * time.Second
+	}
+
+	return props, true
+}
+
+// ReasonCodeError is returned when the broker refuses a packet with an MQTT 5 reason code
+type ReasonCodeError struct {
+	Code   byte
+	Reason string
+}
+
+func (e *ReasonCodeError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("broker refused with reason code 0x%02x: %s", e.Code, e.Reason)
+	}
+
+	return fmt.Sprintf("broker refused with reason code 0x%02x", e.Code)
+}
+
+// token implements mqtt.Token for the requests of the MQTT 5 client and the outgoing buffer
+type token struct {
+	done chan struct{}
+	err  error
+}
+
+func newToken(f func() error) *token {
+	t := &token{done: make(chan struct{})}
+
+	go func() {
+		defer close(t.done)
+
+		t.err = f()
+	}()
+
+	return t
+}
+
+func (t *token) Wait() bool {
+	<-t.done
+
+	return true
+}
+
+func (t *token) WaitTimeout(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-t.done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func (t *token) Done() <-chan struct{} {
+	return t.done
+}
+
+func (t *token) Error() error {
+	select {
+	case <-t.done:
+		return t.err
+	default:
+		return nil
+	}
+}
+
+// v5Message implements mqtt.Message over an MQTT 5 publish
+type v5Message struct {
+	publish *paho.Publish
+}
+
+func (m *v5Message) Duplicate() bool   { return false }
+func (m *v5Message) Qos() byte         { return m.publish.QoS }
+func (m *v5Message) Retained() bool    { return m.publish.Retain }
+func (m *v5Message) Topic() string     { return m.publish.Topic }
+func (m *v5Message) MessageID() uint16 { return m.publish.PacketID }
+func (m *v5Message) Payload() []byte   { return m.publish.Payload }
+
+// Ack is a no-op, the MQTT 5 client acknowledges the messages once their handler returns
+func (m *v5Message) Ack() {}
+
+// v5Client implements mqtt.Client over the MQTT 5 connection manager of paho.golang, which keeps reconnecting
+// to the brokers on its own. The handlers are registered by topic filter and resubscribed on every reconnection,
+// since the sessions start clean
+type v5Client struct {
+	cfg    autopaho.ClientConfig
+	router *paho.StandardRouter
+
+	start     sync.Once
+	cm        atomic.Pointer[autopaho.ConnectionManager]
+	connected atomic.Bool
+
+	mu            sync.Mutex
+	subscriptions map[string]byte
+}
+
+func newV5Client(addresses []string, tlsCfg *tls.Config, onConnect func(client Broker)) (*v5Client, error) {
+	c := &v5Client{router: paho.NewStandardRouter(), subscriptions: make(map[string]byte)}
+
+	for _, address := range addresses {
+		u, err := url.Parse(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid broker address %s: %w", address, err)
+		}
+
+		c.cfg.BrokerUrls = append(c.cfg.BrokerUrls, u)
+	}
+
+	if ManualAck() {
+		log.Warn().Msg("MQTT_MANUAL_ACK has no effect with MQTT 5, messages are acknowledged once handled")
+	}
+
+	c.cfg.TlsCfg = tlsCfg
+	c.cfg.KeepAlive = uint16(config.Duration("MQTT_KEEP_ALIVE", 30*time.Second) / time.Second)
+	c.cfg.ConnectRetryDelay = config.Duration("MQTT_RECONNECT_INTERVAL", time.Second)
+	c.cfg.ClientID = os.Getenv("MQTT_CLIENT_ID")
+	c.cfg.Router = c.router
+	warnSessionClientID(c.cfg.ClientID)
+	if !CleanSession() {
+		c.cfg.Router = sessionRouter{StandardRouter: c.router, client: c}
+
+		expiry := uint32(sessionExpiry() / time.Second)
+		c.cfg.SetConnectPacketConfigurator(func(cp *paho.Connect) *paho.Connect {
+			cp.CleanStart = false
+			if cp.Properties == nil {
+				cp.Properties = &paho.ConnectProperties{}
+			}
+			cp.Properties.SessionExpiryInterval = &expiry
+
+			return cp
+		})
+	}
+	c.cfg.SetUsernamePassword(os.Getenv("MQTT_USER"), []byte(os.Getenv("MQTT_PASS")))
+	will := DeliveryFor("SERVER_WILL_TOPIC")
+	c.cfg.SetWillMessage(os.Getenv("SERVER_WILL_TOPIC"), []byte(willPayload), will.QoS, will.Retained)
+
+	c.cfg.OnConnectionUp = func(cm *autopaho.ConnectionManager, _ *paho.Connack) {
+		c.connected.Store(true)
+		log.Info().Msg("Connected to broker over MQTT 5")
+
+		c.resubscribe(cm)
+		onConnect(c)
+	}
+	c.cfg.OnConnectError = func(err error) {
+		log.Warn().Err(err).Msg("failed to connect to broker")
+	}
+	c.cfg.OnClientError = func(err error) {
+		c.connected.Store(false)
+		log.Warn().Err(err).Msg("Connection lost to broker")
+	}
+	c.cfg.OnServerDisconnect = func(d *paho.Disconnect) {
+		c.connected.Store(false)
+		log.Warn().Err(&ReasonCodeError{Code: d.ReasonCode, Reason: disconnectReason(d)}).Msg("Broker closed the connection")
+	}
+
+	return c, nil
+}
+
+func disconnectReason(d *paho.Disconnect) string {
+	if d.Properties == nil {
+		return ""
+	}
+
+	return d.Properties.ReasonString
+}
+
+func (c *v5Client) resubscribe(cm *autopaho.ConnectionManager) {
+	c.mu.Lock()
+	subscriptions := make(map[string]paho.SubscribeOptions, len(c.subscriptions))
+	for topic, qos := range c.subscriptions {
+		subscriptions[topic] = paho.SubscribeOptions{QoS: qos}
+	}
+	c.mu.Unlock()
+
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	go func() {
+		if _, err := cm.Subscribe(context.Background(), &paho.Subscribe{Subscriptions: subscriptions}); err != nil {
+			log.Error().Err(err).Msg("failed to resubscribe after reconnecting")
+		}
+	}()
+}
+
+func (c *v5Client) IsConnected() bool {
+	return c.cm.Load() != nil
+}
+
+func (c *v5Client) IsConnectionOpen() bool {
+	return c.connected.Load()
+}
+
+// Connect starts connecting in the background, the token completing once the first connection is up
+func (c *v5Client) Connect() mqtt.Token {
+	var err error
+	c.start.Do(func() {
+		var cm *autopaho.ConnectionManager
+
+		cm, err = autopaho.NewConnection(context.Background(), c.cfg)
+		c.cm.Store(cm)
+	})
+
+	return newToken(func() error {
+		if err != nil {
+			return err
+		}
+
+		return c.cm.Load().AwaitConnection(context.Background())
+	})
+}
+
+func (c *v5Client) Disconnect(quiesce uint) {
+	cm := c.cm.Load()
+	if cm == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(quiesce)*time.Millisecond)
+	defer cancel()
+
+	if err := cm.Disconnect(ctx); err != nil {
+		log.Warn().Err(err).Msg("failed to disconnect from broker cleanly")
+	}
+	c.connected.Store(false)
+}
+
+func (c *v5Client) Publish(topic string, qos byte, retained bool, payload interface{}) mqtt.Token {
+	return c.publish(topic, qos, retained, payload, nil)
+}
+
+func (c *v5Client) publish(topic string, qos byte, retained bool, payload interface{}, props *Properties) mqtt.Token {
+	return newToken(func() error {
+		cm := c.cm.Load()
+		if cm == nil {
+			return autopaho.ConnectionDownError
+		}
+
+		p := &paho.Publish{Topic: topic, QoS: qos, Retain: retained}
+		switch v := payload.(type) {
+		case string:
+			p.Payload = []byte(v)
+		case []byte:
+			p.Payload = v
+		default:
+			return fmt.Errorf("unsupported payload type %T", payload)
+		}
+
+		if props != nil {
+			p.Properties = &paho.PublishProperties{
+				ResponseTopic:   props.ResponseTopic,
+				CorrelationData: props.CorrelationData,
+				ContentType:     props.ContentType,
+			}
+			if props.MessageExpiry > 0 {
+				expiry := uint32((props.MessageExpiry + time.Second - 1) / time.Second)
+				p.Properties.MessageExpiry = &expiry
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), config.Duration("MQTT_PUBLISH_TIMEOUT", 30*time.Second))
+		defer cancel()
+
+		resp, err := cm.Publish(ctx, p)
+		if err != nil {
+			return err
+		}
+
+		// QoS 0 publishes get no response
+		if resp != nil && resp.ReasonCode >= 0x80 {
+			reason := ""
+			if resp.Properties != nil {
+				reason = resp.Properties.ReasonString
+			}
+
+			return &ReasonCodeError{Code: resp.ReasonCode, Reason: reason}
+		}
+
+		return nil
+	})
+}
+
+func (c *v5Client) Subscribe(topic string, qos byte, callback mqtt.MessageHandler) mqtt.Token {
+	return c.SubscribeMultiple(map[string]byte{topic: qos}, callback)
+}
+
+func (c *v5Client) SubscribeMultiple(filters map[string]byte, callback mqtt.MessageHandler) mqtt.Token {
+	subscribe := &paho.Subscribe{Subscriptions: make(map[string]paho.SubscribeOptions, len(filters))}
+
+	c.mu.Lock()
+	for topic, qos := range filters {
+		c.subscriptions[topic] = qos
+		subscribe.Subscriptions[topic] = paho.SubscribeOptions{QoS: qos}
+
+		if callback != nil {
+			c.AddRoute(topic, callback)
+		}
+	}
+	c.mu.Unlock()
+
+	return newToken(func() error {
+		cm := c.cm.Load()
+		if cm == nil {
+			return autopaho.ConnectionDownError
+		}
+
+		suback, err := cm.Subscribe(context.Background(), subscribe)
+		if err != nil {
+			return err
+		}
+
+		for _, code := range suback.Reasons {
+			if code >= 0x80 {
+				return &ReasonCodeError{Code: code, Reason: subackReason(suback)}
+			}
+		}
+
+		return nil
+	})
+}
+
+func subackReason(suback *paho.Suback) string {
+	if suback.Properties == nil {
+		return ""
+	}
+
+	return suback.Properties.ReasonString
+}
+
+func (c *v5Client) Unsubscribe(topics ...string) mqtt.Token {
+	c.mu.Lock()
+	for _, topic := range topics {
+		delete(c.subscriptions, topic)
+		c.router.UnregisterHandler(topic)
+	}
+	c.mu.Unlock()
+
+	return newToken(func() error {
+		cm := c.cm.Load()
+		if cm == nil {
+			return autopaho.ConnectionDownError
+		}
+
+		_, err := cm.Unsubscribe(context.Background(), &paho.Unsubscribe{Topics: topics})
+
+		return err
+	})
+}
+
+func (c *v5Client) AddRoute(topic string, callback mqtt.MessageHandler) {
+	c.router.RegisterHandler(topic, func(p *paho.Publish) {
+		callback(c, &v5Message{publish: p})
+	})
+}
+
+// OptionsReader is not backed by any options, the MQTT 5 client is configured by autopaho
+func (c *v5Client) OptionsReader() mqtt.ClientOptionsReader {
+	return mqtt.ClientOptionsReader{}
+}
@@ -0,0 +1,4 @@
+// Package cabinetpb contains the generated gRPC bindings of the cabinet service
+package cabinetpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative cabinet.proto
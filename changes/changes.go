@@ -0,0 +1,217 @@
+// Package changes listens for the changes of the slots postgres notifies of on the slot_changes channel, so that
+// the edits made outside of the server, like by hand or by other tools, also reach the streams and the devices.
+// The changes made by the other instances reach the streams of this one, while their snapshots are left to them
+package changes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/config"
+	"letovo-computers-server/events"
+	"letovo-computers-server/metrics"
+	"letovo-computers-server/state"
+	"letovo-computers-server/storage"
+	"letovo-computers-server/types"
+)
+
+// Channel is the channel the trigger of the slots notifies on
+const Channel = "slot_changes"
+
+// change is the payload of a notification, the slot as it is after the change, or as it was before its deletion
+type change struct {
+	Op                string `json:"op"`
+	ID                string `json:"id"`
+	CabinetID         string `json:"cabinet_id"`
+	PreviousCabinetID string `json:"previous_cabinet_id"`
+	IsTaken           bool   `json:"is_taken"`
+	TakenBy           string `json:"taken_by"`
+	Origin            string `json:"origin"`
+}
+
+// Listen broadcasts the slots changed in the db to the hub and refreshes the retained snapshots of the slots
+// changed outside of the server, until ctx is done. The snapshots are refreshed once per CHANGES_DEBOUNCE,
+// however many slots changed, and republished as a whole after the connection is lost, as the notifications
+// sent in the meantime are gone
+func Listen(ctx context.Context, dsn string, client broker.Broker, hub *events.Hub) {
+	notifications := make(chan *pgconn.Notification)
+	go listen(ctx, dsn, notifications)
+
+	handle(ctx, notifications, client, hub)
+}
+
+// handle applies the notifications until ctx is done, see Listen
+func handle(ctx context.Context, notifications <-chan *pgconn.Notification, client broker.Broker, hub *events.Hub) {
+	self := storage.ApplicationName()
+
+	debounce := time.NewTicker(config.Duration("CHANGES_DEBOUNCE", 250*time.Millisecond))
+	defer debounce.Stop()
+
+	cabinets := make(map[string]bool)
+	snapshot := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case n := <-notifications:
+			// a nil notification follows a reconnection
+			if n == nil {
+				log.Info().Msg("reconnected to slot changes, republishing the state")
+				metrics.SlotChanges.WithLabelValues("resync").Inc()
+
+				if err := state.Republish(ctx, client); err != nil {
+					log.Error().Err(err).Msg("failed to republish state after reconnecting")
+				}
+				continue
+			}
+
+			var c change
+			if err := json.Unmarshal([]byte(n.Payload), &c); err != nil {
+				log.Error().Err(err).Str("payload", n.Payload).Msg("failed to decode slot change")
+				continue
+			}
+
+			switch {
+			case c.Origin == self:
+				// already broadcast and published while storing it
+				metrics.SlotChanges.WithLabelValues("self").Inc()
+				continue
+			case strings.HasPrefix(c.Origin, storage.ApplicationPrefix):
+				metrics.SlotChanges.WithLabelValues("peer").Inc()
+			default:
+				log.Info().Str("slot", c.ID).Str("op", c.Op).Str("origin", c.Origin).Msgf("slot %s changed in db", c.ID)
+				metrics.SlotChanges.WithLabelValues("external").Inc()
+
+				cabinets[c.CabinetID] = true
+				cabinets[c.PreviousCabinetID] = true
+				snapshot = true
+			}
+
+			if c.Op != "DELETE" {
+				hub.Broadcast(event(c))
+			}
+
+		case <-debounce.C:
+			if !snapshot {
+				continue
+			}
+
+			ids := make([]string, 0, len(cabinets))
+			for id := range cabinets {
+				ids = append(ids, id)
+			}
+
+			if err := state.RefreshCabinets(ctx, client, ids...); err != nil {
+				log.Error().Err(err).Msg("failed to publish cabinet snapshot")
+			}
+
+			if state.Enabled() {
+				if err := state.PublishSnapshot(ctx, client); err != nil {
+					log.Error().Err(err).Msg("failed to publish snapshot")
+				}
+			}
+
+			cabinets = make(map[string]bool)
+			snapshot = false
+		}
+	}
+}
+
+// listen passes the notifications on the channel on to out until ctx is done, reconnecting with a backoff
+// doubling up to a minute once the connection is lost, and sending nil once reconnected
+func listen(ctx context.Context, dsn string, out chan<- *pgconn.Notification) {
+	backoff := time.Second
+
+	for connected := false; ; connected = true {
+		err := wait(ctx, dsn, connected, out)
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Warn().Err(err).Dur("backoff", backoff).Msg("lost slot changes connection, reconnecting")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > time.Minute {
+			backoff = time.Minute
+		}
+	}
+}
+
+// wait listens on the channel over a connection of its own, as the pooled ones are handed to other queries,
+// and passes the notifications on until the connection fails. The wait times out every 90 seconds to ping the
+// connection, as a dead one goes unnoticed otherwise while it is quiet
+func wait(ctx context.Context, dsn string, reconnected bool, out chan<- *pgconn.Notification) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", Channel, err)
+	}
+
+	if reconnected {
+		select {
+		case out <- nil:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	for {
+		waitCtx, cancel := context.WithTimeout(ctx, 90*time.Second)
+		n, err := conn.WaitForNotification(waitCtx)
+		cancel()
+
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Is(err, context.DeadlineExceeded):
+			if err := conn.Ping(ctx); err != nil {
+				return fmt.Errorf("failed to ping: %w", err)
+			}
+			continue
+		case err != nil:
+			return err
+		}
+
+		select {
+		case out <- n:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// event stands for the change of the slot in the streams, as the slot being taken by or placed back by the holder
+func event(c change) types.Event {
+	status := types.Placed
+	if c.IsTaken {
+		status = types.Taken
+	}
+
+	return types.Event{
+		Timestamp: time.Now(),
+		RFID:      c.TakenBy,
+		Slot:      c.ID,
+		Status:    status,
+		Result:    types.ResultChanged,
+	}
+}
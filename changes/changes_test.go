@@ -0,0 +1,134 @@
+package changes
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+
+	"letovo-computers-server/aliases"
+	"letovo-computers-server/api"
+	"letovo-computers-server/broker/brokertest"
+	"letovo-computers-server/events"
+	"letovo-computers-server/models"
+	"letovo-computers-server/storage/storagetest"
+)
+
+// sseEvents reads the events of the SSE stream as they arrive
+func sseEvents(t *testing.T, url string) <-chan [2]string {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := make(chan [2]string)
+	go func() {
+		defer resp.Body.Close()
+		defer close(out)
+
+		var name string
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				select {
+				case out <- [2]string{name, strings.TrimPrefix(line, "data: ")}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+func next(t *testing.T, stream <-chan [2]string) (string, string) {
+	t.Helper()
+
+	select {
+	case event, ok := <-stream:
+		if !ok {
+			t.Fatal("the stream ended")
+		}
+		return event[0], event[1]
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an event")
+	}
+
+	return "", ""
+}
+
+func TestExternalChangeReachesSSE(t *testing.T) {
+	storagetest.New(t)
+	t.Setenv("CHANGES_DEBOUNCE", "1h")
+
+	ctx := context.Background()
+	if err := (&models.User{ID: "0001"}).InsertG(ctx, boil.Infer()); err != nil {
+		t.Fatal(err)
+	}
+	slot := &models.Slot{ID: "A1", TakenBy: "0001"}
+	if err := slot.InsertG(ctx, boil.Infer()); err != nil {
+		t.Fatal(err)
+	}
+
+	hub := events.NewHub()
+	srv := httptest.NewServer(api.New("", api.Options{Hub: hub, Aliases: aliases.New(nil)}).Handler)
+	// registered before the stream, so the stream is cancelled before the server waits for it to close
+	t.Cleanup(srv.Close)
+
+	stream := sseEvents(t, srv.URL+"/events")
+	if name, _ := next(t, stream); name != "snapshot" {
+		t.Fatalf("got %s event first, want the snapshot", name)
+	}
+
+	// the slot is taken by hand, which the trigger notifies of
+	slot.IsTaken = true
+	if _, err := slot.UpdateG(ctx, boil.Whitelist(models.SlotColumns.IsTaken)); err != nil {
+		t.Fatal(err)
+	}
+
+	notifications := make(chan *pgconn.Notification, 1)
+	notifications <- &pgconn.Notification{
+		Channel: Channel,
+		Payload: `{"op":"UPDATE","id":"A1","is_taken":true,"taken_by":"0001","origin":"psql"}`,
+	}
+
+	handleCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go handle(handleCtx, notifications, brokertest.New(), hub)
+
+	name, data := next(t, stream)
+	if name != "slot" {
+		t.Fatalf("got %s event, want the changed slot", name)
+	}
+
+	var changed models.Slot
+	if err := json.Unmarshal([]byte(data), &changed); err != nil {
+		t.Fatal(err)
+	}
+	if changed.ID != "A1" || !changed.IsTaken {
+		t.Errorf("slot event = %s, want A1 taken", data)
+	}
+}
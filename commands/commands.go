@@ -0,0 +1,327 @@
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/config"
+	"letovo-computers-server/metrics"
+	"letovo-computers-server/models"
+)
+
+const (
+	CmdUnlock = "unlock"
+	CmdReboot = "reboot"
+	CmdBlink  = "blink"
+	CmdResync = "resync"
+)
+
+// ValidCmd reports whether the devices understand the command
+func ValidCmd(cmd string) bool {
+	switch cmd {
+	case CmdUnlock, CmdReboot, CmdBlink, CmdResync:
+		return true
+	default:
+		return false
+	}
+}
+
+// The statuses a stored command goes through
+const (
+	// StatusPending is the status of the commands not yet accepted by the broker
+	StatusPending = "pending"
+	// StatusDelivered is the status of the commands accepted by the broker, waiting for the reply of the device
+	StatusDelivered = "delivered"
+	// StatusAcknowledged is the status of the commands the device replied to have executed
+	StatusAcknowledged = "acknowledged"
+	// StatusFailed is the status of the commands the device failed to execute or the broker refused
+	StatusFailed = "failed"
+	// StatusExpired is the status of the commands the device did not reply to in time
+	StatusExpired = "expired"
+)
+
+// ErrNoReply is returned when the device does not acknowledge the command in time
+var ErrNoReply = errors.New("device did not reply")
+
+// Command is published to the command topic of a device
+type Command struct {
+	ID     string `json:"id"`
+	Cmd    string `json:"cmd"`
+	Device string `json:"device,omitempty"`
+	Slot   string `json:"slot,omitempty"`
+}
+
+// Reply is published by the devices to the ARDUINO_REPLY_TOPIC once they have executed a Command
+type Reply struct {
+	ID    string `json:"id"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Topic returns the command topic of the device, or the topic shared by all devices if the device is unknown
+func Topic(device string) string {
+	topic := config.String("ARDUINO_COMMAND_TOPIC", "")
+	if device == "" {
+		return topic
+	}
+
+	return topic + "/" + device
+}
+
+// Dispatcher sends commands to the devices and matches their replies with the waiting senders by the command ID
+type Dispatcher struct {
+	client broker.Broker
+
+	mu      sync.Mutex
+	pending map[string]chan Reply
+}
+
+// New creates a Dispatcher publishing with the client
+func New(client broker.Broker) *Dispatcher {
+	return &Dispatcher{client: client, pending: make(map[string]chan Reply)}
+}
+
+// Enqueue stores the command and publishes it to the device in the background, returning the stored command.
+// The command expires if the device does not reply within the ttl, its progress being tracked in the commands table
+func (d *Dispatcher) Enqueue(ctx context.Context, command Command, ttl time.Duration) (*models.Command, error) {
+	row, err := d.store(ctx, &command, time.Now().Add(ttl))
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		ctx, cancel := context.WithDeadline(context.Background(), row.ExpiresAt)
+		defer cancel()
+
+		if err := d.publish(ctx, command); err != nil {
+			log.Error().Err(err).Str("command", command.ID).Msgf("failed to send %s command", command.Cmd)
+		}
+	}()
+
+	return row, nil
+}
+
+// Send publishes the command to the device and waits for its reply until ctx is done, returning ErrNoReply then.
+// The command is tracked like the enqueued ones, although failing to store it does not keep it from being sent
+func (d *Dispatcher) Send(ctx context.Context, command Command) (Reply, error) {
+	if command.ID == "" {
+		id, err := newID()
+		if err != nil {
+			return Reply{}, err
+		}
+
+		command.ID = id
+	}
+
+	expiresAt, ok := ctx.Deadline()
+	if !ok {
+		expiresAt = time.Now().Add(config.Duration("COMMAND_TTL", time.Minute))
+	}
+
+	if _, err := d.store(ctx, &command, expiresAt); err != nil {
+		log.Error().Err(err).Str("command", command.ID).Msg("failed to store command, sending it untracked")
+	}
+
+	replies := make(chan Reply, 1)
+
+	d.mu.Lock()
+	d.pending[command.ID] = replies
+	d.mu.Unlock()
+
+	defer func() {
+		d.mu.Lock()
+		delete(d.pending, command.ID)
+		d.mu.Unlock()
+	}()
+
+	err := d.publish(ctx, command)
+	if errors.Is(err, ErrNoReply) {
+		return Reply{}, err
+	}
+	if err != nil {
+		return Reply{}, fmt.Errorf("failed to publish command: %w", err)
+	}
+
+	select {
+	case reply := <-replies:
+		return reply, nil
+	case <-ctx.Done():
+		return Reply{}, ErrNoReply
+	}
+}
+
+// publish publishes the command to the device, marking it delivered once the broker accepts it
+func (d *Dispatcher) publish(ctx context.Context, command Command) error {
+	payload, err := json.Marshal(command)
+	if err != nil {
+		return fmt.Errorf("failed to marshal command: %w", err)
+	}
+
+	// over MQTT 5 the command also carries where to reply and expires with the wait, so that a device coming
+	// online later does not execute a stale unlock. Commands are never retained for the same reason
+	props := broker.Properties{CorrelationData: []byte(command.ID)}
+	if topic := config.String("ARDUINO_REPLY_TOPIC", ""); !strings.ContainsAny(topic, "+#") {
+		props.ResponseTopic = topic
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		props.MessageExpiry = time.Until(deadline)
+	}
+
+	delivery := broker.DeliveryFor("ARDUINO_COMMAND_TOPIC")
+	t := broker.PublishWithProperties(d.client, Topic(command.Device), delivery.QoS, delivery.Retained, payload, props)
+	select {
+	case <-t.Done():
+	case <-ctx.Done():
+		return ErrNoReply
+	}
+
+	if t.Error() != nil {
+		d.update(command.ID, models.M{
+			models.CommandColumns.Status: StatusFailed,
+			models.CommandColumns.Error:  t.Error().Error(),
+		}, StatusPending)
+
+		return t.Error()
+	}
+
+	d.update(command.ID, models.M{
+		models.CommandColumns.Status:      StatusDelivered,
+		models.CommandColumns.DeliveredAt: time.Now(),
+	}, StatusPending)
+
+	return nil
+}
+
+// store inserts the command as pending, assigning it an ID if it has none
+func (d *Dispatcher) store(ctx context.Context, command *Command, expiresAt time.Time) (*models.Command, error) {
+	if command.ID == "" {
+		id, err := newID()
+		if err != nil {
+			return nil, err
+		}
+
+		command.ID = id
+	}
+
+	row := &models.Command{
+		ID:        command.ID,
+		CMD:       command.Cmd,
+		Device:    command.Device,
+		Slot:      command.Slot,
+		Status:    StatusPending,
+		ExpiresAt: expiresAt,
+	}
+
+	err := row.InsertG(ctx, boil.Infer())
+	if err != nil {
+		return nil, fmt.Errorf("failed to store command: %w", err)
+	}
+
+	return row, nil
+}
+
+// update sets the columns of the command if it is in one of the from statuses, so that a late or duplicate update
+// does not overwrite a final status. Failing to do so is only logged, the tracking never gets in the way of sending
+func (d *Dispatcher) update(id string, set models.M, from ...string) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.Duration("COMMAND_DB_TIMEOUT", 5*time.Second))
+	defer cancel()
+
+	_, err := models.Commands(
+		models.CommandWhere.ID.EQ(id),
+		models.CommandWhere.Status.IN(from),
+	).UpdateAllG(ctx, set)
+	if err != nil {
+		log.Error().Err(err).Str("command", id).Msgf("failed to mark command %s", set[models.CommandColumns.Status])
+	}
+}
+
+// HandleReply is the callback of the ARDUINO_REPLY_TOPIC, recording the replies and handing them over
+// to the waiting senders
+func (d *Dispatcher) HandleReply(_ mqtt.Client, resp mqtt.Message) {
+	reply := Reply{}
+
+	err := json.Unmarshal(resp.Payload(), &reply)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to unmarshal command reply")
+		metrics.ObserveParseFailure("ARDUINO_REPLY_TOPIC")
+		return
+	}
+
+	// MQTT 5 devices may match the reply by its correlation data alone
+	if props, ok := broker.MessageProperties(resp); ok && reply.ID == "" {
+		reply.ID = string(props.CorrelationData)
+	}
+
+	// a late reply still tells that the device executed the command, so it overrides the expiry
+	status := StatusAcknowledged
+	if !reply.OK {
+		status = StatusFailed
+	}
+	d.update(reply.ID, models.M{
+		models.CommandColumns.Status:    status,
+		models.CommandColumns.Error:     reply.Error,
+		models.CommandColumns.RepliedAt: time.Now(),
+	}, StatusPending, StatusDelivered, StatusExpired)
+
+	d.mu.Lock()
+	replies, ok := d.pending[reply.ID]
+	d.mu.Unlock()
+
+	if !ok {
+		log.Debug().Str("id", reply.ID).Msg("received reply to unknown or expired command")
+		return
+	}
+
+	select {
+	case replies <- reply:
+	default:
+		// a duplicate delivery of the reply
+	}
+}
+
+// Run marks the commands the devices did not reply to in time as expired every COMMAND_EXPIRY_INTERVAL until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(config.Duration("COMMAND_EXPIRY_INTERVAL", 30*time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := models.Commands(
+				models.CommandWhere.Status.IN([]string{StatusPending, StatusDelivered}),
+				models.CommandWhere.ExpiresAt.LT(time.Now()),
+			).UpdateAllG(ctx, models.M{models.CommandColumns.Status: StatusExpired})
+			if err != nil {
+				log.Error().Err(err).Msg("failed to expire commands")
+				continue
+			}
+
+			if n > 0 {
+				log.Info().Int64("commands", n).Msg("Expired commands the devices did not reply to")
+			}
+		}
+	}
+}
+
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate command id: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// String returns the setting named by the key, or def when it is unset or empty
+func String(key, def string) string {
+	if v := lookup(key); v != "" {
+		return v
+	}
+
+	return def
+}
+
+// Int returns the setting named by the key as an int, or def when it is unset or malformed
+func Int(key string, def int) int {
+	v := lookup(key)
+	if v == "" {
+		return def
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		log.Warn().Err(err).Msgf("malformed %s, falling back to %d", key, def)
+		return def
+	}
+
+	return i
+}
+
+// Float returns the setting named by the key as a float64, or def when it is unset or malformed
+func Float(key string, def float64) float64 {
+	v := lookup(key)
+	if v == "" {
+		return def
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Warn().Err(err).Msgf("malformed %s, falling back to %g", key, def)
+		return def
+	}
+
+	return f
+}
+
+// Bool returns the setting named by the key as a bool, or def when it is unset or malformed
+func Bool(key string, def bool) bool {
+	v := lookup(key)
+	if v == "" {
+		return def
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Warn().Err(err).Msgf("malformed %s, falling back to %t", key, def)
+		return def
+	}
+
+	return b
+}
+
+// Duration returns the setting named by the key as a time.Duration, or def when it is unset or malformed
+func Duration(key string, def time.Duration) time.Duration {
+	v := lookup(key)
+	if v == "" {
+		return def
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Warn().Err(err).Msgf("malformed %s, falling back to %s", key, def)
+		return def
+	}
+
+	return d
+}
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/joho/godotenv"
+	"github.com/rs/zerolog/log"
+)
+
+// immutable settings are only read when establishing the db and broker connections, the http listener, the worker
+// pools and the lookup tables loaded from files, so changing them requires a restart
+var immutable = map[string]bool{
+	"DB_DRIVER":                   true,
+	"DB_NOTIFY":                   true,
+	"SQLITE_PATH":                 true,
+	"PGUSER":                      true,
+	"PGPASSWORD":                  true,
+	"PGHOST":                      true,
+	"PGPORT":                      true,
+	"PGDATABASE":                  true,
+	"PGSSLMODE":                   true,
+	"MQTT_HOST":                   true,
+	"MQTT_PORT":                   true,
+	"MQTT_CLIENT_ID":              true,
+	"MQTT_USER":                   true,
+	"MQTT_PASS":                   true,
+	"MQTT_CA_FILE":                true,
+	"MQTT_CERT_FILE":              true,
+	"MQTT_KEY_FILE":               true,
+	"MQTT_INSECURE_SKIP_VERIFY":   true,
+	"MQTT_TRANSPORT":              true,
+	"MQTT_WS_PATH":                true,
+	"MQTT_PROTOCOL_VERSION":       true,
+	"MQTT_SHARE_GROUP":            true,
+	"MQTT_CLEAN_SESSION":          true,
+	"MQTT_MANUAL_ACK":             true,
+	"MQTT_SESSION_EXPIRY":         true,
+	"MQTT_RECONNECT_INTERVAL":     true,
+	"MQTT_MAX_RECONNECT_INTERVAL": true,
+	"MQTT_RECONNECT_JITTER":       true,
+	"MQTT_WORKERS":                true,
+	"MQTT_WORKER_QUEUE_SIZE":      true,
+	"MQTT_WORKER_OVERFLOW":        true,
+	"SERVER_WILL_TOPIC":           true,
+	"MQTT_EMBEDDED":               true,
+	"MQTT_EMBEDDED_ADDRESS":       true,
+	"MQTT_EMBEDDED_CERT_FILE":     true,
+	"MQTT_EMBEDDED_KEY_FILE":      true,
+	"MQTT_EMBEDDED_AUTH_FILE":     true,
+	"OUTGOING_BUFFER_FILE":        true,
+	"BRIDGE_BROKERS":              true,
+	"BRIDGE_TOPIC":                true,
+	"BRIDGE_CLIENT_ID":            true,
+	"BRIDGE_USER":                 true,
+	"BRIDGE_PASS":                 true,
+	"BRIDGE_CA_FILE":              true,
+	"BRIDGE_CERT_FILE":            true,
+	"BRIDGE_KEY_FILE":             true,
+	"BRIDGE_INSECURE_SKIP_VERIFY": true,
+	"HTTP_ADDR":                   true,
+	"HTTP_REUSEPORT":              true,
+	"GRPC_ADDR":                   true,
+	"SLOT_ALIASES_FILE":           true,
+	"DEVICE_SCOPES_FILE":          true,
+	"DEVICE_SECRETS_FILE":         true,
+	"PAYLOAD_SCHEMA_DIR":          true,
+	"WEBHOOK_WORKERS":             true,
+	"RECENT_EVENTS_SIZE":          true,
+	"DEADMAN_WINDOW":              true,
+}
+
+// settings are the ones reloaded from the env file, taking precedence over the environment. The unset ones were
+// removed from the file since the startup, having been set by it, and read as unset rather than as the environment
+type settings struct {
+	values map[string]string
+	unset  map[string]bool
+}
+
+// overrides holds the settings reloaded from the env file. Reload swaps them as a whole, so readers always see
+// a consistent set of settings
+var overrides atomic.Value
+
+var (
+	// reloadMu serializes Load and Reload, which keep track of the keys of the env file
+	reloadMu sync.Mutex
+	// loaded are the keys Load set in the environment from the env file, the environment taking precedence
+	loaded = map[string]bool{}
+	// fileKeys are the keys of the env file as last read, so that Reload tells the ones removed since
+	fileKeys = map[string]bool{}
+)
+
+func init() {
+	overrides.Store(settings{})
+}
+
+func lookup(key string) string {
+	v, _ := Lookup(key)
+	return v
+}
+
+// Lookup returns the value of the setting named by the key and whether it is set at all, so that a setting
+// deliberately set empty can be told apart from an unset one
+func Lookup(key string) (string, bool) {
+	current := overrides.Load().(settings)
+	if v, ok := current.values[key]; ok {
+		return v, true
+	}
+	if current.unset[key] {
+		return "", false
+	}
+
+	return os.LookupEnv(key)
+}
+
+// Load sets the settings of the env file in the environment, leaving the ones already set there alone
+func Load(path string) error {
+	values, err := godotenv.Read(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	for key, v := range values {
+		fileKeys[key] = true
+		if _, ok := os.LookupEnv(key); ok {
+			continue
+		}
+
+		if err := os.Setenv(key, v); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+		loaded[key] = true
+	}
+
+	return nil
+}
+
+// Change is a setting modified by Reload
+type Change struct {
+	Key string
+	Old string
+	New string
+}
+
+// Reload re-reads the env file and atomically applies the changed mutable settings, returning them. A setting
+// removed from the file reverts to its value in the environment, or to its default if the file set it.
+// Changes to immutable settings are logged and ignored
+func Reload(path string) ([]Change, error) {
+	values, err := godotenv.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	current := overrides.Load().(settings)
+	next := settings{
+		values: make(map[string]string, len(current.values)+len(values)),
+		unset:  make(map[string]bool, len(current.unset)),
+	}
+	for key, v := range current.values {
+		next.values[key] = v
+	}
+	for key := range current.unset {
+		next.unset[key] = true
+	}
+
+	var changes []Change
+	for key, v := range values {
+		old, ok := Lookup(key)
+		if ok && v == old {
+			continue
+		}
+
+		if immutable[key] {
+			log.Warn().Str("key", key).Msgf("%s changed, restart the server to apply it", key)
+			continue
+		}
+
+		next.values[key] = v
+		delete(next.unset, key)
+		changes = append(changes, Change{Key: key, Old: old, New: v})
+	}
+
+	for key := range fileKeys {
+		if _, ok := values[key]; ok {
+			continue
+		}
+
+		old, ok := Lookup(key)
+		if !ok {
+			continue
+		}
+
+		if immutable[key] {
+			log.Warn().Str("key", key).Msgf("%s removed, restart the server to apply it", key)
+			continue
+		}
+
+		delete(next.values, key)
+		v := os.Getenv(key)
+		if loaded[key] {
+			next.unset[key] = true
+			v = ""
+		}
+
+		changes = append(changes, Change{Key: key, Old: old, New: v})
+	}
+
+	fileKeys = make(map[string]bool, len(values))
+	for key := range values {
+		fileKeys[key] = true
+	}
+
+	overrides.Store(next)
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes, nil
+}
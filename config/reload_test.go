@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// resetReload forgets the reloaded settings and the keys of the env file once the test is over
+func resetReload(t *testing.T) {
+	t.Cleanup(func() {
+		overrides.Store(settings{})
+		loaded = map[string]bool{}
+		fileKeys = map[string]bool{}
+	})
+}
+
+func TestReload(t *testing.T) {
+	resetReload(t)
+	t.Setenv("HEALTH_ERROR_RATIO_THRESHOLD", "0.5")
+	t.Setenv("HTTP_ADDR", ":8080")
+	t.Setenv("MQTT_MANUAL_ACK", "false")
+
+	path := filepath.Join(t.TempDir(), ".env")
+	err := os.WriteFile(path, []byte("HEALTH_ERROR_RATIO_THRESHOLD=0.9\nHTTP_ADDR=:9090\nMQTT_MANUAL_ACK=true\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := Reload(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changes) != 1 || changes[0] != (Change{Key: "HEALTH_ERROR_RATIO_THRESHOLD", Old: "0.5", New: "0.9"}) {
+		t.Errorf("changes = %+v, want the threshold changed alone", changes)
+	}
+	if threshold := Float("HEALTH_ERROR_RATIO_THRESHOLD", 0.5); threshold != 0.9 {
+		t.Errorf("threshold = %g after reloading, want 0.9", threshold)
+	}
+
+	// immutable settings keep their value until a restart
+	if addr := String("HTTP_ADDR", ""); addr != ":8080" {
+		t.Errorf("HTTP_ADDR = %s after reloading, want :8080", addr)
+	}
+	// the handlers acking the messages have to agree with the client set up with it
+	if Bool("MQTT_MANUAL_ACK", false) {
+		t.Error("MQTT_MANUAL_ACK = true after reloading, want false")
+	}
+}
+
+func TestReloadRemovedKeys(t *testing.T) {
+	resetReload(t)
+	t.Setenv("HEALTH_ERROR_WINDOW", "5m")
+	// set by the env file alone, so that it is unset again once removed from it
+	t.Setenv("HEALTH_ERROR_MIN_SAMPLES", "")
+	if err := os.Unsetenv("HEALTH_ERROR_MIN_SAMPLES"); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), ".env")
+	err := os.WriteFile(path, []byte("HEALTH_ERROR_WINDOW=1m\nHEALTH_ERROR_MIN_SAMPLES=3\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Reload(path); err != nil {
+		t.Fatal(err)
+	}
+	if window := Duration("HEALTH_ERROR_WINDOW", 0); window != time.Minute {
+		t.Fatalf("window = %s after reloading, want 1m", window)
+	}
+
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := Reload(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Change{
+		{Key: "HEALTH_ERROR_MIN_SAMPLES", Old: "3", New: ""},
+		{Key: "HEALTH_ERROR_WINDOW", Old: "1m", New: "5m"},
+	}
+	if len(changes) != len(want) || changes[0] != want[0] || changes[1] != want[1] {
+		t.Errorf("changes = %+v, want %+v", changes, want)
+	}
+
+	// the environment outlives the env file, the settings it lacks falling back to their defaults
+	if window := Duration("HEALTH_ERROR_WINDOW", 0); window != 5*time.Minute {
+		t.Errorf("window = %s after removing it, want the 5m of the environment", window)
+	}
+	if samples := Int("HEALTH_ERROR_MIN_SAMPLES", 10); samples != 10 {
+		t.Errorf("min samples = %d after removing it, want the default 10", samples)
+	}
+}
@@ -0,0 +1,89 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/config"
+	"letovo-computers-server/models"
+)
+
+const (
+	// StageAuthenticate is the stage of the messages whose signature could not be verified
+	StageAuthenticate = "authenticate"
+	// StageDecode is the stage of the messages whose payload could not be decoded
+	StageDecode = "decode"
+	// StageValidate is the stage of the messages whose payload breaks its JSON Schema
+	StageValidate = "validate"
+	// StageReplay is the stage of the messages sent outside the replay window
+	StageReplay = "replay"
+	// StagePersist is the stage of the messages which could not be stored
+	StagePersist = "persist"
+)
+
+// Letter is published to the DEAD_LETTER_TOPIC for every message which could not be processed
+type Letter struct {
+	Topic      string    `json:"topic"`
+	Payload    string    `json:"payload"`
+	Stage      string    `json:"stage"`
+	Error      string    `json:"error"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// Send stores the message which failed at the stage in the failed_messages table and republishes it
+// to the DEAD_LETTER_TOPIC, if it is configured, so that it can be inspected and replayed later
+func Send(ctx context.Context, client broker.Broker, msg mqtt.Message, stage string, cause error) error {
+	letter := Letter{
+		Topic:      msg.Topic(),
+		Payload:    string(msg.Payload()),
+		Stage:      stage,
+		Error:      cause.Error(),
+		ReceivedAt: time.Now(),
+	}
+
+	row := models.FailedMessage{
+		ReceivedAt: letter.ReceivedAt,
+		Topic:      letter.Topic,
+		Payload:    msg.Payload(),
+		Stage:      letter.Stage,
+		Error:      letter.Error,
+	}
+
+	// the db may well be what failed, so the letter is still published when it cannot be stored
+	insertErr := row.InsertG(ctx, boil.Infer())
+	publishErr := publish(ctx, client, letter)
+
+	if insertErr != nil && publishErr != nil {
+		return fmt.Errorf("failed to store failed message: %w, and %s", insertErr, publishErr)
+	}
+	if insertErr != nil {
+		return fmt.Errorf("failed to store failed message: %w", insertErr)
+	}
+
+	return publishErr
+}
+
+func publish(ctx context.Context, client broker.Broker, letter Letter) error {
+	topic := config.String("DEAD_LETTER_TOPIC", "")
+	if topic == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.Duration("DEAD_LETTER_TIMEOUT", 5*time.Second))
+	defer cancel()
+
+	delivery := broker.DeliveryFor("DEAD_LETTER_TOPIC")
+
+	return broker.PublishSync(ctx, client, topic, delivery.QoS, delivery.Retained, payload)
+}
@@ -0,0 +1,6 @@
+// Package devicepb contains the generated protobuf bindings of the device payloads. The nanopb structs
+// of the Arduino firmware are generated from the same schema into the arduino directory
+package devicepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative device.proto
+//go:generate nanopb_generator --output-dir=arduino device.proto
@@ -0,0 +1,73 @@
+package directory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"letovo-computers-server/config"
+)
+
+var client = &http.Client{Timeout: 5 * time.Second}
+
+// Enabled reports whether the student directory is configured
+func Enabled() bool {
+	return config.String("DIRECTORY_URL", "") != ""
+}
+
+// Entry is what the student directory has on the holder of a tag, its fields empty where the directory leaves
+// them out. Role is one of student, teacher and staff
+type Entry struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Class string `json:"class"`
+	Role  string `json:"role"`
+}
+
+// Lookup returns the entry the student directory at DIRECTORY_URL has for the RFID,
+// or an empty one if the directory is not configured or does not know the tag
+func Lookup(ctx context.Context, rfid string) (Entry, error) {
+	if !Enabled() {
+		return Entry{}, nil
+	}
+
+	u, err := url.Parse(config.String("DIRECTORY_URL", ""))
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to parse directory url: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("rfid", rfid)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to create directory request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to query directory: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return Entry{}, nil
+	case resp.StatusCode >= http.StatusBadRequest:
+		return Entry{}, fmt.Errorf("directory responded with %s", resp.Status)
+	}
+
+	var entry Entry
+
+	err = json.NewDecoder(resp.Body).Decode(&entry)
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to decode directory response: %w", err)
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/types"
+)
+
+// Publisher publishes processed events to a topic. With a positive window, the events published within it are
+// batched into a single array payload, which is flushed once the window elapses or the batch reaches maxBatch events
+type Publisher struct {
+	client   broker.Broker
+	topic    string
+	delivery broker.Delivery
+	window   time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	batch []types.Event
+	timer *time.Timer
+	wg    sync.WaitGroup
+}
+
+// NewPublisher creates a Publisher. The zero window disables batching
+func NewPublisher(
+	client broker.Broker, topic string, delivery broker.Delivery, window time.Duration, maxBatch int,
+) *Publisher {
+	if maxBatch < 1 {
+		maxBatch = 1
+	}
+
+	return &Publisher{
+		client:   client,
+		topic:    topic,
+		delivery: delivery,
+		window:   window,
+		maxBatch: maxBatch,
+	}
+}
+
+// Publish publishes the event right away or adds it to the current batch
+func (p *Publisher) Publish(event types.Event) {
+	if p.window <= 0 {
+		p.publish(event)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.batch = append(p.batch, event)
+	switch {
+	case len(p.batch) >= p.maxBatch:
+		p.flushLocked()
+	case p.timer == nil:
+		p.timer = time.AfterFunc(p.window, p.Flush)
+	}
+}
+
+// Flush publishes the current batch, if any
+func (p *Publisher) Flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.flushLocked()
+}
+
+// Close flushes the current batch and waits for the publishes to complete
+func (p *Publisher) Close() {
+	p.Flush()
+	p.wg.Wait()
+}
+
+func (p *Publisher) flushLocked() {
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+
+	if len(p.batch) == 0 {
+		return
+	}
+
+	p.publish(p.batch)
+	p.batch = nil
+}
+
+func (p *Publisher) publish(v interface{}) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to marshal events")
+		return
+	}
+
+	broker.Publish(&p.wg, p.client, p.topic, p.delivery, string(payload))
+}
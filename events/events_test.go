@@ -0,0 +1,74 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/broker/brokertest"
+	"letovo-computers-server/types"
+)
+
+// batches decodes the batches published to the topic
+func batches(t *testing.T, client *brokertest.Fake) [][]types.Event {
+	t.Helper()
+
+	var decoded [][]types.Event
+	for _, msg := range client.Published("server/events") {
+		var batch []types.Event
+		if err := json.Unmarshal(msg.Payload(), &batch); err != nil {
+			t.Fatalf("payload %q is not a batch: %v", msg.Payload(), err)
+		}
+
+		decoded = append(decoded, batch)
+	}
+
+	return decoded
+}
+
+func TestPublisherBatchesWithinWindow(t *testing.T) {
+	client := brokertest.New()
+	p := NewPublisher(client, "server/events", broker.Delivery{QoS: 1}, 20*time.Millisecond, 100)
+
+	for _, slotID := range []string{"A1", "A2", "A3"} {
+		p.Publish(types.Event{RFID: "0001", Slot: slotID, Status: types.Taken, Result: "ok"})
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); len(client.Published("server/events")) == 0; time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("the batch was not flushed once the window elapsed")
+		}
+	}
+	p.Close()
+
+	published := batches(t, client)
+	if len(published) != 1 || len(published[0]) != 3 {
+		t.Fatalf("published %v, want a single batch of 3 events", published)
+	}
+	for i, slotID := range []string{"A1", "A2", "A3"} {
+		if published[0][i].Slot != slotID {
+			t.Errorf("batch[%d].Slot = %s, want %s", i, published[0][i].Slot, slotID)
+		}
+	}
+}
+
+func TestPublisherFlushesFullBatch(t *testing.T) {
+	client := brokertest.New()
+	p := NewPublisher(client, "server/events", broker.Delivery{QoS: 1}, time.Hour, 2)
+
+	for _, slotID := range []string{"A1", "A2", "A3"} {
+		p.Publish(types.Event{RFID: "0001", Slot: slotID, Status: types.Taken, Result: "ok"})
+	}
+
+	if published := batches(t, client); len(published) != 1 || len(published[0]) != 2 {
+		t.Fatalf("published %v before the window elapsed, want the full batch of 2 events", published)
+	}
+
+	// the rest is flushed on shutdown
+	p.Close()
+
+	if published := batches(t, client); len(published) != 2 || len(published[1]) != 1 {
+		t.Errorf("published %v after closing, want the last event in a batch of its own", published)
+	}
+}
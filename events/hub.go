@@ -0,0 +1,59 @@
+package events
+
+import (
+	"sync"
+
+	"letovo-computers-server/types"
+)
+
+// Hub fans processed events out to in-process subscribers, such as streaming API clients
+type Hub struct {
+	mu   sync.Mutex
+	subs map[chan types.Event]struct{}
+}
+
+// NewHub creates a Hub without subscribers
+func NewHub() *Hub {
+	return &Hub{subs: make(map[chan types.Event]struct{})}
+}
+
+// Subscribe returns a channel receiving the events broadcast from now on and a function cancelling the subscription.
+// Subscribers falling more than buffer events behind are dropped, closing their channel, so they can resync
+// instead of silently missing events
+func (h *Hub) Subscribe(buffer int) (<-chan types.Event, func()) {
+	ch := make(chan types.Event, buffer)
+
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		h.dropLocked(ch)
+	}
+}
+
+// Broadcast sends the event to every subscriber without blocking on slow ones
+func (h *Hub) Broadcast(event types.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs {
+		select {
+		case ch <- event:
+		default:
+			h.dropLocked(ch)
+		}
+	}
+}
+
+func (h *Hub) dropLocked(ch chan types.Event) {
+	if _, ok := h.subs[ch]; !ok {
+		return
+	}
+
+	delete(h.subs, ch)
+	close(ch)
+}
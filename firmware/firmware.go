@@ -0,0 +1,255 @@
+package firmware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/config"
+	"letovo-computers-server/metrics"
+	"letovo-computers-server/models"
+)
+
+// ErrExists is returned by Upload when the version was already uploaded, releases being immutable
+var ErrExists = errors.New("firmware version already exists")
+
+// Announcement is published to the SERVER_FIRMWARE_TOPIC as a retained message once a release is uploaded,
+// telling the devices what to download
+type Announcement struct {
+	Version   string `json:"version"`
+	Size      int    `json:"size"`
+	SHA256    string `json:"sha256"`
+	ChunkSize int    `json:"chunk_size"`
+	Chunks    int    `json:"chunks"`
+}
+
+// Request is published by the devices to the ARDUINO_FIRMWARE_REQUEST_TOPIC to download a release, the latest one
+// when the version is empty. The chunks are streamed from the offset on, all of the remaining ones when count is zero,
+// so that a device can resume an interrupted download or pace it to its memory
+type Request struct {
+	Device  string `json:"device"`
+	Version string `json:"version"`
+	Offset  int    `json:"offset"`
+	Count   int    `json:"count"`
+}
+
+// Chunk is a piece of a release published to SERVER_FIRMWARE_CHUNK_TOPIC/<device>, the chunk topic defaulting
+// to the SERVER_FIRMWARE_TOPIC. The data is base64 encoded in the JSON payload
+type Chunk struct {
+	Version string `json:"version"`
+	Index   int    `json:"index"`
+	Chunks  int    `json:"chunks"`
+	Data    []byte `json:"data"`
+}
+
+// metadata are the columns of a release but its binary
+var metadata = []string{
+	models.FirmwareReleaseColumns.Version,
+	models.FirmwareReleaseColumns.Size,
+	models.FirmwareReleaseColumns.Sha256,
+	models.FirmwareReleaseColumns.CreatedAt,
+}
+
+// ChunkSize is the FIRMWARE_CHUNK_SIZE the releases are streamed in
+func ChunkSize() int {
+	if size := config.Int("FIRMWARE_CHUNK_SIZE", 1024); size > 0 {
+		return size
+	}
+
+	return 1024
+}
+
+// NewAnnouncement describes the release to the devices
+func NewAnnouncement(release *models.FirmwareRelease) Announcement {
+	size := ChunkSize()
+
+	return Announcement{
+		Version:   release.Version,
+		Size:      release.Size,
+		SHA256:    release.Sha256,
+		ChunkSize: size,
+		Chunks:    (release.Size + size - 1) / size,
+	}
+}
+
+// Upload stores the binary as the release of the version, returning it without the binary
+func Upload(ctx context.Context, version string, data []byte) (*models.FirmwareRelease, error) {
+	exists, err := models.FirmwareReleaseExistsG(ctx, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check firmware version: %w", err)
+	}
+	if exists {
+		return nil, ErrExists
+	}
+
+	sum := sha256.Sum256(data)
+	release := &models.FirmwareRelease{
+		Version: version,
+		Size:    len(data),
+		Sha256:  hex.EncodeToString(sum[:]),
+		Data:    data,
+	}
+
+	err = release.InsertG(ctx, boil.Infer())
+	if err != nil {
+		return nil, fmt.Errorf("failed to store firmware: %w", err)
+	}
+
+	release.Data = nil
+
+	return release, nil
+}
+
+// List returns the releases without their binaries, newest first
+func List(ctx context.Context) (models.FirmwareReleaseSlice, error) {
+	return models.FirmwareReleases(
+		qm.Select(metadata...),
+		qm.OrderBy(models.FirmwareReleaseColumns.CreatedAt+" DESC"),
+	).AllG(ctx)
+}
+
+// find returns the release of the version along with its binary, the latest release when the version is empty
+func find(ctx context.Context, version string) (*models.FirmwareRelease, error) {
+	if version != "" {
+		return models.FindFirmwareReleaseG(ctx, version)
+	}
+
+	return models.FirmwareReleases(qm.OrderBy(models.FirmwareReleaseColumns.CreatedAt + " DESC")).OneG(ctx)
+}
+
+// Announce publishes the release to the SERVER_FIRMWARE_TOPIC
+func Announce(ctx context.Context, client broker.Broker, release *models.FirmwareRelease) error {
+	topic := config.String("SERVER_FIRMWARE_TOPIC", "")
+	if topic == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(NewAnnouncement(release))
+	if err != nil {
+		return fmt.Errorf("failed to marshal firmware announcement: %w", err)
+	}
+
+	delivery := broker.DeliveryFor("SERVER_FIRMWARE_TOPIC")
+
+	return broker.PublishSync(ctx, client, topic, delivery.QoS, delivery.Retained, payload)
+}
+
+// HandleRequest is the callback of the ARDUINO_FIRMWARE_REQUEST_TOPIC. The chunks are streamed in the background,
+// at most FIRMWARE_MAX_TRANSFERS at a time, since waiting for the broker to take each of them would block the client
+func HandleRequest(ctx context.Context) mqtt.MessageHandler {
+	transfers := make(chan struct{}, maxTransfers())
+
+	return func(client mqtt.Client, resp mqtt.Message) {
+		request := Request{}
+
+		err := json.Unmarshal(resp.Payload(), &request)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to unmarshal firmware request")
+			metrics.ObserveParseFailure("ARDUINO_FIRMWARE_REQUEST_TOPIC")
+			return
+		}
+
+		if request.Device == "" {
+			request.Device = broker.CabinetID(config.String("ARDUINO_FIRMWARE_REQUEST_TOPIC", ""), resp.Topic())
+		}
+		if request.Device == "" {
+			log.Warn().Str("topic", resp.Topic()).Msg("received firmware request of an unnamed device")
+			return
+		}
+
+		select {
+		case transfers <- struct{}{}:
+		default:
+			log.Warn().Str("device", request.Device).Msg("too many firmware transfers, dropping request")
+			return
+		}
+
+		go func() {
+			defer func() { <-transfers }()
+
+			if err := stream(ctx, client, request); err != nil {
+				log.Error().Err(err).Str("device", request.Device).Msg("failed to stream firmware")
+			}
+		}()
+	}
+}
+
+func maxTransfers() int {
+	if n := config.Int("FIRMWARE_MAX_TRANSFERS", 4); n > 0 {
+		return n
+	}
+
+	return 1
+}
+
+// stream publishes the requested chunks of the release to the device one by one, waiting for the broker
+// to take each of them before the next
+func stream(ctx context.Context, client broker.Broker, request Request) error {
+	release, err := find(ctx, request.Version)
+	if err != nil {
+		return fmt.Errorf("failed to fetch firmware %q: %w", request.Version, err)
+	}
+
+	announcement := NewAnnouncement(release)
+	if request.Offset < 0 || request.Offset >= announcement.Chunks {
+		return fmt.Errorf("offset %d out of the %d chunks of %s", request.Offset, announcement.Chunks, release.Version)
+	}
+
+	last := announcement.Chunks
+	if request.Count > 0 && request.Offset+request.Count < last {
+		last = request.Offset + request.Count
+	}
+
+	topic := config.String("SERVER_FIRMWARE_CHUNK_TOPIC", config.String("SERVER_FIRMWARE_TOPIC", ""))
+	if topic == "" {
+		return errors.New("neither SERVER_FIRMWARE_CHUNK_TOPIC nor SERVER_FIRMWARE_TOPIC is set")
+	}
+	topic = strings.TrimSuffix(topic, "/") + "/" + request.Device
+
+	delivery := broker.DeliveryFor("SERVER_FIRMWARE_CHUNK_TOPIC")
+	timeout := config.Duration("FIRMWARE_CHUNK_TIMEOUT", 10*time.Second)
+
+	log.Info().
+		Str("device", request.Device).
+		Str("version", release.Version).
+		Int("offset", request.Offset).
+		Int("chunks", last-request.Offset).
+		Msgf("streaming firmware %s to %s", release.Version, request.Device)
+
+	for i := request.Offset; i < last; i++ {
+		end := (i + 1) * announcement.ChunkSize
+		if end > release.Size {
+			end = release.Size
+		}
+
+		payload, err := json.Marshal(Chunk{
+			Version: release.Version,
+			Index:   i,
+			Chunks:  announcement.Chunks,
+			Data:    release.Data[i*announcement.ChunkSize : end],
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal firmware chunk: %w", err)
+		}
+
+		chunkCtx, cancel := context.WithTimeout(ctx, timeout)
+		err = broker.PublishSync(chunkCtx, client, topic, delivery.QoS, delivery.Retained, payload)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to publish chunk %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+// Package graph serves the GraphQL endpoint, a read-only view of the slots, users and event history
+package graph
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+
+	"letovo-computers-server/aliases"
+	"letovo-computers-server/config"
+)
+
+//go:generate gqlgen generate --config gqlgen.yml
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// Options are the services backing the resolvers
+type Options struct {
+	Aliases *aliases.Aliases
+}
+
+// New creates the handler executing the queries, rejecting those whose complexity exceeds
+// GRAPHQL_COMPLEXITY_LIMIT. Every field costs one, multiplied by the limit for the list fields
+func New(opts Options) http.Handler {
+	cfg := Config{Resolvers: &resolver{opts: opts}}
+
+	cfg.Complexity.Query.Slots = func(child int, _ *bool, _, _ *string, limit, _ *int) int {
+		return listComplexity(child, limit)
+	}
+	cfg.Complexity.Query.Events = func(child int, _, _ *time.Time, _, _ *string, limit, _ *int) int {
+		return listComplexity(child, limit)
+	}
+	cfg.Complexity.Slot.Events = func(child int, _, _ *time.Time, limit, _ *int) int {
+		return listComplexity(child, limit)
+	}
+	cfg.Complexity.User.Events = func(child int, _, _ *time.Time, limit, _ *int) int {
+		return listComplexity(child, limit)
+	}
+
+	srv := handler.New(NewExecutableSchema(cfg))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.Introspection{})
+	srv.Use(&extension.ComplexityLimit{Func: complexityLimit})
+
+	return srv
+}
+
+func complexityLimit(context.Context, *graphql.OperationContext) int {
+	return config.Int("GRAPHQL_COMPLEXITY_LIMIT", 5000)
+}
+
+func listComplexity(child int, limit *int) int {
+	n := defaultLimit
+	if limit != nil && *limit > 0 {
+		n = *limit
+	}
+
+	return 1 + child*n
+}
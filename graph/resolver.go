@@ -0,0 +1,189 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/models"
+	"letovo-computers-server/state"
+	"letovo-computers-server/types"
+)
+
+type resolver struct {
+	opts Options
+}
+
+func (r *resolver) Event() EventResolver { return &eventResolver{r} }
+func (r *resolver) Query() QueryResolver { return &queryResolver{r} }
+func (r *resolver) Slot() SlotResolver   { return &slotResolver{r} }
+func (r *resolver) User() UserResolver   { return &userResolver{r} }
+
+// page validates the limit and offset arguments into query mods
+func page(limit, offset *int) ([]qm.QueryMod, error) {
+	n := defaultLimit
+	if limit != nil {
+		n = *limit
+		if n < 1 || n > maxLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+		}
+	}
+
+	skip := 0
+	if offset != nil {
+		skip = *offset
+		if skip < 0 {
+			return nil, errors.New("offset must not be negative")
+		}
+	}
+
+	return []qm.QueryMod{qm.Limit(n), qm.Offset(skip)}, nil
+}
+
+// events lists the logged events matching the filters within [from, to), newest first
+func events(ctx context.Context, filters []qm.QueryMod, from, to *time.Time, limit, offset *int) ([]*models.Event, error) {
+	mods, err := page(limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if from != nil {
+		filters = append(filters, models.EventWhere.ReceivedAt.GTE(*from))
+	}
+
+	if to != nil {
+		filters = append(filters, models.EventWhere.ReceivedAt.LT(*to))
+	}
+
+	filters = append(filters, qm.OrderBy(models.EventColumns.ReceivedAt+" DESC, "+models.EventColumns.ID+" DESC"))
+
+	return models.Events(append(filters, mods...)...).AllG(ctx)
+}
+
+// nullable turns a missing row into a null result
+func nullable[T any](v *T, err error) (*T, error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return v, err
+}
+
+type queryResolver struct{ *resolver }
+
+func (r *queryResolver) Slots(
+	ctx context.Context, taken *bool, takenBy *string, cabinet *string, limit *int, offset *int,
+) ([]*models.Slot, error) {
+	mods, err := page(limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if taken != nil {
+		mods = append(mods, models.SlotWhere.IsTaken.EQ(*taken))
+	}
+
+	if takenBy != nil && *takenBy != "" {
+		mods = append(mods, models.SlotWhere.TakenBy.EQ(*takenBy))
+	}
+
+	if cabinet != nil && *cabinet != "" {
+		mods = append(mods, state.InCabinet(*cabinet))
+	}
+
+	return models.Slots(append(mods, qm.OrderBy(models.SlotColumns.ID))...).AllG(ctx)
+}
+
+func (r *queryResolver) Slot(ctx context.Context, id string) (*models.Slot, error) {
+	return nullable(models.FindSlotG(ctx, r.opts.Aliases.ID(id)))
+}
+
+func (r *queryResolver) User(ctx context.Context, rfid string) (*models.User, error) {
+	return nullable(models.FindUserG(ctx, rfid))
+}
+
+func (r *queryResolver) Events(
+	ctx context.Context, from *time.Time, to *time.Time, rfid *string, slot *string, limit *int, offset *int,
+) ([]*models.Event, error) {
+	var filters []qm.QueryMod
+	if rfid != nil && *rfid != "" {
+		filters = append(filters, models.EventWhere.Rfid.EQ(*rfid))
+	}
+
+	if slot != nil && *slot != "" {
+		filters = append(filters, models.EventWhere.Slot.EQ(r.opts.Aliases.ID(*slot)))
+	}
+
+	return events(ctx, filters, from, to, limit, offset)
+}
+
+type slotResolver struct{ *resolver }
+
+func (r *slotResolver) Alias(_ context.Context, obj *models.Slot) (*string, error) {
+	alias := r.opts.Aliases.Alias(obj.ID)
+	if alias == "" {
+		return nil, nil
+	}
+
+	return &alias, nil
+}
+
+func (r *slotResolver) TakenBy(ctx context.Context, obj *models.Slot) (*models.User, error) {
+	if !obj.IsTaken {
+		return nil, nil
+	}
+
+	return nullable(models.FindUserG(ctx, obj.TakenBy))
+}
+
+func (r *slotResolver) Events(
+	ctx context.Context, obj *models.Slot, from *time.Time, to *time.Time, limit *int, offset *int,
+) ([]*models.Event, error) {
+	return events(ctx, []qm.QueryMod{models.EventWhere.Slot.EQ(obj.ID)}, from, to, limit, offset)
+}
+
+type userResolver struct{ *resolver }
+
+func (r *userResolver) Slots(ctx context.Context, obj *models.User) ([]*models.Slot, error) {
+	return obj.TakenBySlots(models.SlotWhere.IsTaken.EQ(true), qm.OrderBy(models.SlotColumns.ID)).AllG(ctx)
+}
+
+func (r *userResolver) Events(
+	ctx context.Context, obj *models.User, from *time.Time, to *time.Time, limit *int, offset *int,
+) ([]*models.Event, error) {
+	return events(ctx, []qm.QueryMod{models.EventWhere.Rfid.EQ(obj.ID)}, from, to, limit, offset)
+}
+
+type eventResolver struct{ *resolver }
+
+// User is the user of the event, the departed ones included, as the history still names them
+func (r *eventResolver) User(ctx context.Context, obj *models.Event) (*models.User, error) {
+	return nullable(models.Users(models.UserWhere.ID.EQ(obj.Rfid), qm.WithDeleted()).OneG(ctx))
+}
+
+func (r *eventResolver) Slot(ctx context.Context, obj *models.Event) (*models.Slot, error) {
+	if obj.Slot == "" {
+		return nil, nil
+	}
+
+	return nullable(models.Slots(models.SlotWhere.ID.EQ(obj.Slot), qm.WithDeleted()).OneG(ctx))
+}
+
+func (r *eventResolver) Status(_ context.Context, obj *models.Event) (EventStatus, error) {
+	switch types.Status(obj.Status) {
+	case types.Placed:
+		return EventStatusPlaced, nil
+	case types.Taken:
+		return EventStatusTaken, nil
+	case types.Scanned:
+		return EventStatusScanned, nil
+	case types.Disconnected:
+		return EventStatusDisconnected, nil
+	}
+
+	return "", fmt.Errorf("unknown status %d", obj.Status)
+}
@@ -0,0 +1,99 @@
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+)
+
+// Status is the health of the server as reported by /healthz
+type Status struct {
+	Status     string  `json:"status"`
+	ErrorRatio float64 `json:"error_ratio"`
+	Processed  int     `json:"processed"`
+	Failed     int     `json:"failed"`
+}
+
+// Monitor tracks the ratio of messages which failed to be persisted. The counts are reset every window,
+// and the server is degraded once the ratio exceeds the threshold over at least minSamples messages
+type Monitor struct {
+	window     time.Duration
+	threshold  float64
+	minSamples int
+
+	mu        sync.Mutex
+	since     time.Time
+	processed int
+	failed    int
+}
+
+// NewMonitor creates a Monitor starting a new window right away
+func NewMonitor(window time.Duration, threshold float64, minSamples int) *Monitor {
+	return &Monitor{
+		window:     window,
+		threshold:  threshold,
+		minSamples: minSamples,
+		since:      time.Now(),
+	}
+}
+
+// Configure replaces the window and the thresholds, keeping the counts of the current window
+func (m *Monitor) Configure(window time.Duration, threshold float64, minSamples int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.window = window
+	m.threshold = threshold
+	m.minSamples = minSamples
+}
+
+// Observe counts the outcome of persisting a message
+func (m *Monitor) Observe(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rollLocked()
+
+	m.processed++
+	if err != nil {
+		m.failed++
+	}
+}
+
+// Status reports the health over the current window
+func (m *Monitor) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rollLocked()
+
+	status := Status{
+		Status:    StatusOK,
+		Processed: m.processed,
+		Failed:    m.failed,
+	}
+
+	if m.processed > 0 {
+		status.ErrorRatio = float64(m.failed) / float64(m.processed)
+	}
+
+	if m.processed >= m.minSamples && status.ErrorRatio > m.threshold {
+		status.Status = StatusDegraded
+	}
+
+	return status
+}
+
+func (m *Monitor) rollLocked() {
+	if time.Since(m.since) < m.window {
+		return
+	}
+
+	m.since = time.Now()
+	m.processed = 0
+	m.failed = 0
+}
@@ -0,0 +1,47 @@
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMonitorDegraded(t *testing.T) {
+	m := NewMonitor(time.Hour, 0.5, 4)
+	failure := errors.New("failed to upsert")
+
+	// too few samples to judge, however many failed
+	m.Observe(failure)
+	m.Observe(failure)
+	if status := m.Status(); status.Status != StatusOK {
+		t.Fatalf("status = %+v with too few samples, want ok", status)
+	}
+
+	// right at the threshold
+	m.Observe(nil)
+	m.Observe(nil)
+	if status := m.Status(); status.Status != StatusOK || status.ErrorRatio != 0.5 {
+		t.Fatalf("status = %+v at the threshold, want ok at 0.5", status)
+	}
+
+	m.Observe(failure)
+	status := m.Status()
+	if status.Status != StatusDegraded || status.Processed != 5 || status.Failed != 3 {
+		t.Errorf("status = %+v past the threshold, want degraded with 3 of 5 failed", status)
+	}
+}
+
+func TestMonitorWindowResets(t *testing.T) {
+	m := NewMonitor(20*time.Millisecond, 0.5, 1)
+
+	m.Observe(errors.New("failed to upsert"))
+	if status := m.Status(); status.Status != StatusDegraded {
+		t.Fatalf("status = %+v, want degraded", status)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if status := m.Status(); status.Status != StatusOK || status.Processed != 0 {
+		t.Errorf("status = %+v after the window, want ok with the counts reset", status)
+	}
+}
@@ -0,0 +1,71 @@
+package keymutex
+
+import (
+	"sort"
+	"sync"
+)
+
+type entry struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// KeyMutex is a set of mutexes addressed by string keys. Keys are evicted as soon as nobody holds or awaits them,
+// so the map only ever contains the keys in use
+type KeyMutex struct {
+	mu    sync.Mutex
+	locks map[string]*entry
+}
+
+// New creates an empty KeyMutex
+func New() *KeyMutex {
+	return &KeyMutex{locks: make(map[string]*entry)}
+}
+
+// Lock locks the key and returns the function unlocking it
+func (k *KeyMutex) Lock(key string) (unlock func()) {
+	k.mu.Lock()
+	e, ok := k.locks[key]
+	if !ok {
+		e = new(entry)
+		k.locks[key] = e
+	}
+	e.refs++
+	k.mu.Unlock()
+
+	e.mu.Lock()
+
+	return func() {
+		e.mu.Unlock()
+
+		k.mu.Lock()
+		e.refs--
+		if e.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
+}
+
+// LockAll locks every key in a consistent order, so that concurrent callers locking overlapping keys never deadlock,
+// and returns the function unlocking them all
+func (k *KeyMutex) LockAll(keys []string) (unlock func()) {
+	sorted := make([]string, len(keys))
+	copy(sorted, keys)
+	sort.Strings(sorted)
+
+	unlocks := make([]func(), 0, len(sorted))
+	for i, key := range sorted {
+		if i > 0 && key == sorted[i-1] {
+			continue
+		}
+
+		unlocks = append(unlocks, k.Lock(key))
+	}
+
+	return func() {
+		for i := len(unlocks) - 1; i >= 0; i-- {
+			unlocks[i]()
+		}
+	}
+}
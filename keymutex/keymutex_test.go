@@ -0,0 +1,89 @@
+package keymutex
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSameKeySerialized(t *testing.T) {
+	k := New()
+
+	var (
+		wg      sync.WaitGroup
+		inside  int
+		most    int
+		counter int
+	)
+
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				unlock := k.Lock("A1")
+
+				// the lock is the only thing guarding these, so -race flags any overlap
+				inside++
+				if inside > most {
+					most = inside
+				}
+				counter++
+				inside--
+
+				unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if most != 1 {
+		t.Errorf("%d goroutines held the key at once, want 1", most)
+	}
+	if counter != 2000 {
+		t.Errorf("counter = %d, want 2000", counter)
+	}
+	if len(k.locks) != 0 {
+		t.Errorf("%d keys left in the map, want none", len(k.locks))
+	}
+}
+
+func TestDifferentKeysParallel(t *testing.T) {
+	k := New()
+
+	unlock := k.Lock("A1")
+
+	locked := make(chan struct{})
+	go func() {
+		k.Lock("A2")()
+		k.LockAll([]string{"A3", "A2"})()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("locking other keys waited for the held one")
+	}
+
+	blocked := make(chan struct{})
+	go func() {
+		k.LockAll([]string{"A2", "A1"})()
+		close(blocked)
+	}()
+
+	select {
+	case <-blocked:
+		t.Fatal("locking the held key did not wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+
+	select {
+	case <-blocked:
+	case <-time.After(time.Second):
+		t.Fatal("the held key was not handed over after unlocking")
+	}
+}
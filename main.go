@@ -3,39 +3,82 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
-	"github.com/joho/godotenv"
-	_ "github.com/lib/pq"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/volatiletech/sqlboiler/v4/boil"
+	"google.golang.org/grpc"
 	"gopkg.in/natefinch/lumberjack.v2"
 
+	"letovo-computers-server/admin"
+	"letovo-computers-server/alert"
+	"letovo-computers-server/aliases"
+	"letovo-computers-server/api"
+	"letovo-computers-server/bridge"
 	"letovo-computers-server/broker"
+	"letovo-computers-server/changes"
+	"letovo-computers-server/commands"
+	"letovo-computers-server/config"
+	"letovo-computers-server/deadletter"
+	"letovo-computers-server/directory"
+	"letovo-computers-server/events"
+	"letovo-computers-server/firmware"
+	"letovo-computers-server/health"
+	"letovo-computers-server/keymutex"
+	"letovo-computers-server/metrics"
+	"letovo-computers-server/migrations"
 	"letovo-computers-server/models"
+	"letovo-computers-server/occupancy"
+	"letovo-computers-server/outbox"
+	"letovo-computers-server/payloads"
+	"letovo-computers-server/presence"
+	"letovo-computers-server/provisioning"
+	"letovo-computers-server/rawlog"
+	"letovo-computers-server/recent"
+	"letovo-computers-server/replay"
+	"letovo-computers-server/retention"
+	"letovo-computers-server/rpc"
+	"letovo-computers-server/schema"
+	"letovo-computers-server/scopes"
+	"letovo-computers-server/signing"
+	"letovo-computers-server/state"
+	"letovo-computers-server/storage"
 	"letovo-computers-server/types"
+	"letovo-computers-server/watchdog"
+	"letovo-computers-server/webhooks"
 )
 
-func init() {
-	debug := flag.Bool("debug", false, "sets log level to debug")
-	flag.Parse()
+// version and commit are set at build time with -ldflags "-X main.version=... -X main.commit=..."
+var (
+	version = "dev"
+	commit  = "unknown"
+)
 
-	// Default level is info, unless debug flag is present
-	zerolog.SetGlobalLevel(zerolog.InfoLevel)
-	if *debug {
-		zerolog.SetGlobalLevel(zerolog.DebugLevel)
-	}
+var startedAt = time.Now()
+
+var (
+	debug        = flag.Bool("debug", false, "sets log level to debug")
+	verifySchema = flag.Bool("verify-schema", false, "verifies the db schema matches the models at startup")
+)
+
+func init() {
+	metrics.RegisterBuildInfo(version, commit, startedAt)
 
 	zerolog.TimestampFieldName = "timestamp"
 	zerolog.CallerMarshalFunc = func(pc uintptr, file string, line int) string {
@@ -65,27 +108,75 @@ func init() {
 }
 
 func main() {
+	// the flags are parsed here rather than in init, which the test binary runs before parsing its own
+	flag.Parse()
+
+	// Default level is info, unless debug flag is present
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	if *debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	}
+
 	log.Debug().Msg("Starting the server")
 
-	err := godotenv.Load(".env")
+	err := config.Load(".env")
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to load .env file")
 	}
 
-	db, err := sql.Open("postgres", fmt.Sprintf(
-		"postgres://%s:%s@%s:%s/%s?sslmode=%s",
-		os.Getenv("PGUSER"), os.Getenv("PGPASSWORD"), os.Getenv("PGHOST"),
-		os.Getenv("PGPORT"), os.Getenv("PGDATABASE"), os.Getenv("PGSSLMODE"),
-	))
+	db, driver, err := storage.Open()
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to connect to db")
 	}
 
+	configurePool(db, driver)
+	metrics.RegisterDBStats(db, string(driver))
+
 	err = db.Ping()
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to ping db")
 	}
 
+	err = migrations.Select(string(driver))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to select migrations")
+	}
+
+	// the migrate subcommand only runs the migrations, like ./server migrate status
+	if flag.Arg(0) == "migrate" {
+		command, args := "up", []string(nil)
+		if flag.NArg() > 1 {
+			command, args = flag.Arg(1), flag.Args()[2:]
+		}
+
+		err = migrations.Run(db, command, args...)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to run migrations")
+		}
+
+		return
+	}
+
+	if config.Bool("MIGRATE_ON_START", true) {
+		err = migrations.Up(db)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to migrate db")
+		}
+	}
+
+	if version, err := migrations.Version(db); err != nil {
+		log.Warn().Err(err).Msg("failed to get db schema version")
+	} else {
+		log.Info().Int64("version", version).Msgf("db schema is at version %d", version)
+	}
+
+	if *verifySchema {
+		err = schema.Verify(context.Background(), db, string(driver))
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to verify db schema")
+		}
+	}
+
 	boil.SetDB(db)
 	defer func(db *sql.DB) {
 		err := db.Close()
@@ -94,9 +185,24 @@ func main() {
 		}
 	}(db)
 
-	client := broker.Init()
-	if token := client.Connect(); token.Wait() && token.Error() != nil {
-		log.Fatal().Err(token.Error()).Msg("failed to connect to broker")
+	embedded, err := broker.StartEmbedded(new(provisioning.Hook))
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to start embedded broker")
+	}
+	if embedded != nil {
+		defer func() {
+			if err := embedded.Close(); err != nil {
+				log.Error().Err(err).Msg("failed to close embedded broker")
+			}
+		}()
+	}
+
+	// the broker may come up after the server, so keep trying until it does or the server is told to stop
+	connectCtx, stopConnecting := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	client, err := connectBroker(connectCtx)
+	stopConnecting()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to broker")
 	}
 
 	sigs := make(chan os.Signal, 1)
@@ -105,7 +211,7 @@ func main() {
 	quit := make(chan bool, 1)
 
 	go func() {
-		if err := start(client, sigs); err != nil {
+		if err := start(client, db, driver, sigs); err != nil {
 			log.Error().Err(err).Msg("Shutting down the server due to an error")
 		}
 
@@ -116,122 +222,1383 @@ func main() {
 	log.Debug().Msg("Gracefully shut down the server")
 }
 
-func start(client mqtt.Client, sigs chan os.Signal) error {
+func start(client broker.Broker, db *sql.DB, driver storage.Driver, sigs chan os.Signal) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	var wg sync.WaitGroup
 
-	broker.Publish(&wg, client, os.Getenv("SERVER_STREAM_TOPIC"), "hi from go")
+	// the message handlers store the messages through the store, while the api and the other packages
+	// still query the global db
+	store := storage.New(db)
+	go store.Breaker().Run(ctx)
+
+	rec := &recorder{
+		events:   store.Repos().Events,
+		recent:   recent.New(config.Int("RECENT_EVENTS_SIZE", 100)),
+		hub:      events.NewHub(),
+		webhooks: webhooks.NewDispatcher(config.Int("WEBHOOK_QUEUE_SIZE", 1000)),
+	}
+	rec.webhooks.Run(ctx, config.Int("WEBHOOK_WORKERS", 2))
+
+	raw := rawlog.New()
+	go raw.Run(ctx)
+
+	go outbox.Run(ctx, db, driver, client)
+
+	if topic := config.String("SERVER_EVENTS_TOPIC", ""); topic != "" {
+		rec.publisher = events.NewPublisher(client, topic, broker.DeliveryFor("SERVER_EVENTS_TOPIC"),
+			config.Duration("EVENTS_BATCH_WINDOW", 0), config.Int("EVENTS_BATCH_SIZE", 100),
+		)
+	}
+
+	bridged, err := bridge.New()
+	if err != nil {
+		return err
+	}
+	rec.bridge = bridged
+
+	slotAliases, err := aliases.Load(config.String("SLOT_ALIASES_FILE", ""))
+	if err != nil {
+		return err
+	}
+
+	deviceScopes, err := scopes.Load(config.String("DEVICE_SCOPES_FILE", ""))
+	if err != nil {
+		return err
+	}
+
+	deviceSecrets, err := signing.Load(config.String("DEVICE_SECRETS_FILE", ""))
+	if err != nil {
+		return err
+	}
+
+	// enrolled devices sign their payloads with the secrets they were issued, on top of the ones in the file
+	if provisioning.Enabled() {
+		deviceSecrets = deviceSecrets.WithLookup(func(device string) ([][]byte, error) {
+			return provisioning.Secrets(ctx, device)
+		})
+	}
+
+	// payloads are validated against the JSON Schemas unless PAYLOAD_VALIDATION is turned off
+	var validator *payloads.Validator
+	if config.Bool("PAYLOAD_VALIDATION", true) {
+		validator, err = payloads.Load(config.String("PAYLOAD_SCHEMA_DIR", ""))
+		if err != nil {
+			return err
+		}
+	}
+
+	monitor := health.NewMonitor(
+		config.Duration("HEALTH_ERROR_WINDOW", 5*time.Minute),
+		config.Float("HEALTH_ERROR_RATIO_THRESHOLD", 0.5),
+		config.Int("HEALTH_ERROR_MIN_SAMPLES", 10),
+	)
+
+	dispatcher := commands.New(client)
+	go dispatcher.Run(ctx)
+
+	go retention.Run(ctx, driver)
+	go occupancy.Run(ctx)
+
+	// sqlite is served by this instance alone, which publishes the changes it makes itself
+	if driver == storage.Postgres && config.Bool("DB_NOTIFY", true) {
+		go changes.Listen(ctx, storage.PostgresDSN(), client, rec.hub)
+	}
 
-	broker.Subscribe(&wg, client, os.Getenv("ARDUINO_STREAM_TOPIC"), 2,
-		func(ctx context.Context) func(client mqtt.Client, resp mqtt.Message) {
-			return func(client mqtt.Client, resp mqtt.Message) {
-				message := new(types.MQTTMessage)
+	// devices silent for too long are announced as disconnected, as one hanging with its connection
+	// still open never triggers its will
+	go presence.Monitor(ctx, func(device string, lastSeen time.Time) {
+		log.Warn().Str("device", device).Time("last_seen", lastSeen).Msgf("arduino %s stopped sending heartbeats", device)
+		rec.record(&types.MQTTMessage{Device: device, Status: types.Disconnected}, "", nil)
 
-				err := json.Unmarshal(resp.Payload(), message)
+		message := fmt.Sprintf("arduino %s has not sent a heartbeat since %s", device, lastSeen.Format(time.RFC3339))
+		if err := alert.Send(ctx, "heartbeat", message); err != nil {
+			log.Error().Err(err).Msg("failed to send heartbeat alert")
+		}
+	})
+
+	var srv *http.Server
+	if addr := os.Getenv("HTTP_ADDR"); addr != "" {
+		srv = api.New(addr, api.Options{
+			Client:   client,
+			DB:       db,
+			Events:   rec.recent,
+			Hub:      rec.hub,
+			Aliases:  slotAliases,
+			Health:   monitor,
+			Scopes:   deviceScopes,
+			Commands: dispatcher,
+		})
+
+		listener, err := api.Listen(ctx, addr, config.Bool("HTTP_REUSEPORT", false))
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+
+		go func() {
+			if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error().Err(err).Msg("failed to serve http")
+			}
+		}()
+	}
+
+	var grpcSrv *grpc.Server
+	if addr := os.Getenv("GRPC_ADDR"); addr != "" {
+		grpcSrv = rpc.New(rpc.Options{
+			Client:  client,
+			Hub:     rec.hub,
+			Aliases: slotAliases,
+		})
+
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+
+		go func() {
+			if err := grpcSrv.Serve(listener); err != nil {
+				log.Error().Err(err).Msg("failed to serve grpc")
+			}
+		}()
+	}
+
+	var deadman *watchdog.Watchdog
+	if window := config.Duration("DEADMAN_WINDOW", 0); window > 0 {
+		deadman = watchdog.New(window, func(window time.Duration) {
+			log.Error().Dur("window", window).Msgf("no valid messages received for %s", window)
+
+			err := alert.Send(ctx, "deadman", fmt.Sprintf("no valid messages received for %s", window))
+			if err != nil {
+				log.Error().Err(err).Msg("failed to send deadman alert")
+			}
+		})
+		defer deadman.Stop()
+	}
+
+	// unknown plaintext payloads are logged at a sampled rate, so misbehaving firmware does not flood the logs
+	plaintextLog := log.Sample(&zerolog.BurstSampler{Burst: 5, Period: time.Minute})
+
+	hello, err := json.Marshal(types.Hello{
+		ServerID:  os.Getenv("MQTT_CLIENT_ID"),
+		Version:   version,
+		StartedAt: startedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal hello: %w", err)
+	}
+
+	broker.Publish(&wg, client, config.String("SERVER_HELLO_TOPIC", config.String("SERVER_STREAM_TOPIC", "")+"/hello"),
+		broker.DeliveryFor("SERVER_HELLO_TOPIC"), string(hello),
+	)
+
+	streamHandler := func(ctx context.Context) func(client mqtt.Client, resp mqtt.Message) {
+		return func(client mqtt.Client, resp mqtt.Message) {
+			// the ack is only withheld for failures worth a redelivery, see broker.ManualAck
+			ack := true
+
+			// messages failing to be processed are dead-lettered, unless the broker redelivers them
+			var stage string
+			var failure error
+
+			defer func() {
+				raw.Record(deviceKey(resp), resp, stage, failure)
+
+				if failure != nil && (ack || !broker.ManualAck()) {
+					if err := deadletter.Send(ctx, client, resp, stage, failure); err != nil {
+						log.Error().Err(err).Str("topic", resp.Topic()).Msg("failed to dead-letter message")
+					}
+				}
+
+				if ack {
+					resp.Ack()
+				}
+			}()
+
+			// devices publishing to a wildcard subscription are named after the cabinet in their topic
+			cabinetID := broker.CabinetID(config.String("ARDUINO_STREAM_TOPIC", ""), payloads.TrimEncoding(resp.Topic()))
+
+			payload, err := deviceSecrets.Verify(cabinetID, resp.Payload())
+			if err != nil {
+				log.Warn().Err(err).Str("topic", resp.Topic()).Msg("rejected unauthenticated message")
+				stage, failure = deadletter.StageAuthenticate, err
+				return
+			}
+
+			// binary payloads are turned into their JSON counterpart, going through the same checks
+			if encoding := payloads.EncodingOf(resp); encoding != payloads.JSON {
+				payload, err = payloads.ToJSON(encoding, payload)
 				if err != nil {
-					log.Error().Err(err).Msg("failed to unmarshal message")
+					log.Error().Err(err).Msg("failed to decode binary message")
+					metrics.ObserveParseFailure("ARDUINO_STREAM_TOPIC")
+					stage, failure = deadletter.StageDecode, err
 					return
 				}
+			}
 
-				switch message.Status {
-				case types.Placed:
-					log.Info().
-						Str("RFID", message.RFID).
-						Str("slots", message.Slots).
-						Int("status", int(message.Status)).
-						Msgf("%s placed computer to %s", message.RFID, message.Slots)
-
-					for _, slotID := range strings.Split(message.Slots, ";") {
-						if slotID == "" {
-							continue
-						}
-
-						slot := models.Slot{
-							ID:      slotID,
-							TakenBy: message.RFID,
-							IsTaken: false,
-						}
-
-						err = slot.UpsertG(ctx, true, []string{"id"},
-							boil.Whitelist("taken_by", "is_taken"), boil.Infer(),
-						)
-						if err != nil {
-							log.Error().Err(err).Msg("failed to upsert slot to db in Placed case")
-						}
-					}
+			if !json.Valid(payload) {
+				handlePlaintext(client, string(bytes.TrimSpace(payload)), plaintextLog)
+				return
+			}
 
-				case types.Taken:
-					log.Info().
-						Str("RFID", message.RFID).
-						Str("slots", message.Slots).
-						Int("status", int(message.Status)).
-						Msgf("%s took computer from %s", message.RFID, message.Slots)
-
-					for _, slotID := range strings.Split(message.Slots, ";") {
-						if slotID == "" {
-							continue
-						}
-
-						slot := models.Slot{
-							ID:      slotID,
-							TakenBy: message.RFID,
-							IsTaken: true,
-						}
-
-						err = slot.UpsertG(ctx, true, []string{"id"},
-							boil.Whitelist("taken_by", "is_taken"), boil.Infer(),
-						)
-						if err != nil {
-							log.Error().Err(err).Msg("failed to upsert slot to db in Taken case")
-						}
-					}
+			// devices catching up after being offline send their events as an array, stored together
+			if payloads.IsBatch(payload) {
+				if deadman != nil {
+					deadman.Feed()
+				}
+
+				stage, failure = processBatch(ctx, store, client, resp, cabinetID, payload, validator, deviceScopes, rec, monitor)
+				ack = stage != deadletter.StagePersist || rejected(failure)
+				return
+			}
+
+			err = validator.Validate("ARDUINO_STREAM_TOPIC", payload)
+			if err != nil {
+				log.Warn().Err(err).Str("topic", resp.Topic()).Msg("rejected message violating its schema")
+				metrics.ObserveParseFailure("ARDUINO_STREAM_TOPIC")
+				stage, failure = deadletter.StageValidate, err
 
-				case types.Scanned:
-					log.Info().
-						Str("RFID", message.RFID).
-						Int("status", int(message.Status)).
-						Msgf("scanned the %s tag ", message.RFID)
+				// the nack is keyed by whatever RFID the payload carries
+				rejectedMessage := new(types.MQTTMessage)
+				_ = json.Unmarshal(payload, rejectedMessage)
+				nack(client, rejectedMessage, err)
+				return
+			}
+
+			message := new(types.MQTTMessage)
+
+			err = json.Unmarshal(payload, message)
+			if err != nil {
+				log.Error().Err(err).Msg("failed to unmarshal message")
+				metrics.ObserveParseFailure("ARDUINO_STREAM_TOPIC")
+				stage, failure = deadletter.StageDecode, err
+				return
+			}
+			message.Payload = resp.Payload()
+
+			if message.Device == "" {
+				message.Device = cabinetID
+			}
+
+			err = message.Validate()
+			if err != nil {
+				log.Warn().Err(err).Str("RFID", message.RFID).Msg("rejected invalid message")
+				nack(client, message, err)
+				return
+			}
+
+			// a redelivered message was processed already, so it is acknowledged again for the device to stop
+			// retrying it, without toggling the slots a second time
+			err = replay.Check(ctx, message.Device, message.Seq, message.SentAt)
+			switch {
+			case errors.Is(err, replay.ErrDuplicate):
+				log.Debug().Err(err).Str("RFID", message.RFID).Msg("dropped duplicate message")
+				acknowledge(client, resp, message, nil)
+				return
+			case errors.Is(err, replay.ErrOutOfWindow):
+				log.Warn().Err(err).Str("RFID", message.RFID).Msg("rejected replayed message")
+				nack(client, message, err)
+				stage, failure = deadletter.StageReplay, err
+				return
+			case err != nil:
+				log.Error().Err(err).Msg("failed to check message sequence")
+				ack = false
+				stage, failure = deadletter.StagePersist, err
+				return
+			}
+
+			if deadman != nil {
+				deadman.Feed()
+			}
+
+			switch message.Status {
+			case types.Placed:
+				log.Info().
+					Str("RFID", message.RFID).
+					Str("slots", message.Slots).
+					Int("status", int(message.Status)).
+					Msgf("%s placed computer to %s", message.RFID, message.Slots)
+
+				slotIDs := scopeSlots(deviceScopes, rec, message, message.SlotIDs())
+
+				err = upsertSlots(ctx, store, message, slotIDs, false, cabinetID, ackOf(resp, message, nil))
+				if errors.Is(err, replay.ErrDuplicate) {
+					log.Debug().Err(err).Str("RFID", message.RFID).Msg("dropped duplicate message")
+					acknowledge(client, resp, message, nil)
+					return
+				}
+				if err != nil {
+					log.Error().Err(err).Msg("failed to upsert slots to db in Placed case")
+					ack = rejected(err)
+					stage, failure = deadletter.StagePersist, err
+				} else if err := state.PublishCabinets(ctx, client, slotIDs); err != nil {
+					log.Error().Err(err).Msg("failed to publish cabinet snapshot")
+				}
+				monitor.Observe(err)
+
+				for _, slotID := range slotIDs {
+					rec.recordStored(message, slotID, err)
+				}
+				acknowledgeStored(client, resp, message, err)
+
+			case types.Taken:
+				log.Info().
+					Str("RFID", message.RFID).
+					Str("slots", message.Slots).
+					Int("status", int(message.Status)).
+					Msgf("%s took computer from %s", message.RFID, message.Slots)
+
+				slotIDs := scopeSlots(deviceScopes, rec, message, message.SlotIDs())
+
+				err = upsertSlots(ctx, store, message, slotIDs, true, cabinetID, ackOf(resp, message, nil))
+				if errors.Is(err, replay.ErrDuplicate) {
+					log.Debug().Err(err).Str("RFID", message.RFID).Msg("dropped duplicate message")
+					acknowledge(client, resp, message, nil)
+					return
+				}
+				if err != nil {
+					log.Error().Err(err).Msg("failed to upsert slots to db in Taken case")
+					ack = rejected(err)
+					alertLoanLimit(ctx, message.Device, err)
+					stage, failure = deadletter.StagePersist, err
+				} else if err := state.PublishCabinets(ctx, client, slotIDs); err != nil {
+					log.Error().Err(err).Msg("failed to publish cabinet snapshot")
+				}
+				monitor.Observe(err)
+
+				for _, slotID := range slotIDs {
+					rec.recordStored(message, slotID, err)
+				}
+				acknowledgeStored(client, resp, message, err)
+
+			case types.Scanned:
+				log.Info().
+					Str("RFID", message.RFID).
+					Int("status", int(message.Status)).
+					Msgf("scanned the %s tag ", message.RFID)
 
-					user := models.User{
-						ID: message.RFID,
+				entry, err := directory.Lookup(ctx, message.RFID)
+				if err != nil {
+					log.Error().Err(err).Msg("failed to look up user in directory in Scanned case")
+				}
+
+				user, columns := scannedUser(message, entry)
+
+				reply := ackOf(resp, message, nil)
+				err = store.InTx(ctx, func(repos storage.Repos) error {
+					if err := repos.Devices.Sequence(ctx, message.Device, message.Seq); err != nil {
+						return err
 					}
 
-					err := user.UpsertG(ctx, true, []string{"id"},
-						boil.Whitelist("login"), boil.Infer(),
-					)
-					if err != nil {
-						log.Error().Err(err).Msg("failed to upsert user to db in Scanned case")
+					if err := upsertUserIn(ctx, repos, message, user, columns); err != nil {
+						return err
 					}
 
-				default:
-					log.Warn().
-						Str("RFID", message.RFID).
-						Str("slots", message.Slots).
-						Int("status", int(message.Status)).
-						Msg(message.Message)
+					return repos.Outbox.Enqueue(ctx, reply)
+				})
+				if errors.Is(err, replay.ErrDuplicate) {
+					log.Debug().Err(err).Str("RFID", message.RFID).Msg("dropped duplicate message")
+					acknowledge(client, resp, message, nil)
+					return
+				}
+				if err != nil {
+					log.Error().Err(err).Msg("failed to upsert user to db in Scanned case")
+					ack = false
+					stage, failure = deadletter.StagePersist, err
 				}
+				monitor.Observe(err)
+
+				rec.recordStored(message, "", err)
+				acknowledgeStored(client, resp, message, err)
+
+			default:
+				log.Warn().
+					Str("RFID", message.RFID).
+					Str("slots", message.Slots).
+					Int("status", int(message.Status)).
+					Msg(message.Message)
+
+				err = store.Repos().Devices.Sequence(ctx, message.Device, message.Seq)
+				if err != nil && !errors.Is(err, replay.ErrDuplicate) {
+					log.Error().Err(err).Msg("failed to record message sequence")
+				}
+			}
+		}
+	}(ctx)
+
+	willHandler := func(ctx context.Context) func(client mqtt.Client, resp mqtt.Message) {
+		return func(client mqtt.Client, resp mqtt.Message) {
+			device := presence.Parse("ARDUINO_WILL_TOPIC", resp).Device
+			message := &types.MQTTMessage{Device: device, Status: types.Disconnected, Payload: resp.Payload()}
+
+			log.Warn().Msgf("arduino %s is offline", device)
+			if device == "" {
+				rec.record(message, "", nil)
+				return
 			}
-		}(ctx))
 
-	broker.Subscribe(&wg, client, os.Getenv("ARDUINO_WILL_TOPIC"), 2,
-		func(ctx context.Context) func(client mqtt.Client, resp mqtt.Message) {
-			return func(client mqtt.Client, resp mqtt.Message) {
-				log.Warn().Msgf("arduino %s is offline", resp.Payload())
-				log.Debug().Msgf("%s %s %t %d %t %d\n", resp.Topic(), resp.Payload(), resp.Duplicate(), resp.Qos(), resp.Retained(), resp.MessageID())
+			err := store.InTx(ctx, func(repos storage.Repos) error {
+				if err := repos.Devices.Offline(ctx, device, time.Now()); err != nil {
+					return err
+				}
+
+				return repos.Events.Insert(ctx, eventRow(message, "", nil))
+			})
+			if err != nil {
+				log.Error().Err(err).Msg("failed to record will")
 			}
-		}(ctx),
+			rec.recordStored(message, "", err)
+		}
+	}(ctx)
+
+	// the stream is handled by the workers, keeping the slow db upserts off the client goroutine
+	pool := broker.NewPool(
+		config.Int("MQTT_WORKERS", 4),
+		config.Int("MQTT_WORKER_QUEUE_SIZE", 100),
+		config.String("MQTT_WORKER_OVERFLOW", broker.OverflowBlock),
 	)
 
+	router := broker.NewRouter(broker.Recovery, broker.Metrics, broker.Logging)
+	// the upserts set the state a message reports rather than changing it, so any instance of the share group
+	// may handle any message. The replies and admin commands stay with every instance, as the instance that
+	// sent a command is the one waiting for its reply
+	// the breaker holds the stream on the workers while the db is down, and with their queues full the client
+	// stops taking messages from the broker
+	router.HandleShared("ARDUINO_STREAM_TOPIC", streamHandler, pool.Middleware(deviceKey), store.Breaker().Middleware(ctx))
+	router.HandleShared("ARDUINO_WILL_TOPIC", broker.Acked(willHandler))
+	router.HandleShared("ARDUINO_BIRTH_TOPIC", broker.Acked(presence.HandleBirth(ctx)))
+	router.HandleShared("ARDUINO_HEARTBEAT_TOPIC", broker.Acked(presence.HandleHeartbeat(ctx)))
+	router.HandleShared("ARDUINO_FIRMWARE_REQUEST_TOPIC", broker.Acked(firmware.HandleRequest(ctx)), validator.Middleware)
+	router.HandleShared("DEVICE_ENROLL_TOPIC", broker.Acked(provisioning.HandleEnrollment(ctx)))
+	router.Handle("SERVER_COMMAND_TOPIC", broker.Acked(admin.Handle(ctx)))
+	router.Handle("ARDUINO_REPLY_TOPIC", broker.Acked(dispatcher.HandleReply), validator.Middleware)
+
+	if rec.bridge != nil {
+		if err := rec.bridge.Start(router); err != nil {
+			return err
+		}
+	}
+
+	subscribeCtx, cancelSubscribe := context.WithTimeout(ctx, config.Duration("MQTT_SUBSCRIBE_TIMEOUT", 10*time.Second))
+	err = router.Subscribe(subscribeCtx, client)
+	cancelSubscribe()
+	if err != nil {
+		return err
+	}
+
 	wg.Wait()
 
 	log.Info().Msg("Server is ready to handle requests")
 
+	hups := make(chan os.Signal, 1)
+	signal.Notify(hups, syscall.SIGHUP)
+	defer signal.Stop(hups)
+
+	for {
+		select {
+		case <-hups:
+			reload(client, db, driver, router, monitor)
+
+		case <-sigs:
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), config.Duration("SHUTDOWN_TIMEOUT", 30*time.Second))
+			shutdown(shutdownCtx, client, router, pool, srv, grpcSrv, rec)
+			cancel()
+
+			return nil
+		}
+	}
+}
+
+// shutdown stops taking messages and lets the ones being handled finish before disconnecting, so that none is cut off
+// mid-upsert, giving up on them once ctx is done. The servers go first, as their requests may publish
+func shutdown(
+	ctx context.Context, client broker.Broker, router *broker.Router, pool *broker.Pool,
+	srv *http.Server, grpcSrv *grpc.Server, rec *recorder,
+) {
+	if srv != nil {
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Error().Err(err).Msg("failed to shut down http server")
+		}
+	}
+
+	if grpcSrv != nil {
+		grpcSrv.GracefulStop()
+	}
+
+	if err := router.Drain(ctx, client); err != nil {
+		log.Warn().Err(err).Msg("failed to drain subscriptions")
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		pool.Close()
+		close(drained)
+	}()
+
 	select {
-	case <-sigs:
-		client.Disconnect(250)
+	case <-drained:
+	case <-ctx.Done():
+		log.Warn().Msg("abandoning the queued messages, the workers did not finish in time")
+	}
+
+	if rec.publisher != nil {
+		rec.publisher.Close()
+	}
+
+	if rec.bridge != nil {
+		rec.bridge.Close(ctx)
+	}
+
+	if err := broker.Goodbye(ctx, client); err != nil {
+		log.Error().Err(err).Msg("failed to announce shutdown")
+	}
+
+	client.Disconnect(250)
+}
+
+// reload re-reads the mutable settings from the .env file, resubscribing the routes whose topics changed
+func reload(client broker.Broker, db *sql.DB, driver storage.Driver, router *broker.Router, monitor *health.Monitor) {
+	changes, err := config.Reload(".env")
+	if err != nil {
+		log.Error().Err(err).Msg("failed to reload config")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Duration("MQTT_SUBSCRIBE_TIMEOUT", 10*time.Second))
+	defer cancel()
+
+	for _, change := range changes {
+		log.Info().Str("key", change.Key).Msgf("reloaded %s", change.Key)
+
+		if err := router.Resubscribe(ctx, client, change); err != nil {
+			log.Error().Err(err).Str("key", change.Key).Msg("failed to resubscribe")
+		}
+	}
+
+	monitor.Configure(
+		config.Duration("HEALTH_ERROR_WINDOW", 5*time.Minute),
+		config.Float("HEALTH_ERROR_RATIO_THRESHOLD", 0.5),
+		config.Int("HEALTH_ERROR_MIN_SAMPLES", 10),
+	)
+
+	configurePool(db, driver)
+}
+
+// configurePool sizes the db connection pool. Capping the open connections keeps the morning rush at the cabinets
+// from exhausting the connections of Postgres, the handlers waiting for a free connection instead, while recycling
+// them lets the pool shrink back once it is over. Sqlite takes a single writer at a time, so its transactions
+// queue for the one connection rather than fail on the lock
+func configurePool(db *sql.DB, driver storage.Driver) {
+	if driver == storage.SQLite {
+		db.SetMaxOpenConns(1)
+		return
+	}
+
+	db.SetMaxOpenConns(config.Int("DB_MAX_OPEN_CONNS", 20))
+	db.SetMaxIdleConns(config.Int("DB_MAX_IDLE_CONNS", 10))
+	db.SetConnMaxLifetime(config.Duration("DB_CONN_MAX_LIFETIME", 30*time.Minute))
+	db.SetConnMaxIdleTime(config.Duration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute))
+}
+
+// deviceKey keys the stream messages by the device reporting them, falling back to their topic. A slot is only
+// reported by the device it belongs to, so the messages of every slot keep their order across the workers
+func deviceKey(msg mqtt.Message) string {
+	var message struct {
+		Device string `json:"device"`
+	}
+
+	if err := json.Unmarshal(msg.Payload(), &message); err == nil && message.Device != "" {
+		return message.Device
+	}
+
+	return payloads.TrimEncoding(msg.Topic())
+}
+
+// ackOf is the ack of the outcome of storing the message, to the response topic of its device: the SERVER_ACK_TOPIC
+// suffixed with the device name, if it is configured, or nil. MQTT 5 devices name their response topic in the
+// message instead, the ack echoing its correlation data. The ack is encoded like the message, under the same
+// topic suffix if the message had one
+func ackOf(resp mqtt.Message, message *types.MQTTMessage, err error) *models.OutboxMessage {
+	props, _ := broker.MessageProperties(resp)
+	encoding := payloads.EncodingOf(resp)
+
+	topic := props.ResponseTopic
+	if topic == "" {
+		topic = config.String("SERVER_ACK_TOPIC", "")
+		if topic == "" {
+			return nil
+		}
+
+		if message.Device != "" {
+			topic += "/" + message.Device
+		}
+		topic += payloads.TopicSuffix(resp.Topic())
+	}
+
+	response := types.Ack{
+		ID:     message.ID,
+		RFID:   message.RFID,
+		Status: message.Status,
+		OK:     err == nil,
+		Result: types.ResultOK,
+	}
+	if err != nil {
+		response.Result = err.Error()
+	}
+
+	payload, contentType, err := payloads.MarshalAck(encoding, response)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to marshal ack")
+		return nil
+	}
+
+	return outbox.Message(topic, broker.DeliveryFor("SERVER_ACK_TOPIC"), payload, broker.Properties{
+		CorrelationData: props.CorrelationData,
+		ContentType:     contentType,
+	})
+}
+
+// acknowledge publishes the ack of the message right away, for the outcomes no ack was queued along with, like
+// the failures to store the message and the duplicates of the stored ones
+func acknowledge(client broker.Broker, resp mqtt.Message, message *types.MQTTMessage, err error) {
+	ack := ackOf(resp, message, err)
+	if ack == nil {
+		return
+	}
+
+	// these acks only matter to the device while it waits for them, so they are not retried
+	t := broker.PublishWithProperties(client, ack.Topic, byte(ack.Qos), ack.Retained, ack.Payload, broker.Properties{
+		CorrelationData: ack.CorrelationData,
+		ContentType:     ack.ContentType,
+	})
+	go func() {
+		<-t.Done()
+		if t.Error() != nil {
+			log.Error().Err(t.Error()).Str("topic", ack.Topic).Msg("failed to publish ack")
+		}
+	}()
+}
+
+// acknowledgeStored acknowledges the outcome of storing the message. The ack of the stored message was queued in
+// the outbox along with it, so it is only published here if storing it failed
+func acknowledgeStored(client broker.Broker, resp mqtt.Message, message *types.MQTTMessage, err error) {
+	if err != nil {
+		acknowledge(client, resp, message, err)
+		return
+	}
+
+	outbox.Wake()
+}
+
+// nack publishes the reason the message was rejected, by its schema or by Validate, to the SERVER_NACK_TOPIC,
+// if it is configured
+func nack(client broker.Broker, message *types.MQTTMessage, err error) {
+	topic := config.String("SERVER_NACK_TOPIC", "")
+	if topic == "" {
+		return
+	}
+
+	validationErr := new(types.ValidationError)
+	schemaErr := new(payloads.Error)
+	switch {
+	case errors.As(err, &validationErr):
+	case errors.As(err, &schemaErr) && len(schemaErr.Violations) > 0:
+		// the schema is checked first, so the violation stands in for the error Validate would have returned
+		violation := schemaErr.Violations[0]
+		validationErr = &types.ValidationError{Field: strings.TrimPrefix(violation.Location, "/"), Reason: violation.Reason}
+	default:
+		validationErr = &types.ValidationError{Reason: err.Error()}
+	}
+
+	payload, err := json.Marshal(struct {
+		RFID string `json:"RFID"`
+		*types.ValidationError
+	}{
+		RFID:            message.RFID,
+		ValidationError: validationErr,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to marshal nack")
+		return
+	}
+
+	var wg sync.WaitGroup
+	broker.Publish(&wg, client, topic, broker.DeliveryFor("SERVER_NACK_TOPIC"), string(payload))
+}
+
+// recorder reports the outcome of processing messages
+type recorder struct {
+	events    storage.EventRepo
+	recent    *recent.Buffer
+	hub       *events.Hub
+	publisher *events.Publisher
+	bridge    *bridge.Bridge
+	webhooks  *webhooks.Dispatcher
+}
+
+// record logs the outcome of processing the message to the events table and the recent events, broadcasts it
+// to the streaming clients, publishes it to the SERVER_EVENTS_TOPIC, mirrors it to the bridged broker and delivers it
+// to the webhooks
+func (r *recorder) record(message *types.MQTTMessage, slotID string, err error) {
+	row := eventRow(message, slotID, err)
+	if err := r.events.Insert(context.Background(), row); err != nil {
+		log.Error().Err(err).Msg("failed to log event")
+	}
+
+	r.publish(row)
+}
+
+// recordStored records the outcome of storing the message like record does. The event of a message stored
+// successfully was logged to the events table along with it, so only the failures are logged here
+func (r *recorder) recordStored(message *types.MQTTMessage, slotID string, err error) {
+	if err != nil {
+		r.record(message, slotID, err)
+		return
+	}
+
+	r.publish(eventRow(message, slotID, nil))
+}
+
+// eventRow is the row logging the outcome of processing the message to the events table, along with its payload
+func eventRow(message *types.MQTTMessage, slotID string, err error) *models.Event {
+	result := types.ResultOK
+	if err != nil {
+		result = err.Error()
+	}
+
+	payload := message.Payload
+	if payload == nil {
+		payload = []byte{}
+	}
+
+	return &models.Event{
+		ReceivedAt: time.Now(),
+		Device:     message.Device,
+		Rfid:       message.RFID,
+		Slot:       slotID,
+		Status:     int16(message.Status),
+		Result:     result,
+		Payload:    payload,
+		Laptop:     message.Laptop,
+	}
+}
+
+func (r *recorder) publish(row *models.Event) {
+	event := types.Event{
+		Timestamp: row.ReceivedAt,
+		Device:    row.Device,
+		RFID:      row.Rfid,
+		Slot:      row.Slot,
+		Status:    types.Status(row.Status),
+		Result:    row.Result,
+	}
+
+	r.recent.Add(event)
+	r.hub.Broadcast(event)
+	r.webhooks.Dispatch(event)
+	if r.publisher != nil {
+		r.publisher.Publish(event)
+	}
+	if r.bridge != nil {
+		r.bridge.Mirror(event)
+	}
+}
+
+// initBroker creates the broker client, replaced by the tests
+var initBroker = broker.Init
+
+// connectBroker creates the client, which republishes the retained state every time it (re)connects, and connects it
+func connectBroker(ctx context.Context) (broker.Broker, error) {
+	client, err := initBroker(republishState)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure broker connection: %w", err)
+	}
+
+	err = broker.ConnectWithRetry(ctx, client, config.Duration("MQTT_CONNECT_TIMEOUT", 30*time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// republishState restores the retained state every time the connection is (re)established,
+// in case the broker has lost it while restarting
+func republishState(client broker.Broker) {
+	if !state.Enabled() && !state.CabinetsEnabled() {
+		return
+	}
+
+	if err := state.Republish(context.Background(), client); err != nil {
+		log.Error().Err(err).Msg("failed to republish retained state")
+	}
+}
+
+// handlePlaintext handles the control strings old debug firmware publishes instead of JSON
+func handlePlaintext(client broker.Broker, payload string, sampled zerolog.Logger) {
+	switch strings.ToLower(payload) {
+	case "ping":
+		var wg sync.WaitGroup
+		broker.Publish(&wg, client, config.String("SERVER_STREAM_TOPIC", ""), broker.DeliveryFor("SERVER_STREAM_TOPIC"), "pong")
+
+	case "hello":
+		log.Debug().Msg("arduino said hello")
+
+	default:
+		sampled.Error().Str("payload", payload).Msg("failed to handle non-JSON message")
+	}
+}
+
+// resolveLogin picks the login of a scanned user. When both the message and the directory provide one,
+// LOGIN_PRECEDENCE decides which wins
+func resolveLogin(messageLogin, directoryLogin string) string {
+	switch {
+	case messageLogin == "":
+		return directoryLogin
+	case directoryLogin == "":
+		return messageLogin
+	case config.String("LOGIN_PRECEDENCE", "message") == "directory":
+		return directoryLogin
+	default:
+		return messageLogin
+	}
+}
+
+// scannedUser builds the scanned user from the message and the directory entry, along with the columns to update
+// on an existing user. Without a known login the tag is stored as it is, keeping a previously bound login intact,
+// while the profile is only updated with what the directory has
+func scannedUser(message *types.MQTTMessage, entry directory.Entry) (*models.User, []string) {
+	user := &models.User{ID: message.RFID, Login: resolveLogin(message.Login, entry.Login)}
+
+	var columns []string
+	if user.Login != "" {
+		columns = append(columns, models.UserColumns.Login)
+	}
+	if entry.Name != "" {
+		user.Name = entry.Name
+		columns = append(columns, models.UserColumns.Name)
+	}
+	if entry.Email != "" {
+		user.Email = entry.Email
+		columns = append(columns, models.UserColumns.Email)
+	}
+	if entry.Class != "" {
+		user.Class = entry.Class
+		columns = append(columns, models.UserColumns.Class)
+	}
+	if entry.Role != "" {
+		if err := types.ValidateUserRole(entry.Role); err != nil {
+			log.Warn().Err(err).Str("RFID", message.RFID).Msg("ignoring role of user in directory")
+		} else {
+			user.Role = entry.Role
+			columns = append(columns, models.UserColumns.Role)
+		}
+	}
+
+	return user, columns
+}
+
+var (
+	errTooManySlots  = errors.New("too many slots in a single message")
+	errTooManyEvents = errors.New("too many events in a single batch")
+	errOutOfScope    = errors.New("slot is out of the device scope")
+)
+
+// slotLocks serializes transitions of the same slot within the process
+var slotLocks = keymutex.New()
+
+// scopeSlots drops the slots the device that sent the message is not permitted to report
+func scopeSlots(deviceScopes *scopes.Scopes, rec *recorder, message *types.MQTTMessage, slotIDs []string) []string {
+	allowed := slotIDs[:0]
+	for _, slotID := range slotIDs {
+		if deviceScopes.Allowed(message.Device, slotID) {
+			allowed = append(allowed, slotID)
+			continue
+		}
+
+		log.Warn().
+			Str("device", message.Device).
+			Str("RFID", message.RFID).
+			Str("slot", slotID).
+			Msgf("rejected %s reported by %s as out of its scope", slotID, message.Device)
+		rec.record(message, slotID, errOutOfScope)
+	}
+
+	return allowed
+}
+
+// rejected reports whether storing the message failed for a reason its redelivery would run into again
+func rejected(err error) bool {
+	return errors.Is(err, errTooManySlots) || errors.Is(err, storage.ErrLoanLimit) ||
+		errors.Is(err, storage.ErrDeletedSlot)
+}
+
+// alertLoanLimit alerts of the takes rejected for their user holding the MAX_LOANS_PER_USER laptops already
+func alertLoanLimit(ctx context.Context, device string, err error) {
+	if !errors.Is(err, storage.ErrLoanLimit) {
+		return
+	}
+
+	log.Warn().Err(err).Str("device", device).Msg("rejected take past the loan limit")
+	metrics.LoanLimitRejections.Inc()
+
+	text := fmt.Sprintf("rejected take reported by %s: %v", device, err)
+	if err := alert.Send(ctx, "loan-limit", text); err != nil {
+		log.Error().Err(err).Msg("failed to send loan limit alert")
+	}
+}
+
+// upsertSlots assigns the slots to the RFID of the message, filing them under the cabinet when it is known, and logs
+// its events along with them. Messages referencing more than MAX_SLOTS_PER_TX slots are either split into several
+// bounded transactions or rejected, depending on OVERSIZED_SLOTS_MODE. The ack, if any, is queued in the outbox
+// and the sequence number of the message recorded by the last of the transactions, which fails with
+// replay.ErrDuplicate for a message stored already
+func upsertSlots(ctx context.Context, store storage.Store, message *types.MQTTMessage, slotIDs []string, isTaken bool,
+	cabinetID string, ack *models.OutboxMessage,
+) error {
+	limit := config.Int("MAX_SLOTS_PER_TX", 0)
+	if limit <= 0 || len(slotIDs) <= limit {
+		return upsertSlotsTx(ctx, store, message, slotIDs, isTaken, cabinetID, ack, true)
+	}
+
+	if config.String("OVERSIZED_SLOTS_MODE", "split") == "reject" {
+		return fmt.Errorf("%w: got %d, max %d", errTooManySlots, len(slotIDs), limit)
+	}
+
+	log.Warn().
+		Str("RFID", message.RFID).
+		Int("slots", len(slotIDs)).
+		Int("limit", limit).
+		Msgf("splitting %d slots into transactions of at most %d", len(slotIDs), limit)
+
+	for start := 0; start < len(slotIDs); start += limit {
+		end := start + limit
+		if end > len(slotIDs) {
+			end = len(slotIDs)
+		}
+
+		last := end == len(slotIDs)
+
+		var lastAck *models.OutboxMessage
+		if last {
+			lastAck = ack
+		}
+
+		err := upsertSlotsTx(ctx, store, message, slotIDs[start:end], isTaken, cabinetID, lastAck, last)
+		if err != nil {
+			return fmt.Errorf("committed %d of %d slots: %w", start, len(slotIDs), err)
+		}
+	}
+
+	return nil
+}
+
+// upsertSlotsTx assigns the slots to the RFID of the message inside a single transaction, queuing their retained
+// state and the ack, if any, along with them, and recording the sequence number of the message first if record
+// is set
+func upsertSlotsTx(ctx context.Context, store storage.Store, message *types.MQTTMessage, slotIDs []string, isTaken bool,
+	cabinetID string, ack *models.OutboxMessage, record bool,
+) error {
+	unlock := slotLocks.LockAll(slotIDs)
+	defer unlock()
+
+	return store.InTx(ctx, func(repos storage.Repos) error {
+		if record {
+			if err := repos.Devices.Sequence(ctx, message.Device, message.Seq); err != nil {
+				return err
+			}
+		}
+
+		if err := upsertSlotsIn(ctx, repos, message, slotIDs, isTaken, cabinetID); err != nil {
+			return err
+		}
+
+		if err := state.Queue(ctx, repos, slotIDs); err != nil {
+			return err
+		}
+
+		return repos.Outbox.Enqueue(ctx, ack)
+	})
+}
+
+// upsertSlotsIn assigns the slots to the RFID of the message within the transaction and logs an event per slot,
+// the caller holding their locks. The slots, their events and their loans are each stored in a single statement,
+// however many slots a resync reports. The events record the laptop the device read, falling back to the one stored
+// in the slot, whose status follows it being taken and returned. Taking the slot opens a loan due in
+// LOAN_DURATION, which placing it back closes. A slot changed by another instance meanwhile fails the transaction,
+// which is run again from the state it left and dead-lettered once out of attempts
+func upsertSlotsIn(ctx context.Context, repos storage.Repos, message *types.MQTTMessage, slotIDs []string,
+	isTaken bool, cabinetID string,
+) error {
+	if len(slotIDs) == 0 {
+		return nil
+	}
+
+	columns := []string{"taken_by", "is_taken"}
+	if cabinetID != "" {
+		columns = append(columns, "cabinet_id")
+	}
+
+	// a slot repeated in the message is upserted once, as a statement cannot update a row twice
+	slots := make(models.SlotSlice, 0, len(slotIDs))
+	distinct := make([]string, 0, len(slotIDs))
+	seen := make(map[string]bool, len(slotIDs))
+	for _, slotID := range slotIDs {
+		if seen[slotID] {
+			continue
+		}
+		seen[slotID] = true
+
+		distinct = append(distinct, slotID)
+		slots = append(slots, &models.Slot{
+			ID:        slotID,
+			TakenBy:   message.RFID,
+			IsTaken:   isTaken,
+			CabinetID: cabinetID,
+		})
+	}
+
+	if err := repos.Slots.UpsertAll(ctx, slots, columns...); err != nil {
+		return err
+	}
+
+	var homed map[string]string
+	if message.Laptop == "" {
+		var err error
+		if homed, err = repos.Laptops.HomedAt(ctx, distinct...); err != nil {
+			return err
+		}
+	}
+
+	rows := make(models.EventSlice, 0, len(slotIDs))
+	for _, slotID := range slotIDs {
+		row := eventRow(message, slotID, nil)
+		if row.Laptop == "" {
+			row.Laptop = homed[slotID]
+		}
+
+		rows = append(rows, row)
+	}
+
+	at := rows[0].ReceivedAt
+
+	var laptops []string
+	loans := make([]*models.Loan, 0, len(distinct))
+	for _, slotID := range distinct {
+		laptop := message.Laptop
+		if laptop == "" {
+			laptop = homed[slotID]
+		}
+		if laptop != "" {
+			laptops = append(laptops, laptop)
+		}
+
+		loans = append(loans, &models.Loan{
+			Rfid:    message.RFID,
+			Slot:    slotID,
+			Laptop:  laptop,
+			TakenAt: at,
+			DueAt:   at.Add(config.Duration("LOAN_DURATION", 8*time.Hour)),
+		})
+	}
+
+	if err := repos.Laptops.Track(ctx, isTaken, laptops...); err != nil {
+		return err
+	}
+
+	var err error
+	if isTaken {
+		err = repos.Loans.Open(ctx, loans...)
+	} else {
+		err = repos.Loans.Close(ctx, at, distinct...)
+	}
+	if err != nil {
+		return err
+	}
+
+	return repos.Events.InsertAll(ctx, rows)
+}
+
+// upsertUserIn stores the scanned user within the transaction, updating the columns of an existing one, and logs
+// the event of the scan
+func upsertUserIn(ctx context.Context, repos storage.Repos, message *types.MQTTMessage, user *models.User,
+	columns []string,
+) error {
+	if err := repos.Users.Upsert(ctx, user, columns...); err != nil {
+		return err
+	}
+
+	if err := repos.Events.Insert(ctx, eventRow(message, "", nil)); err != nil {
+		return fmt.Errorf("user %s: %w", user.ID, err)
 	}
 
 	return nil
 }
+
+// processBatch stores the events of a batch, like the ones a device publishes once back online, inside a single
+// transaction in the order they were sent, so that a slot taken and placed back while offline ends up placed.
+// A batch referencing more than MAX_SLOTS_PER_TX slots is split into several transactions, see splitBatch.
+// Events breaking the schema fail the batch as a whole, while the invalid and duplicate ones are nacked and acked
+// on their own, like the messages they stand for. The stage and error are the ones to dead-letter the batch with
+func processBatch(ctx context.Context, store storage.Store, client broker.Broker, resp mqtt.Message, cabinetID string, payload []byte,
+	validator *payloads.Validator, deviceScopes *scopes.Scopes, rec *recorder, monitor *health.Monitor,
+) (string, error) {
+	elements, err := payloads.SplitBatch(payload)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to unmarshal batch")
+		metrics.ObserveParseFailure("ARDUINO_STREAM_TOPIC")
+		return deadletter.StageDecode, err
+	}
+
+	if limit := config.Int("MAX_BATCH_EVENTS", 100); limit > 0 && len(elements) > limit {
+		log.Warn().Int("events", len(elements)).Int("limit", limit).Msg("rejected oversized batch")
+		return deadletter.StageValidate, fmt.Errorf("%w: got %d, max %d", errTooManyEvents, len(elements), limit)
+	}
+
+	messages := make([]*types.MQTTMessage, 0, len(elements))
+	for i, element := range elements {
+		err := validator.Validate("ARDUINO_STREAM_TOPIC", element)
+		if err != nil {
+			log.Warn().Err(err).Str("topic", resp.Topic()).Int("event", i).Msg("rejected batch violating its schema")
+			metrics.ObserveParseFailure("ARDUINO_STREAM_TOPIC")
+			return deadletter.StageValidate, fmt.Errorf("event %d: %w", i, err)
+		}
+
+		message := new(types.MQTTMessage)
+		if err := json.Unmarshal(element, message); err != nil {
+			log.Error().Err(err).Int("event", i).Msg("failed to unmarshal batched message")
+			metrics.ObserveParseFailure("ARDUINO_STREAM_TOPIC")
+			return deadletter.StageDecode, fmt.Errorf("event %d: %w", i, err)
+		}
+		message.Payload = element
+
+		if message.Device == "" {
+			message.Device = cabinetID
+		}
+		messages = append(messages, message)
+	}
+
+	// the sequence numbers are only recorded along with the batch, so the ones repeated within it are caught here
+	lastSeq := make(map[string]uint64)
+
+	accepted := messages[:0]
+	for _, message := range messages {
+		if err := message.Validate(); err != nil {
+			log.Warn().Err(err).Str("RFID", message.RFID).Msg("rejected invalid batched message")
+			nack(client, message, err)
+			continue
+		}
+
+		err := replay.Check(ctx, message.Device, message.Seq, message.SentAt)
+		if err == nil && message.Seq != 0 && message.Seq <= lastSeq[message.Device] {
+			err = fmt.Errorf("%w %d of %s, repeated within the batch", replay.ErrDuplicate, message.Seq, message.Device)
+		}
+
+		switch {
+		case errors.Is(err, replay.ErrDuplicate):
+			log.Debug().Err(err).Str("RFID", message.RFID).Msg("dropped duplicate batched message")
+			acknowledge(client, resp, message, nil)
+			continue
+		case errors.Is(err, replay.ErrOutOfWindow):
+			log.Warn().Err(err).Str("RFID", message.RFID).Msg("rejected replayed batched message")
+			nack(client, message, err)
+			continue
+		case err != nil:
+			log.Error().Err(err).Msg("failed to check message sequence")
+			return deadletter.StagePersist, err
+		}
+
+		if message.Seq > lastSeq[message.Device] {
+			lastSeq[message.Device] = message.Seq
+		}
+		accepted = append(accepted, message)
+	}
+
+	// the scopes, the directory and the acks are resolved up front, keeping the transaction short
+	slotIDs := make([][]string, len(accepted))
+	users := make([]*models.User, len(accepted))
+	userColumns := make([][]string, len(accepted))
+	acks := make([]*models.OutboxMessage, len(accepted))
+
+	for i, message := range accepted {
+		switch message.Status {
+		case types.Placed, types.Taken:
+			slotIDs[i] = scopeSlots(deviceScopes, rec, message, message.SlotIDs())
+			acks[i] = ackOf(resp, message, nil)
+
+		case types.Scanned:
+			entry, err := directory.Lookup(ctx, message.RFID)
+			if err != nil {
+				log.Error().Err(err).Msg("failed to look up user in directory for batched message")
+			}
+
+			users[i], userColumns[i] = scannedUser(message, entry)
+			acks[i] = ackOf(resp, message, nil)
+		}
+	}
+
+	// the events stored so far, the ones past them failing with err
+	stored := 0
+	ends, splitErr := splitBatch(slotIDs, config.Int("MAX_SLOTS_PER_TX", 0))
+	for _, end := range ends {
+		var runSlotIDs []string
+		for _, ids := range slotIDs[stored:end] {
+			runSlotIDs = append(runSlotIDs, ids...)
+		}
+
+		err = storeBatch(ctx, store, accepted[stored:end], slotIDs[stored:end], users[stored:end],
+			userColumns[stored:end], acks[stored:end], runSlotIDs, cabinetID)
+		if err != nil {
+			break
+		}
+		stored = end
+	}
+	if err == nil {
+		err = splitErr
+	}
+	if err != nil && stored > 0 {
+		err = fmt.Errorf("committed %d of %d events: %w", stored, len(accepted), err)
+	}
+
+	var storedSlotIDs []string
+	for _, ids := range slotIDs[:stored] {
+		storedSlotIDs = append(storedSlotIDs, ids...)
+	}
+
+	if err != nil {
+		log.Error().Err(err).Int("events", len(accepted)).Msg("failed to store batch to db")
+		alertLoanLimit(ctx, deviceKey(resp), err)
+	}
+	if len(storedSlotIDs) > 0 {
+		if err := state.PublishCabinets(ctx, client, storedSlotIDs); err != nil {
+			log.Error().Err(err).Msg("failed to publish cabinet snapshot")
+		}
+	}
+	monitor.Observe(err)
+
+	batchErr := err
+	for i, message := range accepted {
+		err := batchErr
+		if i < stored {
+			err = nil
+		}
+
+		switch message.Status {
+		case types.Placed, types.Taken:
+			log.Info().
+				Str("RFID", message.RFID).
+				Str("slots", message.Slots).
+				Int("status", int(message.Status)).
+				Bool("stored", err == nil).
+				Msgf("%s reported %s for %s while batched", message.RFID, message.Status, message.Slots)
+
+			for _, slotID := range slotIDs[i] {
+				rec.recordStored(message, slotID, err)
+			}
+			acknowledgeStored(client, resp, message, err)
+
+		case types.Scanned:
+			rec.recordStored(message, "", err)
+			acknowledgeStored(client, resp, message, err)
+
+		default:
+			log.Warn().
+				Str("RFID", message.RFID).
+				Str("slots", message.Slots).
+				Int("status", int(message.Status)).
+				Msg(message.Message)
+		}
+	}
+
+	if batchErr != nil {
+		return deadletter.StagePersist, batchErr
+	}
+
+	return "", nil
+}
+
+// splitBatch returns the ends of the runs of events to store in a transaction each, so that none references more
+// than limit slots. The batch is split at the event boundaries when OVERSIZED_SLOTS_MODE is split, keeping the events
+// of a run in a single transaction, and rejected as a whole otherwise. An event referencing more than limit slots
+// on its own cannot be split at its boundaries, so it fails the batch from it on
+func splitBatch(slotIDs [][]string, limit int) ([]int, error) {
+	total := 0
+	for _, ids := range slotIDs {
+		total += len(ids)
+	}
+
+	if limit <= 0 || total <= limit {
+		return []int{len(slotIDs)}, nil
+	}
+
+	if config.String("OVERSIZED_SLOTS_MODE", "split") == "reject" {
+		return nil, fmt.Errorf("%w: got %d in the batch, max %d", errTooManySlots, total, limit)
+	}
+
+	log.Warn().
+		Int("slots", total).
+		Int("limit", limit).
+		Msgf("splitting the batch of %d slots into transactions of at most %d", total, limit)
+
+	var ends []int
+	start, count := 0, 0
+	for i, ids := range slotIDs {
+		if len(ids) > limit {
+			if i > start {
+				ends = append(ends, i)
+			}
+
+			return ends, fmt.Errorf("%w: got %d in event %d, max %d", errTooManySlots, len(ids), i, limit)
+		}
+
+		if count+len(ids) > limit {
+			ends = append(ends, i)
+			start, count = i, 0
+		}
+		count += len(ids)
+	}
+
+	return append(ends, len(slotIDs)), nil
+}
+
+// storeBatch applies the batched messages one after the other inside a single transaction, holding the locks of all
+// their slots until it is committed, and queues the retained state of the slots and the acks along with them. The
+// sequence number of every message is recorded along with it, and the ones stored meanwhile by another instance are
+// skipped as duplicates
+func storeBatch(ctx context.Context, store storage.Store, messages []*types.MQTTMessage, slotIDs [][]string,
+	users []*models.User, userColumns [][]string, acks []*models.OutboxMessage, allSlotIDs []string, cabinetID string,
+) error {
+	unlock := slotLocks.LockAll(allSlotIDs)
+	defer unlock()
+
+	return store.InTx(ctx, func(repos storage.Repos) error {
+		for i, message := range messages {
+			err := repos.Devices.Sequence(ctx, message.Device, message.Seq)
+			if errors.Is(err, replay.ErrDuplicate) {
+				log.Debug().Err(err).Str("RFID", message.RFID).Msg("skipped duplicate batched message")
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("event %d: %w", i, err)
+			}
+
+			switch message.Status {
+			case types.Placed, types.Taken:
+				err = upsertSlotsIn(ctx, repos, message, slotIDs[i], message.Status == types.Taken, cabinetID)
+
+			case types.Scanned:
+				err = upsertUserIn(ctx, repos, message, users[i], userColumns[i])
+			}
+
+			if err != nil {
+				return fmt.Errorf("event %d: %w", i, err)
+			}
+		}
+
+		if err := state.Queue(ctx, repos, allSlotIDs); err != nil {
+			return err
+		}
+
+		return repos.Outbox.Enqueue(ctx, acks...)
+	})
+}
@@ -0,0 +1,551 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/broker/brokertest"
+	"letovo-computers-server/config"
+	"letovo-computers-server/health"
+	"letovo-computers-server/models"
+	"letovo-computers-server/storage"
+	"letovo-computers-server/storage/storagetest"
+	"letovo-computers-server/types"
+)
+
+// testStore is the store of a fresh sqlite db
+func testStore(t *testing.T) storage.Store {
+	t.Helper()
+
+	return storage.New(storagetest.New(t))
+}
+
+// runServer starts the server on a fresh sqlite db with the fake broker, returning once it handles the stream,
+// and stops it when the test ends
+func runServer(t *testing.T) (*brokertest.Fake, *sql.DB) {
+	t.Helper()
+
+	t.Setenv("ARDUINO_STREAM_TOPIC", "arduino/stream")
+	t.Setenv("SERVER_STREAM_TOPIC", "server/stream")
+	t.Setenv("MQTT_SHARE_GROUP", "")
+	// a single worker handles the messages in the order they are delivered, see settle
+	t.Setenv("MQTT_WORKERS", "1")
+
+	db := storagetest.New(t)
+	client := brokertest.New()
+	if token := client.Connect(); token.Error() != nil {
+		t.Fatal(token.Error())
+	}
+
+	sigs := make(chan os.Signal, 1)
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- start(client, db, storage.SQLite, sigs)
+	}()
+
+	t.Cleanup(func() {
+		sigs <- syscall.SIGTERM
+		if err := <-stopped; err != nil {
+			t.Error(err)
+		}
+	})
+
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		for _, topic := range client.Subscriptions() {
+			if topic == "arduino/stream" {
+				return client, db
+			}
+		}
+
+		select {
+		case err := <-stopped:
+			t.Fatalf("server stopped: %v", err)
+		default:
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("server did not subscribe to the stream")
+		}
+	}
+}
+
+// settle waits for the server to handle the messages delivered so far, as the single worker answers
+// a ping delivered after them only once it is done with them
+func settle(t *testing.T, client *brokertest.Fake) {
+	t.Helper()
+
+	pongs := len(client.Published("server/stream"))
+	client.Deliver(brokertest.NewMessage("arduino/stream", []byte("ping"), 1, false))
+
+	eventually(t, "the pong", func() bool {
+		return len(client.Published("server/stream")) > pongs
+	})
+}
+
+// eventually polls the condition until it holds, failing the test if it does not within a few seconds
+func eventually(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); !condition(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+func TestUpsertSlotsOversized(t *testing.T) {
+	slotIDs := []string{"A1", "A2", "A3", "A4", "A5"}
+
+	tests := []struct {
+		mode   string
+		err    error
+		stored int64
+	}{
+		{mode: "split", stored: 5},
+		{mode: "reject", err: errTooManySlots},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			store := testStore(t)
+			t.Setenv("MAX_SLOTS_PER_TX", "2")
+			t.Setenv("OVERSIZED_SLOTS_MODE", tt.mode)
+			t.Setenv("MAX_LOANS_PER_USER", "0")
+
+			ctx := context.Background()
+			if err := (&models.User{ID: "0001"}).InsertG(ctx, boil.Infer()); err != nil {
+				t.Fatal(err)
+			}
+
+			message := &types.MQTTMessage{RFID: "0001", Slots: "A1;A2;A3;A4;A5", Status: types.Taken}
+
+			err := upsertSlots(ctx, store, message, slotIDs, true, "", nil)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("upsertSlots() error = %v, want %v", err, tt.err)
+			}
+
+			stored, err := models.Slots(models.SlotWhere.IsTaken.EQ(true)).CountG(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored != tt.stored {
+				t.Errorf("taken slots = %d, want %d", stored, tt.stored)
+			}
+
+			events, err := models.Events().CountG(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if events != tt.stored {
+				t.Errorf("events = %d, want %d", events, tt.stored)
+			}
+		})
+	}
+}
+
+func TestBatchOversized(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   string
+		batch  string
+		stored int64
+	}{
+		{
+			name:   "split at the events",
+			mode:   "split",
+			batch:  `[{"RFID":"0001","slots":"A1;A2","status":1},{"RFID":"0001","slots":"A3","status":1},{"RFID":"0001","slots":"A4","status":1}]`,
+			stored: 4,
+		},
+		{
+			name:   "split up to an oversized event",
+			mode:   "split",
+			batch:  `[{"RFID":"0001","slots":"A1","status":1},{"RFID":"0001","slots":"A2;A3;A4","status":1}]`,
+			stored: 1,
+		},
+		{
+			name:  "rejected",
+			mode:  "reject",
+			batch: `[{"RFID":"0001","slots":"A1;A2","status":1},{"RFID":"0001","slots":"A3","status":1}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MAX_SLOTS_PER_TX", "2")
+			t.Setenv("OVERSIZED_SLOTS_MODE", tt.mode)
+			t.Setenv("MAX_LOANS_PER_USER", "0")
+			client, _ := runServer(t)
+
+			ctx := context.Background()
+			if err := (&models.User{ID: "0001"}).InsertG(ctx, boil.Infer()); err != nil {
+				t.Fatal(err)
+			}
+
+			client.Deliver(brokertest.NewMessage("arduino/stream", []byte(tt.batch), 1, false))
+			settle(t, client)
+
+			stored, err := models.Slots(models.SlotWhere.IsTaken.EQ(true)).CountG(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored != tt.stored {
+				t.Errorf("taken slots = %d, want %d", stored, tt.stored)
+			}
+		})
+	}
+}
+
+func TestHandlePlaintext(t *testing.T) {
+	tests := []struct {
+		payload string
+		pongs   int
+		logged  bool
+	}{
+		{payload: "ping", pongs: 1},
+		{payload: "PING", pongs: 1},
+		{payload: "hello"},
+		{payload: "reboot", logged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.payload, func(t *testing.T) {
+			t.Setenv("SERVER_STREAM_TOPIC", "server/stream")
+
+			client := brokertest.New()
+			var logs bytes.Buffer
+
+			handlePlaintext(client, tt.payload, zerolog.New(&logs))
+
+			pongs := client.Published("server/stream")
+			if len(pongs) != tt.pongs {
+				t.Fatalf("published %d pongs, want %d", len(pongs), tt.pongs)
+			}
+			if tt.pongs > 0 && string(pongs[0].Payload()) != "pong" {
+				t.Errorf("published %q, want pong", pongs[0].Payload())
+			}
+
+			if logged := strings.Contains(logs.String(), tt.payload); logged != tt.logged {
+				t.Errorf("logged %q, want the payload logged = %t", logs.String(), tt.logged)
+			}
+		})
+	}
+}
+
+func TestResolveLogin(t *testing.T) {
+	tests := []struct {
+		name       string
+		precedence string
+		message    string
+		directory  string
+		login      string
+	}{
+		{name: "neither", precedence: "message"},
+		{name: "message only", precedence: "directory", message: "m.login", login: "m.login"},
+		{name: "directory only", precedence: "message", directory: "d.login", login: "d.login"},
+		{name: "both by default", message: "m.login", directory: "d.login", login: "m.login"},
+		{name: "both, message first", precedence: "message", message: "m.login", directory: "d.login", login: "m.login"},
+		{name: "both, directory first", precedence: "directory", message: "m.login", directory: "d.login", login: "d.login"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LOGIN_PRECEDENCE", tt.precedence)
+
+			if login := resolveLogin(tt.message, tt.directory); login != tt.login {
+				t.Errorf("resolveLogin(%q, %q) = %q, want %q", tt.message, tt.directory, login, tt.login)
+			}
+		})
+	}
+}
+
+func TestReconnectRepublishesState(t *testing.T) {
+	testStore(t)
+	t.Setenv("SERVER_STATE_TOPIC", "server/state")
+
+	ctx := context.Background()
+	if err := (&models.User{ID: "0001"}).InsertG(ctx, boil.Infer()); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&models.Slot{ID: "A1", TakenBy: "0001", IsTaken: true}).InsertG(ctx, boil.Infer()); err != nil {
+		t.Fatal(err)
+	}
+
+	fake := brokertest.New()
+	initBroker = func(onConnect func(client broker.Broker)) (broker.Broker, error) {
+		fake.OnConnect(onConnect)
+		return fake, nil
+	}
+	t.Cleanup(func() { initBroker = broker.Init })
+
+	client, err := connectBroker(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the broker restarted without persistence, so it lost whatever was retained before
+	if token := fake.Restart(); token.Error() != nil {
+		t.Fatal(token.Error())
+	}
+
+	retained := make(map[string]bool)
+	client.Subscribe("server/state/#", 1, func(_ mqtt.Client, msg mqtt.Message) {
+		if msg.Retained() {
+			retained[msg.Topic()] = true
+		}
+	})
+
+	for _, topic := range []string{"server/state", "server/state/A1"} {
+		if !retained[topic] {
+			t.Errorf("%s is not retained after reconnecting, retained %v", topic, retained)
+		}
+	}
+}
+
+func TestStoredSlotsPublishState(t *testing.T) {
+	t.Setenv("SERVER_STATE_TOPIC", "server/state")
+	t.Setenv("MAX_LOANS_PER_USER", "0")
+	client, _ := runServer(t)
+
+	ctx := context.Background()
+	if err := (&models.User{ID: "0001"}).InsertG(ctx, boil.Infer()); err != nil {
+		t.Fatal(err)
+	}
+
+	client.Deliver(brokertest.NewMessage("arduino/stream", []byte(`{"RFID":"0001","slots":"a1","status":1}`), 1, false))
+
+	// the state of the slot and the snapshot are queued along with it, so that they are retained once it is stored
+	eventually(t, "the state of A1", func() bool { return len(client.Published("server/state/A1")) > 0 })
+	eventually(t, "the snapshot", func() bool { return len(client.Published("server/state")) > 0 })
+
+	var slot models.Slot
+	if err := json.Unmarshal(client.Published("server/state/A1")[0].Payload(), &slot); err != nil {
+		t.Fatal(err)
+	}
+	if !slot.IsTaken || slot.TakenBy != "0001" {
+		t.Errorf("state of A1 = %+v, want taken by 0001", slot)
+	}
+}
+
+func TestManualAck(t *testing.T) {
+	t.Setenv("MQTT_MANUAL_ACK", "true")
+	t.Setenv("MAX_LOANS_PER_USER", "0")
+	client, db := runServer(t)
+
+	ctx := context.Background()
+	if err := (&models.User{ID: "0001"}).InsertG(ctx, boil.Infer()); err != nil {
+		t.Fatal(err)
+	}
+
+	taken := brokertest.NewMessage("arduino/stream", []byte(`{"RFID":"0001","slots":"A1","status":1}`), 1, false)
+	client.Deliver(taken)
+	eventually(t, "the stored message to be acked", taken.Acked)
+
+	// with the events gone the message fails to be stored, so the broker is left to redeliver it
+	if _, err := db.Exec("DROP TABLE events"); err != nil {
+		t.Fatal(err)
+	}
+
+	failed := brokertest.NewMessage("arduino/stream", []byte(`{"RFID":"0001","slots":"A2","status":1}`), 1, false)
+	client.Deliver(failed)
+	settle(t, client)
+
+	if failed.Acked() {
+		t.Error("the message failing to be stored was acked")
+	}
+}
+
+func TestNackBadRFID(t *testing.T) {
+	tests := []struct {
+		name       string
+		validation string
+		reason     string
+	}{
+		{name: "by the schema", validation: "true", reason: `does not match pattern '^[\\p{L}\\p{N}]*$'`},
+		{name: "by Validate", validation: "false", reason: "must only contain letters and digits"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PAYLOAD_VALIDATION", tt.validation)
+			t.Setenv("SERVER_NACK_TOPIC", "server/nack")
+			client, _ := runServer(t)
+
+			client.Deliver(brokertest.NewMessage("arduino/stream", []byte(`{"RFID":"00-01","slots":"A1","status":1}`), 1, false))
+			settle(t, client)
+
+			published := client.Published("server/nack")
+			if len(published) != 1 {
+				t.Fatalf("published %d nacks, want 1", len(published))
+			}
+
+			var nack struct {
+				RFID   string `json:"RFID"`
+				Field  string `json:"field"`
+				Reason string `json:"reason"`
+			}
+			if err := json.Unmarshal(published[0].Payload(), &nack); err != nil {
+				t.Fatal(err)
+			}
+
+			if nack.RFID != "00-01" || nack.Field != "RFID" || nack.Reason != tt.reason {
+				t.Errorf("nack = %+v, want the RFID field rejected with %q", nack, tt.reason)
+			}
+		})
+	}
+}
+
+func TestHello(t *testing.T) {
+	t.Setenv("MQTT_CLIENT_ID", "server-1")
+	t.Setenv("SERVER_HELLO_TOPIC", "server/hello")
+	client, _ := runServer(t)
+
+	published := client.Published("server/hello")
+	if len(published) != 1 {
+		t.Fatalf("published %d hellos, want 1", len(published))
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(published[0].Payload(), &fields); err != nil {
+		t.Fatalf("hello %q is not JSON: %v", published[0].Payload(), err)
+	}
+	for _, field := range []string{"server_id", "version", "started_at"} {
+		if _, ok := fields[field]; !ok {
+			t.Errorf("hello %s lacks %s", published[0].Payload(), field)
+		}
+	}
+
+	var hello types.Hello
+	if err := json.Unmarshal(published[0].Payload(), &hello); err != nil {
+		t.Fatal(err)
+	}
+	if hello.ServerID != "server-1" || hello.Version != version || !hello.StartedAt.Equal(startedAt) {
+		t.Errorf("hello = %+v, want server-1 at %s started at %s", hello, version, startedAt)
+	}
+
+	if stream := client.Published("server/stream"); len(stream) != 0 {
+		t.Errorf("published %d messages to the stream, want the hello kept off it", len(stream))
+	}
+}
+
+func TestReloadOnSIGHUP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	t.Setenv("HTTP_ADDR", addr)
+	t.Setenv("HEALTH_ERROR_WINDOW", "1h")
+	t.Setenv("HEALTH_ERROR_RATIO_THRESHOLD", "0.5")
+	t.Setenv("HEALTH_ERROR_MIN_SAMPLES", "3")
+	client, db := runServer(t)
+
+	healthz := func() string {
+		t.Helper()
+
+		var report struct {
+			Status string `json:"status"`
+		}
+		for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+			resp, err := http.Get("http://" + addr + "/healthz")
+			if err != nil {
+				if time.Now().After(deadline) {
+					t.Fatal(err)
+				}
+				continue
+			}
+			defer resp.Body.Close()
+
+			if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+				t.Fatal(err)
+			}
+
+			return report.Status
+		}
+	}
+
+	// one message stored and two failing to be stored put the error ratio between the thresholds
+	ctx := context.Background()
+	if err := (&models.User{ID: "0001"}).InsertG(ctx, boil.Infer()); err != nil {
+		t.Fatal(err)
+	}
+	client.Deliver(brokertest.NewMessage("arduino/stream", []byte(`{"RFID":"0001","slots":"A1","status":1}`), 1, false))
+	settle(t, client)
+	if _, err := db.Exec("DROP TABLE events"); err != nil {
+		t.Fatal(err)
+	}
+	for _, slot := range []string{"A2", "A3"} {
+		client.Deliver(brokertest.NewMessage("arduino/stream", []byte(`{"RFID":"0001","slots":"`+slot+`","status":1}`), 1, false))
+	}
+	settle(t, client)
+
+	if status := healthz(); status != health.StatusDegraded {
+		t.Fatalf("/healthz = %s at 2 of 3 failed under the 0.5 threshold, want degraded", status)
+	}
+
+	subscriptions := client.Subscriptions()
+	sort.Strings(subscriptions)
+
+	// the env file is reloaded from the working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("HEALTH_ERROR_RATIO_THRESHOLD=0.9\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	// the reloaded settings outlive the environment of the test, so the threshold is reloaded back
+	t.Cleanup(func() {
+		if err := os.WriteFile(".env", []byte("HEALTH_ERROR_RATIO_THRESHOLD=0.5\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := config.Reload(".env"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	// the running monitor takes the new threshold rather than only the config
+	eventually(t, "the monitor to take the reloaded threshold", func() bool {
+		return healthz() == health.StatusOK
+	})
+	if got := config.Float("HEALTH_ERROR_RATIO_THRESHOLD", 0.5); got != 0.9 {
+		t.Errorf("HEALTH_ERROR_RATIO_THRESHOLD = %v after reloading, want 0.9", got)
+	}
+
+	// reloading a threshold neither reconnects nor resubscribes
+	if !client.IsConnectionOpen() {
+		t.Error("the connection to the broker was closed by reloading")
+	}
+	reloaded := client.Subscriptions()
+	sort.Strings(reloaded)
+	if strings.Join(reloaded, ",") != strings.Join(subscriptions, ",") {
+		t.Errorf("subscriptions = %v after reloading, want %v", reloaded, subscriptions)
+	}
+}
@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"database/sql"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "letovo"
+
+// MessagesReceived counts incoming MQTT messages per route, split by whether they are retained replays or live traffic
+var MessagesReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "mqtt_messages_received_total",
+	Help:      "Number of MQTT messages received, partitioned by route and retained flag.",
+}, []string{"route", "retained"})
+
+// ObserveMessage increments the received messages counter matching the route and the retained flag
+func ObserveMessage(route string, retained bool) {
+	MessagesReceived.WithLabelValues(route, strconv.FormatBool(retained)).Inc()
+}
+
+// ParseFailures counts incoming MQTT messages per route whose payload could not be decoded
+var ParseFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "mqtt_parse_failures_total",
+	Help:      "Number of MQTT messages received whose payload could not be decoded, partitioned by route.",
+}, []string{"route"})
+
+// ObserveParseFailure increments the parse failures counter matching the route
+func ObserveParseFailure(route string) {
+	ParseFailures.WithLabelValues(route).Inc()
+}
+
+// MessagesPublished counts outgoing MQTT messages per route, split by whether the broker accepted them
+var MessagesPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "mqtt_messages_published_total",
+	Help:      "Number of MQTT messages published, partitioned by route and result.",
+}, []string{"route", "result"})
+
+// PublishDuration observes how long the broker takes to accept the messages published to each route
+var PublishDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "mqtt_publish_duration_seconds",
+	Help:      "Time taken for an MQTT publish to complete, partitioned by route.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"route"})
+
+// ObservePublish records the outcome of a publish to the route and how long it took to complete
+func ObservePublish(route string, took time.Duration, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+
+	MessagesPublished.WithLabelValues(route, result).Inc()
+	PublishDuration.WithLabelValues(route).Observe(took.Seconds())
+}
+
+// SubscribeFailures counts the subscriptions the broker did not grant in time, per topic
+var SubscribeFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "mqtt_subscribe_failures_total",
+	Help:      "Number of failed MQTT subscriptions, partitioned by topic.",
+}, []string{"topic"})
+
+// MessageDuration observes how long the handlers of each route take to process a message
+var MessageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "mqtt_message_duration_seconds",
+	Help:      "Time taken to handle an MQTT message, partitioned by the route handling it.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"route"})
+
+// MessagePanics counts the messages whose handler panicked
+var MessagePanics = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "mqtt_message_panics_total",
+	Help:      "Number of MQTT messages whose handler panicked, partitioned by the route handling them.",
+}, []string{"route"})
+
+// WorkerQueued tracks the messages waiting in the queues of the worker pool
+var WorkerQueued = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "mqtt_worker_queued_messages",
+	Help:      "Number of MQTT messages waiting for a worker, partitioned by route.",
+}, []string{"route"})
+
+// WorkerOverflows counts the messages finding the queue of their worker full, split by whether they were
+// dropped or waited for room
+var WorkerOverflows = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "mqtt_worker_overflows_total",
+	Help:      "Number of MQTT messages finding their worker queue full, partitioned by route and overflow mode.",
+}, []string{"route", "mode"})
+
+// APIRateLimited counts HTTP requests rejected for exceeding the rate limit, split by whether the client was
+// identified by its token or its address
+var APIRateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "api_rate_limited_total",
+	Help:      "Number of HTTP requests rejected by the rate limiter, partitioned by the kind of client key.",
+}, []string{"key"})
+
+// SlotChanges counts the changes of the slots the db notified of, by whether this instance, another one or
+// something else made them
+var SlotChanges = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "slot_changes_total",
+	Help:      "Number of slot changes notified by the db, by their source.",
+}, []string{"source"})
+
+// LoanLimitRejections counts the takes rejected for their user holding too many laptops already
+var LoanLimitRejections = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "loan_limit_rejections_total",
+	Help:      "Number of takes rejected for their user holding the maximum number of laptops.",
+})
+
+// OutboxMessages counts the queued messages the outbox published or failed to, by the result
+var OutboxMessages = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "outbox_messages_total",
+	Help:      "Number of outbox messages published or failed to be, by the result.",
+}, []string{"result"})
+
+// RawMessagesDropped counts the received messages left out of the raw message log while its buffer was full
+var RawMessagesDropped = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "raw_messages_dropped_total",
+	Help:      "Number of received messages not logged for the raw message log being full.",
+})
+
+// StaleSlots counts the slot updates lost to a concurrent update of the same slot
+var StaleSlots = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "slot_version_conflicts_total",
+	Help:      "Number of slot updates rejected for a concurrent update of the slot.",
+})
+
+// TxRetries counts the db transactions retried after failing with a transient error
+var TxRetries = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "db_transaction_retries_total",
+	Help:      "Number of db transactions retried after a transient failure.",
+})
+
+// DBUp reports whether the db is up, the message handlers being paused while it is down
+var DBUp = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "db_up",
+	Help:      "Whether the db is up (1) or down (0), as seen by the circuit breaker.",
+})
+
+// RowsPruned counts the rows deleted per table once older than their retention period
+var RowsPruned = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "db_rows_pruned_total",
+	Help:      "Number of db rows pruned past their retention period, partitioned by table.",
+}, []string{"table"})
+
+// RegisterDBStats exposes the stats of the db connection pool, like the connections in use and the time spent
+// waiting for one, labeled by the name of the driver serving the db
+func RegisterDBStats(db *sql.DB, driver string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, driver))
+}
+
+// RegisterBuildInfo exposes the build provenance as an info metric, along with the time the server started at
+func RegisterBuildInfo(version, commit string, startedAt time.Time) {
+	promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "build_info",
+		Help:      "A metric with a constant '1' value labeled by the version, commit and go version the server was built with.",
+		ConstLabels: prometheus.Labels{
+			"version":   version,
+			"commit":    commit,
+			"goversion": runtime.Version(),
+		},
+	}).Set(1)
+
+	promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "start_time_seconds",
+		Help:      "Start time of the server since unix epoch in seconds.",
+	}).Set(float64(startedAt.Unix()))
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRegisterBuildInfo(t *testing.T) {
+	startedAt := time.Unix(1700000000, 0)
+	RegisterBuildInfo("1.2.3", "abc123", startedAt)
+
+	expected := fmt.Sprintf(`
+# HELP letovo_build_info A metric with a constant '1' value labeled by the version, commit and go version the server was built with.
+# TYPE letovo_build_info gauge
+letovo_build_info{commit="abc123",goversion=%q,version="1.2.3"} 1
+# HELP letovo_start_time_seconds Start time of the server since unix epoch in seconds.
+# TYPE letovo_start_time_seconds gauge
+letovo_start_time_seconds 1.7e+09
+`, runtime.Version())
+
+	err := testutil.GatherAndCompare(prometheus.DefaultGatherer, strings.NewReader(expected),
+		"letovo_build_info", "letovo_start_time_seconds",
+	)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestObserveMessageSplitsRetainedAndLive(t *testing.T) {
+	const route = "test"
+
+	ObserveMessage(route, true)
+	ObserveMessage(route, false)
+	ObserveMessage(route, false)
+
+	if got := testutil.ToFloat64(MessagesReceived.WithLabelValues(route, "true")); got != 1 {
+		t.Errorf("retained messages = %g, want 1", got)
+	}
+	if got := testutil.ToFloat64(MessagesReceived.WithLabelValues(route, "false")); got != 2 {
+		t.Errorf("live messages = %g, want 2", got)
+	}
+}
@@ -1,6 +1,7 @@
-DROP TABLE IF EXISTS users CASCADE;
-DROP TABLE IF EXISTS slots CASCADE;
+-- the schema the server started with, as docker-entrypoint-initdb.d created it. The tables are created only where
+-- missing, so that the dbs set up by it adopt the migrations, every later change added by the migration making it
 
+-- +goose Up
 CREATE TABLE IF NOT EXISTS users
 (
     id    VARCHAR(20) UNIQUE NOT NULL,
@@ -18,4 +19,9 @@ CREATE TABLE IF NOT EXISTS slots
 );
 
 INSERT INTO users (id, login)
-VALUES ('null', '');
+VALUES ('null', '')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS slots;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,12 @@
+-- the users are bound to the names and emails of the students their RFID tags were issued to. The dbs set up by
+-- docker-entrypoint-initdb.d may have the columns already, so they are added only where missing
+
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS name  TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS email TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE users
+    DROP COLUMN IF EXISTS email,
+    DROP COLUMN IF EXISTS name;
@@ -0,0 +1,12 @@
+-- the roles assigned to the subjects of the tokens, overriding the role their tokens claim
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS roles
+(
+    subject TEXT UNIQUE NOT NULL,
+    role    TEXT        NOT NULL CHECK (role IN ('viewer', 'operator', 'admin')),
+    PRIMARY KEY (subject)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,16 @@
+-- the urls registered to be delivered the events of the cabinets
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS webhooks
+(
+    id         SERIAL      NOT NULL,
+    url        TEXT        NOT NULL,
+    -- the statuses of the events delivered to the url, all of them when empty
+    events     TEXT[]      NOT NULL DEFAULT '{}',
+    secret     TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,21 @@
+-- the history of the takes and returns, kept for the event queries
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS events
+(
+    id          BIGSERIAL   NOT NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    device      TEXT        NOT NULL DEFAULT '',
+    rfid        VARCHAR(20) NOT NULL DEFAULT '',
+    slot        VARCHAR(5)  NOT NULL DEFAULT '',
+    status      SMALLINT    NOT NULL,
+    result      TEXT        NOT NULL DEFAULT '',
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS events_received_at_idx ON events (received_at);
+CREATE INDEX IF NOT EXISTS events_rfid_received_at_idx ON events (rfid, received_at);
+CREATE INDEX IF NOT EXISTS events_slot_received_at_idx ON events (slot, received_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS events;
@@ -0,0 +1,14 @@
+-- the slots are placed in their cabinets by row and column, as provisioned in bulk. The dbs set up by
+-- docker-entrypoint-initdb.d may have the columns already, so they are added only where missing
+
+-- +goose Up
+ALTER TABLE slots
+    ADD COLUMN IF NOT EXISTS cabinet_id TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS "row"      INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS "column"   INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE slots
+    DROP COLUMN IF EXISTS "column",
+    DROP COLUMN IF EXISTS "row",
+    DROP COLUMN IF EXISTS cabinet_id;
@@ -0,0 +1,18 @@
+-- messages which could not be processed, kept along with the stage they failed at for inspection and replay
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS failed_messages
+(
+    id          BIGSERIAL   NOT NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    topic       TEXT        NOT NULL,
+    payload     BYTEA       NOT NULL,
+    stage       TEXT        NOT NULL DEFAULT '',
+    error       TEXT        NOT NULL DEFAULT '',
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS failed_messages_received_at_idx ON failed_messages (received_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS failed_messages;
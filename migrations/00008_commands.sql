@@ -0,0 +1,23 @@
+-- commands sent to the devices, tracked from pending through delivered to acknowledged, failed or expired
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS commands
+(
+    id           TEXT        NOT NULL,
+    cmd          TEXT        NOT NULL,
+    device       TEXT        NOT NULL DEFAULT '',
+    slot         VARCHAR(5)  NOT NULL DEFAULT '',
+    status       TEXT        NOT NULL DEFAULT 'pending',
+    error        TEXT        NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at   TIMESTAMPTZ NOT NULL,
+    delivered_at TIMESTAMPTZ,
+    replied_at   TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS commands_created_at_idx ON commands (created_at);
+CREATE INDEX IF NOT EXISTS commands_status_expires_at_idx ON commands (status, expires_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS commands;
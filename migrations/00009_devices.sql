@@ -0,0 +1,15 @@
+-- presence of the devices, kept from their birth and will messages and their heartbeats
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS devices
+(
+    id              TEXT        NOT NULL,
+    online          BOOLEAN     NOT NULL DEFAULT false,
+    last_seen       TIMESTAMPTZ,
+    connected_at    TIMESTAMPTZ,
+    disconnected_at TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS devices;
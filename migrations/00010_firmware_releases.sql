@@ -0,0 +1,22 @@
+-- firmware binaries uploaded by the admins and streamed to the devices in chunks, along with the version each
+-- device reports running. The dbs set up by docker-entrypoint-initdb.d may have the column already
+
+-- +goose Up
+ALTER TABLE devices
+    ADD COLUMN IF NOT EXISTS firmware_version TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS firmware_releases
+(
+    version    TEXT        NOT NULL,
+    size       INTEGER     NOT NULL,
+    sha256     TEXT        NOT NULL,
+    data       BYTEA       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (version)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS firmware_releases;
+
+ALTER TABLE devices
+    DROP COLUMN IF EXISTS firmware_version;
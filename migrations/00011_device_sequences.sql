@@ -0,0 +1,10 @@
+-- the devices number their messages, the last number stored being kept to reject the duplicates. The dbs set up by
+-- docker-entrypoint-initdb.d may have the column already
+
+-- +goose Up
+ALTER TABLE devices
+    ADD COLUMN IF NOT EXISTS last_seq BIGINT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE devices
+    DROP COLUMN IF EXISTS last_seq;
@@ -0,0 +1,30 @@
+-- one-time tokens a new device enrolls with, bound to the device it is to be issued credentials for, and the MQTT
+-- credentials and HMAC secrets issued to the devices, the ones replaced by a rotation expiring after a grace period
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS enrollment_tokens
+(
+    token_hash TEXT        NOT NULL,
+    device     TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at    TIMESTAMPTZ,
+    PRIMARY KEY (token_hash)
+);
+
+CREATE TABLE IF NOT EXISTS device_credentials
+(
+    id            BIGSERIAL   NOT NULL,
+    device        TEXT        NOT NULL,
+    password_hash TEXT        NOT NULL,
+    secret        TEXT        NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at    TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS device_credentials_device_idx ON device_credentials (device);
+
+-- +goose Down
+DROP TABLE IF EXISTS device_credentials;
+DROP TABLE IF EXISTS enrollment_tokens;
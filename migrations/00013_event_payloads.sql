@@ -0,0 +1,9 @@
+-- the events keep the message they were logged for as it was received
+
+-- +goose Up
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS payload BYTEA NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE events
+    DROP COLUMN IF EXISTS payload;
@@ -0,0 +1,16 @@
+-- the slots are labeled as they are marked on the cabinets, like B-3, and may carry notes on their condition.
+-- The cabinets are filtered by cabinet_id rather than by the prefix of the slot IDs
+
+-- +goose Up
+ALTER TABLE slots
+    ADD COLUMN IF NOT EXISTS label TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS notes TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS slots_cabinet_id_idx ON slots (cabinet_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS slots_cabinet_id_idx;
+
+ALTER TABLE slots
+    DROP COLUMN IF EXISTS notes,
+    DROP COLUMN IF EXISTS label;
@@ -0,0 +1,18 @@
+-- the users carry the profile the student directory has for them, so that the notifications and reports can
+-- name people rather than their RFIDs. The role is their place at the school, apart from the roles of the api
+
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS class      TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS role       TEXT        NOT NULL DEFAULT 'student' CHECK (role IN ('student', 'teacher', 'staff')),
+    ADD COLUMN IF NOT EXISTS active     BOOLEAN     NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- +goose Down
+ALTER TABLE users
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS active,
+    DROP COLUMN IF EXISTS role,
+    DROP COLUMN IF EXISTS class;
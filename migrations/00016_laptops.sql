@@ -0,0 +1,32 @@
+-- the laptops kept in the cabinets, each with the slot it is stored in. The events of taking and returning them
+-- record the laptop involved, tracking the assets rather than only the occupancy of the slots
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS laptops
+(
+    asset_tag  TEXT        NOT NULL,
+    serial     TEXT        NOT NULL DEFAULT '',
+    model      TEXT        NOT NULL DEFAULT '',
+    home_slot  CHAR(5),
+    status     TEXT        NOT NULL DEFAULT 'available' CHECK (status IN ('available', 'in_use', 'repair', 'retired')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (asset_tag),
+    FOREIGN KEY (home_slot) REFERENCES slots (id) ON DELETE SET NULL
+);
+
+-- a slot is the home of at most one laptop
+CREATE UNIQUE INDEX IF NOT EXISTS laptops_home_slot_idx ON laptops (home_slot);
+
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS laptop TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS events_laptop_received_at_idx ON events (laptop, received_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS events_laptop_received_at_idx;
+
+ALTER TABLE events
+    DROP COLUMN IF EXISTS laptop;
+
+DROP TABLE IF EXISTS laptops;
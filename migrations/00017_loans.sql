@@ -0,0 +1,26 @@
+-- a loan is a laptop checked out of its slot, opened when the slot is taken and closed when it is placed back,
+-- so that the overdue laptops and the history of the users are plain queries
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS loans
+(
+    id          BIGSERIAL   NOT NULL,
+    rfid        VARCHAR(20) NOT NULL,
+    slot        CHAR(5)     NOT NULL,
+    laptop      TEXT        NOT NULL DEFAULT '',
+    taken_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    due_at      TIMESTAMPTZ NOT NULL,
+    returned_at TIMESTAMPTZ,
+    PRIMARY KEY (id),
+    FOREIGN KEY (rfid) REFERENCES users (id),
+    FOREIGN KEY (slot) REFERENCES slots (id)
+);
+
+-- a slot is checked out by at most one loan at a time
+CREATE UNIQUE INDEX IF NOT EXISTS loans_open_slot_idx ON loans (slot) WHERE returned_at IS NULL;
+CREATE INDEX IF NOT EXISTS loans_open_due_at_idx ON loans (due_at) WHERE returned_at IS NULL;
+CREATE INDEX IF NOT EXISTS loans_rfid_taken_at_idx ON loans (rfid, taken_at);
+CREATE INDEX IF NOT EXISTS loans_laptop_taken_at_idx ON loans (laptop, taken_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS loans;
@@ -0,0 +1,13 @@
+-- the slots carry the version of their state, bumped by every update of them, so that two messages about the same
+-- slot stored at the same time, like a duplicate delivered to two instances, cannot interleave. The update read
+-- against an older version loses, and is run again from the state the winner left
+
+-- +goose Up
+ALTER TABLE slots
+    ADD COLUMN IF NOT EXISTS version    BIGINT      NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- +goose Down
+ALTER TABLE slots
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS version;
@@ -0,0 +1,48 @@
+-- every change of a slot is notified on the slot_changes channel, along with the application name of the
+-- connection making it, so that the edits made by other tools reach the streams and the devices as well
+
+-- +goose Up
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_slot_change() RETURNS TRIGGER AS
+$$
+DECLARE
+    slot     slots;
+    previous TEXT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        slot := OLD;
+    ELSE
+        slot := NEW;
+    END IF;
+
+    -- the cabinet the slot is moved out of is refreshed as well
+    IF TG_OP = 'UPDATE' THEN
+        previous := OLD.cabinet_id;
+    END IF;
+
+    PERFORM pg_notify('slot_changes', json_build_object(
+            'op', TG_OP,
+            'id', slot.id,
+            'cabinet_id', slot.cabinet_id,
+            'previous_cabinet_id', previous,
+            'is_taken', slot.is_taken,
+            'taken_by', slot.taken_by,
+            'origin', current_setting('application_name')
+        )::TEXT);
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+DROP TRIGGER IF EXISTS slots_notify_change ON slots;
+
+CREATE TRIGGER slots_notify_change
+    AFTER INSERT OR UPDATE OR DELETE
+    ON slots
+    FOR EACH ROW
+EXECUTE FUNCTION notify_slot_change();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS slots_notify_change ON slots;
+DROP FUNCTION IF EXISTS notify_slot_change();
@@ -0,0 +1,22 @@
+-- every message received on the stream is kept as it was received, along with the outcome of processing it, so
+-- that the bugs of the firmware can be diagnosed after the fact. Only the latest messages of every device are
+-- kept, the older ones pruned as the newer ones come in
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS raw_messages
+(
+    id          BIGSERIAL   NOT NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    device      TEXT        NOT NULL,
+    topic       TEXT        NOT NULL,
+    qos         SMALLINT    NOT NULL DEFAULT 0,
+    payload     BYTEA       NOT NULL,
+    outcome     TEXT        NOT NULL DEFAULT '',
+    error       TEXT        NOT NULL DEFAULT '',
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS raw_messages_device_id_idx ON raw_messages (device, id);
+
+-- +goose Down
+DROP TABLE IF EXISTS raw_messages;
@@ -0,0 +1,24 @@
+-- the mutating api calls are logged along with who made them and the values they changed, as required of the
+-- systems touching the data of the students
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS audit_log
+(
+    id         BIGSERIAL   NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    subject    TEXT        NOT NULL DEFAULT '',
+    role       TEXT        NOT NULL DEFAULT '',
+    method     TEXT        NOT NULL,
+    path       TEXT        NOT NULL,
+    status     INTEGER     NOT NULL,
+    before     JSONB,
+    after      JSONB,
+    source_ip  TEXT        NOT NULL DEFAULT '',
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at);
+CREATE INDEX IF NOT EXISTS audit_log_subject_idx ON audit_log (subject, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,26 @@
+-- the messages the server publishes for the changes it stores, like the acks and the state corrections, are queued
+-- in the transaction storing the change, and published once it commits, so that a crash in between can neither
+-- lose them nor publish them for a change rolled back
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS outbox_messages
+(
+    id               BIGSERIAL   NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    topic            TEXT        NOT NULL,
+    qos              SMALLINT    NOT NULL DEFAULT 0,
+    retained         BOOLEAN     NOT NULL DEFAULT FALSE,
+    payload          BYTEA       NOT NULL,
+    content_type     TEXT        NOT NULL DEFAULT '',
+    correlation_data BYTEA       NOT NULL DEFAULT '',
+    attempts         INTEGER     NOT NULL DEFAULT 0,
+    last_error       TEXT        NOT NULL DEFAULT '',
+    sent_at          TIMESTAMPTZ,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS outbox_messages_pending_idx ON outbox_messages (id) WHERE sent_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_messages_sent_at_idx ON outbox_messages (sent_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox_messages;
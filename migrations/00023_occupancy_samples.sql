@@ -0,0 +1,19 @@
+-- the occupancy of every cabinet is sampled periodically, so that its utilization over time is graphed without
+-- scanning the events
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS occupancy_samples
+(
+    id         BIGSERIAL   NOT NULL,
+    sampled_at TIMESTAMPTZ NOT NULL,
+    cabinet_id TEXT        NOT NULL,
+    slots      INTEGER     NOT NULL,
+    taken      INTEGER     NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (cabinet_id, sampled_at)
+);
+
+CREATE INDEX IF NOT EXISTS occupancy_samples_sampled_at_idx ON occupancy_samples (sampled_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS occupancy_samples;
@@ -0,0 +1,16 @@
+-- the decommissioned slots and the departed users are marked deleted rather than deleted, so that they are left out
+-- of the queries and the snapshots while their events and loans still name them for the reports
+
+-- +goose Up
+ALTER TABLE slots
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE slots
+    DROP COLUMN IF EXISTS deleted_at;
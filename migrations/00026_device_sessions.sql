@@ -0,0 +1,22 @@
+-- every connection of a device is kept as a session, from the birth message or the heartbeat bringing it online to
+-- the will, the missed heartbeats or the next birth ending it, so that its uptime and flapping are told from them.
+-- A device has a single session open at a time
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS device_sessions
+(
+    id              BIGSERIAL   NOT NULL,
+    device          TEXT        NOT NULL,
+    connected_at    TIMESTAMPTZ NOT NULL,
+    connected_by    TEXT        NOT NULL,
+    disconnected_at TIMESTAMPTZ,
+    disconnected_by TEXT        NOT NULL DEFAULT '',
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS device_sessions_device_idx ON device_sessions (device, connected_at);
+
+CREATE UNIQUE INDEX IF NOT EXISTS device_sessions_open_idx ON device_sessions (device) WHERE disconnected_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS device_sessions;
@@ -0,0 +1,10 @@
+-- the dispatcher claims the messages it is about to publish until claimed_until, rather than holding their row locks
+-- while it waits for the broker, and another instance takes over the ones whose claim has expired
+
+-- +goose Up
+ALTER TABLE outbox_messages
+    ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE outbox_messages
+    DROP COLUMN IF EXISTS claimed_until;
@@ -0,0 +1,46 @@
+-- the slot IDs are stored unpadded, as CHAR(5) padded them to the width of the column, which every reader had to
+-- trim before matching them. Casting to VARCHAR drops the padding of the stored IDs
+
+-- +goose Up
+ALTER TABLE laptops
+    DROP CONSTRAINT IF EXISTS laptops_home_slot_fkey;
+
+ALTER TABLE loans
+    DROP CONSTRAINT IF EXISTS loans_slot_fkey;
+
+ALTER TABLE slots
+    ALTER COLUMN id TYPE VARCHAR(5);
+
+ALTER TABLE laptops
+    ALTER COLUMN home_slot TYPE VARCHAR(5);
+
+ALTER TABLE loans
+    ALTER COLUMN slot TYPE VARCHAR(5);
+
+ALTER TABLE laptops
+    ADD CONSTRAINT laptops_home_slot_fkey FOREIGN KEY (home_slot) REFERENCES slots (id) ON DELETE SET NULL;
+
+ALTER TABLE loans
+    ADD CONSTRAINT loans_slot_fkey FOREIGN KEY (slot) REFERENCES slots (id);
+
+-- +goose Down
+ALTER TABLE laptops
+    DROP CONSTRAINT IF EXISTS laptops_home_slot_fkey;
+
+ALTER TABLE loans
+    DROP CONSTRAINT IF EXISTS loans_slot_fkey;
+
+ALTER TABLE slots
+    ALTER COLUMN id TYPE CHAR(5);
+
+ALTER TABLE laptops
+    ALTER COLUMN home_slot TYPE CHAR(5);
+
+ALTER TABLE loans
+    ALTER COLUMN slot TYPE CHAR(5);
+
+ALTER TABLE laptops
+    ADD CONSTRAINT laptops_home_slot_fkey FOREIGN KEY (home_slot) REFERENCES slots (id) ON DELETE SET NULL;
+
+ALTER TABLE loans
+    ADD CONSTRAINT loans_slot_fkey FOREIGN KEY (slot) REFERENCES slots (id);
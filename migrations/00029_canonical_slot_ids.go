@@ -0,0 +1,144 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/types"
+)
+
+func init() {
+	goose.AddMigration(upCanonicalSlotIDs, nil)
+}
+
+// upCanonicalSlotIDs rewrites the slot IDs stored before they were canonicalized, like "a-1" and "A_1", to the ID
+// types.CanonicalizeSlotID looks them up by, under the SLOT_ID_* settings the server migrates with. A slot whose
+// canonical ID is taken already by another one is left alone and logged, to be merged by hand. The events and the
+// commands of every slot are rewritten, the ones of the slots long gone included. The original spelling is lost,
+// so there is no going back
+func upCanonicalSlotIDs(tx *sql.Tx) error {
+	ids, err := column(tx, `SELECT id FROM slots`)
+	if err != nil {
+		return err
+	}
+
+	taken := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		taken[id] = true
+	}
+
+	renamed := make(map[string]string)
+	for _, id := range ids {
+		canonical := types.CanonicalizeSlotID(id)
+		if canonical == id || canonical == "" {
+			continue
+		}
+
+		if taken[canonical] {
+			log.Warn().Str("slot", id).
+				Msgf("slot %s is %s once canonicalized, which is taken already, merge them by hand", id, canonical)
+			continue
+		}
+
+		taken[canonical] = true
+		renamed[id] = canonical
+	}
+
+	if len(renamed) > 0 {
+		if err := renameSlots(tx, renamed); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range []string{"events", "commands"} {
+		slots, err := column(tx, `SELECT DISTINCT slot FROM `+table)
+		if err != nil {
+			return err
+		}
+
+		for _, slot := range slots {
+			canonical := types.CanonicalizeSlotID(slot)
+			if canonical == slot {
+				continue
+			}
+
+			if _, err := tx.Exec(`UPDATE `+table+` SET slot = $1 WHERE slot = $2`, canonical, slot); err != nil {
+				return fmt.Errorf("failed to canonicalize slot %s of %s: %w", slot, table, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// renameSlots renames the slots along with the loans and the laptops referencing them. The foreign keys would
+// reject whichever of a slot and its references is renamed first, so they are checked once all of them are, on
+// commit in sqlite and by adding them back in postgres
+func renameSlots(tx *sql.Tx, renamed map[string]string) error {
+	constraints := []string{
+		`ALTER TABLE laptops DROP CONSTRAINT IF EXISTS laptops_home_slot_fkey`,
+		`ALTER TABLE loans DROP CONSTRAINT IF EXISTS loans_slot_fkey`,
+	}
+	if dir == "sqlite" {
+		constraints = []string{`PRAGMA defer_foreign_keys = ON`}
+	}
+
+	for _, query := range constraints {
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("failed to defer foreign keys of slots: %w", err)
+		}
+	}
+
+	for id, canonical := range renamed {
+		for _, query := range []string{
+			`UPDATE slots SET id = $1 WHERE id = $2`,
+			`UPDATE loans SET slot = $1 WHERE slot = $2`,
+			`UPDATE laptops SET home_slot = $1 WHERE home_slot = $2`,
+		} {
+			if _, err := tx.Exec(query, canonical, id); err != nil {
+				return fmt.Errorf("failed to rename slot %s to %s: %w", id, canonical, err)
+			}
+		}
+
+		log.Info().Str("slot", canonical).Msgf("renamed slot %s to %s", id, canonical)
+	}
+
+	if dir == "sqlite" {
+		return nil
+	}
+
+	for _, query := range []string{
+		`ALTER TABLE laptops ADD CONSTRAINT laptops_home_slot_fkey
+			FOREIGN KEY (home_slot) REFERENCES slots (id) ON DELETE SET NULL`,
+		`ALTER TABLE loans ADD CONSTRAINT loans_slot_fkey FOREIGN KEY (slot) REFERENCES slots (id)`,
+	} {
+		if _, err := tx.Exec(query); err != nil {
+			return fmt.Errorf("failed to restore foreign keys of slots: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// column returns the values of the single column the query selects
+func column(tx *sql.Tx, query string) ([]string, error) {
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %q: %w", query, err)
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to scan %q: %w", query, err)
+		}
+		values = append(values, value)
+	}
+
+	return values, rows.Err()
+}
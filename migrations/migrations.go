@@ -0,0 +1,110 @@
+// Package migrations keeps the db schema in step with the models, applying the versioned SQL migrations
+// embedded in the server. A migration is a NNNNN_name.sql file holding its -- +goose Up and -- +goose Down sections,
+// with its counterpart of the same version under sqlite/ for the sqlite dbs. The data migrations depending on the
+// settings of the server are NNNNN_name.go files instead, run on either db
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pressly/goose/v3"
+	"github.com/rs/zerolog/log"
+)
+
+//go:embed *.sql sqlite/*.sql
+var files embed.FS
+
+// dir holds the migrations of the selected dialect
+var dir = "."
+
+// ErrUnknownCommand is returned by Run for commands other than the ones in Commands
+var ErrUnknownCommand = errors.New("unknown migrate command")
+
+// Commands are the ones Run understands, some followed by the target version
+var Commands = []string{"up", "up-by-one", "up-to", "down", "down-to", "redo", "status", "version"}
+
+func init() {
+	goose.SetBaseFS(files)
+	goose.SetLogger(logger{})
+	if err := goose.SetDialect("postgres"); err != nil {
+		panic(err)
+	}
+}
+
+// Select picks the migrations of the driver, postgres unless sqlite
+func Select(driver string) error {
+	dialect := "postgres"
+	dir = "."
+	if driver == "sqlite" {
+		dialect, dir = "sqlite3", "sqlite"
+	}
+
+	if err := goose.SetDialect(dialect); err != nil {
+		return fmt.Errorf("failed to select migrations of %s: %w", driver, err)
+	}
+
+	return nil
+}
+
+// Up applies the pending migrations, each in a transaction of its own
+func Up(db *sql.DB) error {
+	if err := goose.Up(db, dir); err != nil {
+		return fmt.Errorf("failed to migrate db: %w", err)
+	}
+
+	return nil
+}
+
+// Version returns the version of the latest migration applied to the db
+func Version(db *sql.DB) (int64, error) {
+	version, err := goose.GetDBVersion(db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get db schema version: %w", err)
+	}
+
+	return version, nil
+}
+
+// Run runs the migrate command, like up, down or status, reporting the outcome to the log
+func Run(db *sql.DB, command string, args ...string) error {
+	known := false
+	for _, c := range Commands {
+		known = known || c == command
+	}
+	if !known {
+		return fmt.Errorf("%w %q, expected one of %s", ErrUnknownCommand, command, strings.Join(Commands, ", "))
+	}
+
+	if err := goose.Run(command, db, dir, args...); err != nil {
+		return fmt.Errorf("failed to migrate db %s: %w", command, err)
+	}
+
+	return nil
+}
+
+// logger passes the reports of goose on to zerolog
+type logger struct{}
+
+func (logger) Fatal(v ...interface{}) {
+	log.Fatal().Msg(strings.TrimSpace(fmt.Sprint(v...)))
+}
+
+func (logger) Fatalf(format string, v ...interface{}) {
+	log.Fatal().Msg(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (logger) Print(v ...interface{}) {
+	log.Info().Msg(strings.TrimSpace(fmt.Sprint(v...)))
+}
+
+func (logger) Println(v ...interface{}) {
+	log.Info().Msg(strings.TrimSpace(fmt.Sprintln(v...)))
+}
+
+func (logger) Printf(format string, v ...interface{}) {
+	log.Info().Msg(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
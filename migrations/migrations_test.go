@@ -0,0 +1,175 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3"
+
+	"letovo-computers-server/storage"
+)
+
+// baseline is the schema docker-entrypoint-initdb.d created before the migrations, along with a slot stored in it
+const baseline = `
+CREATE TABLE users
+(
+    id    VARCHAR(20) UNIQUE NOT NULL,
+    login TEXT               NOT NULL DEFAULT '',
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE slots
+(
+    id       CHAR(5) UNIQUE NOT NULL,
+    is_taken BOOLEAN        NOT NULL DEFAULT FALSE,
+    taken_by VARCHAR(20)    NOT NULL,
+    PRIMARY KEY (id),
+    FOREIGN KEY (taken_by) REFERENCES users (id)
+);
+
+INSERT INTO users (id, login)
+VALUES ('null', '');
+
+INSERT INTO slots (id, is_taken, taken_by)
+VALUES ('A1', FALSE, 'null'),
+       ('b-2', FALSE, 'null'),
+       ('a_1', FALSE, 'null');
+`
+
+func TestUpFromBaseline(t *testing.T) {
+	t.Run(string(storage.SQLite), func(t *testing.T) {
+		t.Setenv("DB_DRIVER", string(storage.SQLite))
+		t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "test.db"))
+
+		testUpFromBaseline(t, open(t))
+	})
+
+	t.Run(string(storage.Postgres), func(t *testing.T) {
+		if os.Getenv("PGHOST") == "" {
+			t.Skip("PGHOST is not set")
+		}
+		t.Setenv("DB_DRIVER", string(storage.Postgres))
+
+		db := open(t)
+
+		// the db is shared, so the baseline is created in a schema of its own, on the single connection it is set for
+		schema := fmt.Sprintf("migrations_test_%d", time.Now().UnixNano())
+		db.SetMaxOpenConns(1)
+		if _, err := db.Exec("CREATE SCHEMA " + schema + "; SET search_path TO " + schema); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _, _ = db.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+		testUpFromBaseline(t, db)
+	})
+}
+
+func open(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, driver, err := storage.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	if err := Select(string(driver)); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func testUpFromBaseline(t *testing.T, db *sql.DB) {
+	if _, err := db.Exec(baseline); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Up(db); err != nil {
+		t.Fatal(err)
+	}
+
+	var cabinetID, label string
+	var row, column, version int
+	err := db.QueryRow(`SELECT cabinet_id, "row", "column", label, version FROM slots WHERE id = 'A1'`).
+		Scan(&cabinetID, &row, &column, &label, &version)
+	if err != nil {
+		t.Fatalf("failed to read the baseline slot: %v", err)
+	}
+	if cabinetID != "" || row != 0 || column != 0 || label != "" || version != 0 {
+		t.Fatalf("got slot %q %d %d %q %d, want the defaults", cabinetID, row, column, label, version)
+	}
+
+	var name, email, class string
+	if err := db.QueryRow(`SELECT name, email, class FROM users WHERE id = 'null'`).Scan(&name, &email, &class); err != nil {
+		t.Fatalf("failed to read the baseline user: %v", err)
+	}
+
+	var events int
+	if err := db.QueryRow(`SELECT count(*) FROM events`).Scan(&events); err != nil {
+		t.Fatalf("failed to read the events: %v", err)
+	}
+
+	// the slots stored before the IDs were canonicalized are renamed, unless their canonical ID is taken already
+	var ids []string
+	rows, err := db.Query(`SELECT id FROM slots ORDER BY id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if got := strings.Join(ids, " "); got != "A1 B2 a_1" {
+		t.Errorf("slots = %s after migrating, want A1 B2 a_1", got)
+	}
+}
+
+func TestCanonicalSlotIDs(t *testing.T) {
+	t.Setenv("DB_DRIVER", string(storage.SQLite))
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "test.db"))
+
+	db := open(t)
+	if err := goose.UpTo(db, dir, 28); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := db.Exec(`
+INSERT INTO slots (id, is_taken, taken_by) VALUES ('c-3', TRUE, 'null');
+INSERT INTO laptops (asset_tag, home_slot) VALUES ('LT-1', 'c-3');
+INSERT INTO loans (rfid, slot, laptop, due_at) VALUES ('null', 'c-3', 'LT-1', CURRENT_TIMESTAMP);
+INSERT INTO events (slot, status) VALUES ('c-3', 1), ('d 4', 1);
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Up(db); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, query := range []string{
+		`SELECT count(*) FROM slots WHERE id = 'C3'`,
+		`SELECT count(*) FROM laptops WHERE home_slot = 'C3'`,
+		`SELECT count(*) FROM loans WHERE slot = 'C3'`,
+		`SELECT count(*) FROM events WHERE slot = 'C3'`,
+		`SELECT count(*) FROM events WHERE slot = 'D4'`,
+	} {
+		var n int
+		if err := db.QueryRow(query).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 {
+			t.Errorf("%s = %d after migrating, want 1", query, n)
+		}
+	}
+}
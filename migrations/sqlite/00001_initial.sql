@@ -0,0 +1,26 @@
+-- the schema of the postgres migration of the same version, in the types sqlite has
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS users
+(
+    id    VARCHAR(20) NOT NULL,
+    login TEXT        NOT NULL DEFAULT '',
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS slots
+(
+    id       CHAR(5)     NOT NULL,
+    is_taken BOOLEAN     NOT NULL DEFAULT FALSE,
+    taken_by VARCHAR(20) NOT NULL,
+    PRIMARY KEY (id),
+    FOREIGN KEY (taken_by) REFERENCES users (id)
+);
+
+INSERT INTO users (id, login)
+VALUES ('null', '')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS slots;
+DROP TABLE IF EXISTS users;
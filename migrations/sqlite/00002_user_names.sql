@@ -0,0 +1,9 @@
+-- the columns of the postgres migration of the same version, in the types sqlite has
+
+-- +goose Up
+ALTER TABLE users ADD COLUMN name TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN email TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN email;
+ALTER TABLE users DROP COLUMN name;
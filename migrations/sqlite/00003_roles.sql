@@ -0,0 +1,12 @@
+-- the table of the postgres migration of the same version, in the types sqlite has
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS roles
+(
+    subject TEXT NOT NULL,
+    role    TEXT NOT NULL CHECK (role IN ('viewer', 'operator', 'admin')),
+    PRIMARY KEY (subject)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS roles;
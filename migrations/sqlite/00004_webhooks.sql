@@ -0,0 +1,16 @@
+-- the table of the postgres migration of the same version, in the types sqlite has. The times are stored as text
+-- the driver writes and parses back
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS webhooks
+(
+    id         INTEGER   NOT NULL PRIMARY KEY AUTOINCREMENT,
+    url        TEXT      NOT NULL,
+    -- the statuses of the events delivered to the url, all of them when empty, as their postgres array literal
+    events     TEXT      NOT NULL DEFAULT '{}',
+    secret     TEXT      NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS webhooks;
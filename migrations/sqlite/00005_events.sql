@@ -0,0 +1,21 @@
+-- the table of the postgres migration of the same version, in the types sqlite has. The times are stored as text
+-- the driver writes and parses back
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS events
+(
+    id          INTEGER     NOT NULL PRIMARY KEY AUTOINCREMENT,
+    received_at TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    device      TEXT        NOT NULL DEFAULT '',
+    rfid        VARCHAR(20) NOT NULL DEFAULT '',
+    slot        VARCHAR(5)  NOT NULL DEFAULT '',
+    status      SMALLINT    NOT NULL,
+    result      TEXT        NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS events_received_at_idx ON events (received_at);
+CREATE INDEX IF NOT EXISTS events_rfid_received_at_idx ON events (rfid, received_at);
+CREATE INDEX IF NOT EXISTS events_slot_received_at_idx ON events (slot, received_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS events;
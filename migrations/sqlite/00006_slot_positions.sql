@@ -0,0 +1,11 @@
+-- the columns of the postgres migration of the same version, in the types sqlite has
+
+-- +goose Up
+ALTER TABLE slots ADD COLUMN cabinet_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE slots ADD COLUMN "row" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE slots ADD COLUMN "column" INTEGER NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE slots DROP COLUMN "column";
+ALTER TABLE slots DROP COLUMN "row";
+ALTER TABLE slots DROP COLUMN cabinet_id;
@@ -0,0 +1,18 @@
+-- the table of the postgres migration of the same version, in the types sqlite has. The times are stored as text
+-- the driver writes and parses back
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS failed_messages
+(
+    id          INTEGER   NOT NULL PRIMARY KEY AUTOINCREMENT,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    topic       TEXT      NOT NULL,
+    payload     BLOB      NOT NULL,
+    stage       TEXT      NOT NULL DEFAULT '',
+    error       TEXT      NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS failed_messages_received_at_idx ON failed_messages (received_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS failed_messages;
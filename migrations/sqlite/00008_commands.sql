@@ -0,0 +1,24 @@
+-- the table of the postgres migration of the same version, in the types sqlite has. The times are stored as text
+-- the driver writes and parses back
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS commands
+(
+    id           TEXT       NOT NULL,
+    cmd          TEXT       NOT NULL,
+    device       TEXT       NOT NULL DEFAULT '',
+    slot         VARCHAR(5) NOT NULL DEFAULT '',
+    status       TEXT       NOT NULL DEFAULT 'pending',
+    error        TEXT       NOT NULL DEFAULT '',
+    created_at   TIMESTAMP  NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at   TIMESTAMP  NOT NULL,
+    delivered_at TIMESTAMP,
+    replied_at   TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS commands_created_at_idx ON commands (created_at);
+CREATE INDEX IF NOT EXISTS commands_status_expires_at_idx ON commands (status, expires_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS commands;
@@ -0,0 +1,16 @@
+-- the table of the postgres migration of the same version, in the types sqlite has. The times are stored as text
+-- the driver writes and parses back
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS devices
+(
+    id              TEXT    NOT NULL,
+    online          BOOLEAN NOT NULL DEFAULT FALSE,
+    last_seen       TIMESTAMP,
+    connected_at    TIMESTAMP,
+    disconnected_at TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS devices;
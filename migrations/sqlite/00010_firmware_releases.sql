@@ -0,0 +1,20 @@
+-- the table and the column of the postgres migration of the same version, in the types sqlite has. The times are
+-- stored as text the driver writes and parses back
+
+-- +goose Up
+ALTER TABLE devices ADD COLUMN firmware_version TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS firmware_releases
+(
+    version    TEXT      NOT NULL,
+    size       INTEGER   NOT NULL,
+    sha256     TEXT      NOT NULL,
+    data       BLOB      NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (version)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS firmware_releases;
+
+ALTER TABLE devices DROP COLUMN firmware_version;
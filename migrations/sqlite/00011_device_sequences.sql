@@ -0,0 +1,7 @@
+-- the columns of the postgres migration of the same version, in the types sqlite has
+
+-- +goose Up
+ALTER TABLE devices ADD COLUMN last_seq BIGINT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE devices DROP COLUMN last_seq;
@@ -0,0 +1,29 @@
+-- the tables of the postgres migration of the same version, in the types sqlite has. The times are stored as text
+-- the driver writes and parses back
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS enrollment_tokens
+(
+    token_hash TEXT      NOT NULL,
+    device     TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    used_at    TIMESTAMP,
+    PRIMARY KEY (token_hash)
+);
+
+CREATE TABLE IF NOT EXISTS device_credentials
+(
+    id            INTEGER   NOT NULL PRIMARY KEY AUTOINCREMENT,
+    device        TEXT      NOT NULL,
+    password_hash TEXT      NOT NULL,
+    secret        TEXT      NOT NULL,
+    created_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at    TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS device_credentials_device_idx ON device_credentials (device);
+
+-- +goose Down
+DROP TABLE IF EXISTS device_credentials;
+DROP TABLE IF EXISTS enrollment_tokens;
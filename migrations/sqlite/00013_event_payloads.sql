@@ -0,0 +1,7 @@
+-- the events keep the message they were logged for as it was received
+
+-- +goose Up
+ALTER TABLE events ADD COLUMN payload BLOB NOT NULL DEFAULT x'';
+
+-- +goose Down
+ALTER TABLE events DROP COLUMN payload;
@@ -0,0 +1,13 @@
+-- the slots are labeled as they are marked on the cabinets and may carry notes on their condition
+
+-- +goose Up
+ALTER TABLE slots ADD COLUMN label TEXT NOT NULL DEFAULT '';
+ALTER TABLE slots ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS slots_cabinet_id_idx ON slots (cabinet_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS slots_cabinet_id_idx;
+
+ALTER TABLE slots DROP COLUMN notes;
+ALTER TABLE slots DROP COLUMN label;
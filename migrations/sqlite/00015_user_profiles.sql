@@ -0,0 +1,18 @@
+-- the users carry the profile the student directory has for them. Sqlite only adds columns with constant
+-- defaults, so the times of the existing users are filled in afterwards
+
+-- +goose Up
+ALTER TABLE users ADD COLUMN class TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'student' CHECK (role IN ('student', 'teacher', 'staff'));
+ALTER TABLE users ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE users ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00';
+ALTER TABLE users ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00';
+
+UPDATE users SET created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN updated_at;
+ALTER TABLE users DROP COLUMN created_at;
+ALTER TABLE users DROP COLUMN active;
+ALTER TABLE users DROP COLUMN role;
+ALTER TABLE users DROP COLUMN class;
@@ -0,0 +1,29 @@
+-- the laptops kept in the cabinets, each with the slot it is stored in, and the laptop involved in the events
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS laptops
+(
+    asset_tag  TEXT      NOT NULL,
+    serial     TEXT      NOT NULL DEFAULT '',
+    model      TEXT      NOT NULL DEFAULT '',
+    home_slot  CHAR(5),
+    status     TEXT      NOT NULL DEFAULT 'available' CHECK (status IN ('available', 'in_use', 'repair', 'retired')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (asset_tag),
+    FOREIGN KEY (home_slot) REFERENCES slots (id) ON DELETE SET NULL
+);
+
+-- a slot is the home of at most one laptop
+CREATE UNIQUE INDEX IF NOT EXISTS laptops_home_slot_idx ON laptops (home_slot);
+
+ALTER TABLE events ADD COLUMN laptop TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS events_laptop_received_at_idx ON events (laptop, received_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS events_laptop_received_at_idx;
+
+ALTER TABLE events DROP COLUMN laptop;
+
+DROP TABLE IF EXISTS laptops;
@@ -0,0 +1,11 @@
+-- the slots carry the version of their state, bumped by every update of them
+
+-- +goose Up
+ALTER TABLE slots ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE slots ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00';
+
+UPDATE slots SET updated_at = CURRENT_TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE slots DROP COLUMN updated_at;
+ALTER TABLE slots DROP COLUMN version;
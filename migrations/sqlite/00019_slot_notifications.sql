@@ -0,0 +1,8 @@
+-- sqlite notifies nobody of the changes of the slots, the single instance publishing its own. The migration
+-- keeps the versions in step with postgres
+
+-- +goose Up
+SELECT 1;
+
+-- +goose Down
+SELECT 1;
@@ -0,0 +1,19 @@
+-- every message received on the stream is kept as it was received, the latest ones of every device
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS raw_messages
+(
+    id          INTEGER   NOT NULL PRIMARY KEY AUTOINCREMENT,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    device      TEXT      NOT NULL,
+    topic       TEXT      NOT NULL,
+    qos         SMALLINT  NOT NULL DEFAULT 0,
+    payload     BLOB      NOT NULL,
+    outcome     TEXT      NOT NULL DEFAULT '',
+    error       TEXT      NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS raw_messages_device_id_idx ON raw_messages (device, id);
+
+-- +goose Down
+DROP TABLE IF EXISTS raw_messages;
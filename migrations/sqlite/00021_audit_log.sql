@@ -0,0 +1,22 @@
+-- the mutating api calls are logged along with who made them and the values they changed, the values as json
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS audit_log
+(
+    id         INTEGER   NOT NULL PRIMARY KEY AUTOINCREMENT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    subject    TEXT      NOT NULL DEFAULT '',
+    role       TEXT      NOT NULL DEFAULT '',
+    method     TEXT      NOT NULL,
+    path       TEXT      NOT NULL,
+    status     INTEGER   NOT NULL,
+    before     TEXT,
+    after      TEXT,
+    source_ip  TEXT      NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at);
+CREATE INDEX IF NOT EXISTS audit_log_subject_idx ON audit_log (subject, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,23 @@
+-- the messages the server publishes for the changes it stores are queued in the transaction storing the change
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS outbox_messages
+(
+    id               INTEGER   NOT NULL PRIMARY KEY AUTOINCREMENT,
+    created_at       TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    topic            TEXT      NOT NULL,
+    qos              SMALLINT  NOT NULL DEFAULT 0,
+    retained         BOOLEAN   NOT NULL DEFAULT FALSE,
+    payload          BLOB      NOT NULL,
+    content_type     TEXT      NOT NULL DEFAULT '',
+    correlation_data BLOB      NOT NULL DEFAULT x'',
+    attempts         INTEGER   NOT NULL DEFAULT 0,
+    last_error       TEXT      NOT NULL DEFAULT '',
+    sent_at          TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS outbox_messages_pending_idx ON outbox_messages (id) WHERE sent_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_messages_sent_at_idx ON outbox_messages (sent_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox_messages;
@@ -0,0 +1,17 @@
+-- the occupancy of every cabinet is sampled periodically
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS occupancy_samples
+(
+    id         INTEGER   NOT NULL PRIMARY KEY AUTOINCREMENT,
+    sampled_at TIMESTAMP NOT NULL,
+    cabinet_id TEXT      NOT NULL,
+    slots      INTEGER   NOT NULL,
+    taken      INTEGER   NOT NULL,
+    UNIQUE (cabinet_id, sampled_at)
+);
+
+CREATE INDEX IF NOT EXISTS occupancy_samples_sampled_at_idx ON occupancy_samples (sampled_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS occupancy_samples;
@@ -0,0 +1,18 @@
+-- the open loans of a user are numbered from 1 by their seat, in the order they were taken
+
+-- +goose Up
+ALTER TABLE loans ADD COLUMN seat SMALLINT NOT NULL DEFAULT 1;
+
+UPDATE loans
+SET seat = ranked.n
+FROM (SELECT id, row_number() OVER (PARTITION BY rfid ORDER BY taken_at, id) AS n
+      FROM loans
+      WHERE returned_at IS NULL) ranked
+WHERE loans.id = ranked.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS loans_open_seat_idx ON loans (rfid, seat) WHERE returned_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS loans_open_seat_idx;
+
+ALTER TABLE loans DROP COLUMN seat;
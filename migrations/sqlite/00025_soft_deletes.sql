@@ -0,0 +1,11 @@
+-- the decommissioned slots and the departed users are marked deleted rather than deleted
+
+-- +goose Up
+ALTER TABLE slots ADD COLUMN deleted_at TIMESTAMP;
+
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN deleted_at;
+
+ALTER TABLE slots DROP COLUMN deleted_at;
@@ -0,0 +1,19 @@
+-- every connection of a device is kept as a session, a device having a single one open at a time
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS device_sessions
+(
+    id              INTEGER   NOT NULL PRIMARY KEY AUTOINCREMENT,
+    device          TEXT      NOT NULL,
+    connected_at    TIMESTAMP NOT NULL,
+    connected_by    TEXT      NOT NULL,
+    disconnected_at TIMESTAMP,
+    disconnected_by TEXT      NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS device_sessions_device_idx ON device_sessions (device, connected_at);
+
+CREATE UNIQUE INDEX IF NOT EXISTS device_sessions_open_idx ON device_sessions (device) WHERE disconnected_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS device_sessions;
@@ -0,0 +1,7 @@
+-- the dispatcher claims the messages it is about to publish until claimed_until
+
+-- +goose Up
+ALTER TABLE outbox_messages ADD COLUMN claimed_until TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE outbox_messages DROP COLUMN claimed_until;
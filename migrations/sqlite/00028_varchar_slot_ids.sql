@@ -0,0 +1,8 @@
+-- the slot IDs are stored unpadded in postgres. Sqlite never padded the CHAR(5) columns, whose TEXT affinity keeps
+-- the IDs as they were written, so there is nothing to change
+
+-- +goose Up
+SELECT 1;
+
+-- +goose Down
+SELECT 1;
@@ -4,9 +4,41 @@
 package models
 
 var TableNames = struct {
-	Slots string
-	Users string
+	AuditLog          string
+	Commands          string
+	DeviceCredentials string
+	DeviceSessions    string
+	Devices           string
+	EnrollmentTokens  string
+	Events            string
+	FailedMessages    string
+	FirmwareReleases  string
+	Laptops           string
+	Loans             string
+	OccupancySamples  string
+	OutboxMessages    string
+	RawMessages       string
+	Roles             string
+	Slots             string
+	Users             string
+	Webhooks          string
 }{
-	Slots: "slots",
-	Users: "users",
+	AuditLog:          "audit_log",
+	Commands:          "commands",
+	DeviceCredentials: "device_credentials",
+	DeviceSessions:    "device_sessions",
+	Devices:           "devices",
+	EnrollmentTokens:  "enrollment_tokens",
+	Events:            "events",
+	FailedMessages:    "failed_messages",
+	FirmwareReleases:  "firmware_releases",
+	Laptops:           "laptops",
+	Loans:             "loans",
+	OccupancySamples:  "occupancy_samples",
+	OutboxMessages:    "outbox_messages",
+	RawMessages:       "raw_messages",
+	Roles:             "roles",
+	Slots:             "slots",
+	Users:             "users",
+	Webhooks:          "webhooks",
 }
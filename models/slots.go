@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
@@ -23,88 +24,118 @@ import (
 
 // Slot is an object representing the database table.
 type Slot struct {
-	ID      string `boil:"id" json:"id" toml:"id" yaml:"id"`
-	IsTaken bool   `boil:"is_taken" json:"is_taken" toml:"is_taken" yaml:"is_taken"`
-	TakenBy string `boil:"taken_by" json:"taken_by" toml:"taken_by" yaml:"taken_by"`
+	ID        string    `boil:"id" json:"id" toml:"id" yaml:"id"`
+	IsTaken   bool      `boil:"is_taken" json:"is_taken" toml:"is_taken" yaml:"is_taken"`
+	TakenBy   string    `boil:"taken_by" json:"taken_by" toml:"taken_by" yaml:"taken_by"`
+	CabinetID string    `boil:"cabinet_id" json:"cabinet_id" toml:"cabinet_id" yaml:"cabinet_id"`
+	Row       int       `boil:"row" json:"row" toml:"row" yaml:"row"`
+	Column    int       `boil:"column" json:"column" toml:"column" yaml:"column"`
+	Label     string    `boil:"label" json:"label" toml:"label" yaml:"label"`
+	Notes     string    `boil:"notes" json:"notes" toml:"notes" yaml:"notes"`
+	Version   int64     `boil:"version" json:"version" toml:"version" yaml:"version"`
+	UpdatedAt time.Time `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+	DeletedAt null.Time `boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
 
 	R *slotR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L slotL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var SlotColumns = struct {
-	ID      string
-	IsTaken string
-	TakenBy string
+	ID        string
+	IsTaken   string
+	TakenBy   string
+	CabinetID string
+	Row       string
+	Column    string
+	Label     string
+	Notes     string
+	Version   string
+	UpdatedAt string
+	DeletedAt string
 }{
-	ID:      "id",
-	IsTaken: "is_taken",
-	TakenBy: "taken_by",
+	ID:        "id",
+	IsTaken:   "is_taken",
+	TakenBy:   "taken_by",
+	CabinetID: "cabinet_id",
+	Row:       "row",
+	Column:    "column",
+	Label:     "label",
+	Notes:     "notes",
+	Version:   "version",
+	UpdatedAt: "updated_at",
+	DeletedAt: "deleted_at",
 }
 
 var SlotTableColumns = struct {
-	ID      string
-	IsTaken string
-	TakenBy string
+	ID        string
+	IsTaken   string
+	TakenBy   string
+	CabinetID string
+	Row       string
+	Column    string
+	Label     string
+	Notes     string
+	Version   string
+	UpdatedAt string
+	DeletedAt string
 }{
-	ID:      "slots.id",
-	IsTaken: "slots.is_taken",
-	TakenBy: "slots.taken_by",
+	ID:        "slots.id",
+	IsTaken:   "slots.is_taken",
+	TakenBy:   "slots.taken_by",
+	CabinetID: "slots.cabinet_id",
+	Row:       "slots.row",
+	Column:    "slots.column",
+	Label:     "slots.label",
+	Notes:     "slots.notes",
+	Version:   "slots.version",
+	UpdatedAt: "slots.updated_at",
+	DeletedAt: "slots.deleted_at",
 }
 
 // Generated where
 
-type whereHelperstring struct{ field string }
-
-func (w whereHelperstring) EQ(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
-func (w whereHelperstring) NEQ(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
-func (w whereHelperstring) LT(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
-func (w whereHelperstring) LTE(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
-func (w whereHelperstring) GT(x string) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
-func (w whereHelperstring) GTE(x string) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
-func (w whereHelperstring) IN(slice []string) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
-}
-func (w whereHelperstring) NIN(slice []string) qm.QueryMod {
-	values := make([]interface{}, 0, len(slice))
-	for _, value := range slice {
-		values = append(values, value)
-	}
-	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
-}
-
-type whereHelperbool struct{ field string }
-
-func (w whereHelperbool) EQ(x bool) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
-func (w whereHelperbool) NEQ(x bool) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
-func (w whereHelperbool) LT(x bool) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
-func (w whereHelperbool) LTE(x bool) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
-func (w whereHelperbool) GT(x bool) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
-func (w whereHelperbool) GTE(x bool) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
-
 var SlotWhere = struct {
-	ID      whereHelperstring
-	IsTaken whereHelperbool
-	TakenBy whereHelperstring
+	ID        whereHelperstring
+	IsTaken   whereHelperbool
+	TakenBy   whereHelperstring
+	CabinetID whereHelperstring
+	Row       whereHelperint
+	Column    whereHelperint
+	Label     whereHelperstring
+	Notes     whereHelperstring
+	Version   whereHelperint64
+	UpdatedAt whereHelpertime_Time
+	DeletedAt whereHelpernull_Time
 }{
-	ID:      whereHelperstring{field: "\"slots\".\"id\""},
-	IsTaken: whereHelperbool{field: "\"slots\".\"is_taken\""},
-	TakenBy: whereHelperstring{field: "\"slots\".\"taken_by\""},
+	ID:        whereHelperstring{field: "\"slots\".\"id\""},
+	IsTaken:   whereHelperbool{field: "\"slots\".\"is_taken\""},
+	TakenBy:   whereHelperstring{field: "\"slots\".\"taken_by\""},
+	CabinetID: whereHelperstring{field: "\"slots\".\"cabinet_id\""},
+	Row:       whereHelperint{field: "\"slots\".\"row\""},
+	Column:    whereHelperint{field: "\"slots\".\"column\""},
+	Label:     whereHelperstring{field: "\"slots\".\"label\""},
+	Notes:     whereHelperstring{field: "\"slots\".\"notes\""},
+	Version:   whereHelperint64{field: "\"slots\".\"version\""},
+	UpdatedAt: whereHelpertime_Time{field: "\"slots\".\"updated_at\""},
+	DeletedAt: whereHelpernull_Time{field: "\"slots\".\"deleted_at\""},
 }
 
 // SlotRels is where relationship names are stored.
 var SlotRels = struct {
-	TakenByUser string
+	TakenByUser    string
+	HomeSlotLaptop string
+	Loans          string
 }{
-	TakenByUser: "TakenByUser",
+	TakenByUser:    "TakenByUser",
+	HomeSlotLaptop: "HomeSlotLaptop",
+	Loans:          "Loans",
 }
 
 // slotR is where relationships are stored.
 type slotR struct {
-	TakenByUser *User `boil:"TakenByUser" json:"TakenByUser" toml:"TakenByUser" yaml:"TakenByUser"`
+	TakenByUser    *User     `boil:"TakenByUser" json:"TakenByUser" toml:"TakenByUser" yaml:"TakenByUser"`
+	HomeSlotLaptop *Laptop   `boil:"HomeSlotLaptop" json:"HomeSlotLaptop" toml:"HomeSlotLaptop" yaml:"HomeSlotLaptop"`
+	Loans          LoanSlice `boil:"Loans" json:"Loans" toml:"Loans" yaml:"Loans"`
 }
 
 // NewStruct creates a new relationship struct
@@ -119,13 +150,27 @@ func (r *slotR) GetTakenByUser() *User {
 	return r.TakenByUser
 }
 
+func (r *slotR) GetHomeSlotLaptop() *Laptop {
+	if r == nil {
+		return nil
+	}
+	return r.HomeSlotLaptop
+}
+
+func (r *slotR) GetLoans() LoanSlice {
+	if r == nil {
+		return nil
+	}
+	return r.Loans
+}
+
 // slotL is where Load methods for each relationship are stored.
 type slotL struct{}
 
 var (
-	slotAllColumns            = []string{"id", "is_taken", "taken_by"}
+	slotAllColumns            = []string{"id", "is_taken", "taken_by", "cabinet_id", "row", "column", "label", "notes", "version", "updated_at", "deleted_at"}
 	slotColumnsWithoutDefault = []string{"id", "taken_by"}
-	slotColumnsWithDefault    = []string{"is_taken"}
+	slotColumnsWithDefault    = []string{"is_taken", "cabinet_id", "row", "column", "label", "notes", "version", "updated_at", "deleted_at"}
 	slotPrimaryKeyColumns     = []string{"id"}
 	slotGeneratedColumns      = []string{}
 )
@@ -439,6 +484,31 @@ func (o *Slot) TakenByUser(mods ...qm.QueryMod) userQuery {
 	return Users(queryMods...)
 }
 
+// HomeSlotLaptop pointed to by the foreign key.
+func (o *Slot) HomeSlotLaptop(mods ...qm.QueryMod) laptopQuery {
+	queryMods := []qm.QueryMod{
+		qm.Where("\"home_slot\" = ?", o.ID),
+	}
+
+	queryMods = append(queryMods, mods...)
+
+	return Laptops(queryMods...)
+}
+
+// Loans retrieves all the loan's Loans with an executor.
+func (o *Slot) Loans(mods ...qm.QueryMod) loanQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"loans\".\"slot\"=?", o.ID),
+	)
+
+	return Loans(queryMods...)
+}
+
 // LoadTakenByUser allows an eager lookup of values, cached into the
 // loaded structs of the objects. This is for an N-1 relationship.
 func (slotL) LoadTakenByUser(ctx context.Context, e boil.ContextExecutor, singular bool, maybeSlot interface{}, mods queries.Applicator) error {
@@ -499,6 +569,7 @@ func (slotL) LoadTakenByUser(ctx context.Context, e boil.ContextExecutor, singul
 	query := NewQuery(
 		qm.From(`users`),
 		qm.WhereIn(`users.id in ?`, args...),
+		qmhelper.WhereIsNull(`users.deleted_at`),
 	)
 	if mods != nil {
 		mods.Apply(query)
@@ -559,6 +630,237 @@ func (slotL) LoadTakenByUser(ctx context.Context, e boil.ContextExecutor, singul
 	return nil
 }
 
+// LoadHomeSlotLaptop allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-1 relationship.
+func (slotL) LoadHomeSlotLaptop(ctx context.Context, e boil.ContextExecutor, singular bool, maybeSlot interface{}, mods queries.Applicator) error {
+	var slice []*Slot
+	var object *Slot
+
+	if singular {
+		var ok bool
+		object, ok = maybeSlot.(*Slot)
+		if !ok {
+			object = new(Slot)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeSlot)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeSlot))
+			}
+		}
+	} else {
+		s, ok := maybeSlot.(*[]*Slot)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeSlot)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeSlot))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &slotR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &slotR{}
+			}
+
+			for _, a := range args {
+				if queries.Equal(a, obj.ID) {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`laptops`),
+		qm.WhereIn(`laptops.home_slot in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load Laptop")
+	}
+
+	var resultSlice []*Laptop
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice Laptop")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results of eager load for laptops")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for laptops")
+	}
+
+	if len(slotAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(resultSlice) == 0 {
+		return nil
+	}
+
+	if singular {
+		foreign := resultSlice[0]
+		object.R.HomeSlotLaptop = foreign
+		if foreign.R == nil {
+			foreign.R = &laptopR{}
+		}
+		foreign.R.HomeSlotSlot = object
+	}
+
+	for _, local := range slice {
+		for _, foreign := range resultSlice {
+			if queries.Equal(local.ID, foreign.HomeSlot) {
+				local.R.HomeSlotLaptop = foreign
+				if foreign.R == nil {
+					foreign.R = &laptopR{}
+				}
+				foreign.R.HomeSlotSlot = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// LoadLoans allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (slotL) LoadLoans(ctx context.Context, e boil.ContextExecutor, singular bool, maybeSlot interface{}, mods queries.Applicator) error {
+	var slice []*Slot
+	var object *Slot
+
+	if singular {
+		var ok bool
+		object, ok = maybeSlot.(*Slot)
+		if !ok {
+			object = new(Slot)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeSlot)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeSlot))
+			}
+		}
+	} else {
+		s, ok := maybeSlot.(*[]*Slot)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeSlot)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeSlot))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &slotR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &slotR{}
+			}
+
+			for _, a := range args {
+				if a == obj.ID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`loans`),
+		qm.WhereIn(`loans.slot in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load loans")
+	}
+
+	var resultSlice []*Loan
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice loans")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on loans")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for loans")
+	}
+
+	if len(loanAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.Loans = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &loanR{}
+			}
+			foreign.R.LoanSlot = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.Slot {
+				local.R.Loans = append(local.R.Loans, foreign)
+				if foreign.R == nil {
+					foreign.R = &loanR{}
+				}
+				foreign.R.LoanSlot = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // SetTakenByUserG of the slot to the related item.
 // Sets o.R.TakenByUser to related.
 // Adds o to related.R.TakenBySlots.
@@ -614,9 +916,161 @@ func (o *Slot) SetTakenByUser(ctx context.Context, exec boil.ContextExecutor, in
 	return nil
 }
 
+// SetHomeSlotLaptopG of the slot to the related item.
+// Sets o.R.HomeSlotLaptop to related.
+// Adds o to related.R.HomeSlotSlot.
+// Uses the global database handle.
+func (o *Slot) SetHomeSlotLaptopG(ctx context.Context, insert bool, related *Laptop) error {
+	return o.SetHomeSlotLaptop(ctx, boil.GetContextDB(), insert, related)
+}
+
+// SetHomeSlotLaptop of the slot to the related item.
+// Sets o.R.HomeSlotLaptop to related.
+// Adds o to related.R.HomeSlotSlot.
+func (o *Slot) SetHomeSlotLaptop(ctx context.Context, exec boil.ContextExecutor, insert bool, related *Laptop) error {
+	var err error
+
+	if insert {
+		queries.Assign(&related.HomeSlot, o.ID)
+
+		if err = related.Insert(ctx, exec, boil.Infer()); err != nil {
+			return errors.Wrap(err, "failed to insert into foreign table")
+		}
+	} else {
+		updateQuery := fmt.Sprintf(
+			"UPDATE \"laptops\" SET %s WHERE %s",
+			strmangle.SetParamNames("\"", "\"", 1, []string{"home_slot"}),
+			strmangle.WhereClause("\"", "\"", 2, laptopPrimaryKeyColumns),
+		)
+		values := []interface{}{o.ID, related.AssetTag}
+
+		if boil.IsDebug(ctx) {
+			writer := boil.DebugWriterFrom(ctx)
+			fmt.Fprintln(writer, updateQuery)
+			fmt.Fprintln(writer, values)
+		}
+		if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+			return errors.Wrap(err, "failed to update foreign table")
+		}
+
+		queries.Assign(&related.HomeSlot, o.ID)
+	}
+
+	if o.R == nil {
+		o.R = &slotR{
+			HomeSlotLaptop: related,
+		}
+	} else {
+		o.R.HomeSlotLaptop = related
+	}
+
+	if related.R == nil {
+		related.R = &laptopR{
+			HomeSlotSlot: o,
+		}
+	} else {
+		related.R.HomeSlotSlot = o
+	}
+	return nil
+}
+
+// RemoveHomeSlotLaptopG relationship.
+// Sets o.R.HomeSlotLaptop to nil.
+// Removes o from all passed in related items' relationships struct.
+// Uses the global database handle.
+func (o *Slot) RemoveHomeSlotLaptopG(ctx context.Context, related *Laptop) error {
+	return o.RemoveHomeSlotLaptop(ctx, boil.GetContextDB(), related)
+}
+
+// RemoveHomeSlotLaptop relationship.
+// Sets o.R.HomeSlotLaptop to nil.
+// Removes o from all passed in related items' relationships struct.
+func (o *Slot) RemoveHomeSlotLaptop(ctx context.Context, exec boil.ContextExecutor, related *Laptop) error {
+	var err error
+
+	queries.SetScanner(&related.HomeSlot, nil)
+	if _, err = related.Update(ctx, exec, boil.Whitelist("home_slot")); err != nil {
+		return errors.Wrap(err, "failed to update local table")
+	}
+
+	if o.R != nil {
+		o.R.HomeSlotLaptop = nil
+	}
+
+	if related == nil || related.R == nil {
+		return nil
+	}
+
+	related.R.HomeSlotSlot = nil
+
+	return nil
+}
+
+// AddLoansG adds the given related objects to the existing relationships
+// of the slot, optionally inserting them as new records.
+// Appends related to o.R.Loans.
+// Sets related.R.LoanSlot appropriately.
+// Uses the global database handle.
+func (o *Slot) AddLoansG(ctx context.Context, insert bool, related ...*Loan) error {
+	return o.AddLoans(ctx, boil.GetContextDB(), insert, related...)
+}
+
+// AddLoans adds the given related objects to the existing relationships
+// of the slot, optionally inserting them as new records.
+// Appends related to o.R.Loans.
+// Sets related.R.LoanSlot appropriately.
+func (o *Slot) AddLoans(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*Loan) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.Slot = o.ID
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"loans\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"slot"}),
+				strmangle.WhereClause("\"", "\"", 2, loanPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.Slot = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &slotR{
+			Loans: related,
+		}
+	} else {
+		o.R.Loans = append(o.R.Loans, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &loanR{
+				LoanSlot: o,
+			}
+		} else {
+			rel.R.LoanSlot = o
+		}
+	}
+	return nil
+}
+
 // Slots retrieves all the records using an executor.
 func Slots(mods ...qm.QueryMod) slotQuery {
-	mods = append(mods, qm.From("\"slots\""))
+	mods = append(mods, qm.From("\"slots\""), qmhelper.WhereIsNull("\"slots\".\"deleted_at\""))
 	q := NewQuery(mods...)
 	if len(queries.GetSelect(q)) == 0 {
 		queries.SetSelect(q, []string{"\"slots\".*"})
@@ -640,7 +1094,7 @@ func FindSlot(ctx context.Context, exec boil.ContextExecutor, iD string, selectC
 		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
 	}
 	query := fmt.Sprintf(
-		"select %s from \"slots\" where \"id\"=$1", sel,
+		"select %s from \"slots\" where \"id\"=$1 and \"deleted_at\" is null", sel,
 	)
 
 	q := queries.Raw(query, iD)
@@ -673,6 +1127,13 @@ func (o *Slot) Insert(ctx context.Context, exec boil.ContextExecutor, columns bo
 	}
 
 	var err error
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.UpdatedAt.IsZero() {
+			o.UpdatedAt = currTime
+		}
+	}
 
 	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
 		return err
@@ -754,6 +1215,12 @@ func (o *Slot) UpdateG(ctx context.Context, columns boil.Columns) (int64, error)
 // See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
 // Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
 func (o *Slot) Update(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) (int64, error) {
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		o.UpdatedAt = currTime
+	}
+
 	var err error
 	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
 		return 0, err
@@ -899,6 +1366,11 @@ func (o *Slot) Upsert(ctx context.Context, exec boil.ContextExecutor, updateOnCo
 	if o == nil {
 		return errors.New("models: no slots provided for upsert")
 	}
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		o.UpdatedAt = currTime
+	}
 
 	if err := o.doBeforeUpsertHooks(ctx, exec); err != nil {
 		return err
@@ -1011,13 +1483,13 @@ func (o *Slot) Upsert(ctx context.Context, exec boil.ContextExecutor, updateOnCo
 
 // DeleteG deletes a single Slot record.
 // DeleteG will match against the primary key column to find the record to delete.
-func (o *Slot) DeleteG(ctx context.Context) (int64, error) {
-	return o.Delete(ctx, boil.GetContextDB())
+func (o *Slot) DeleteG(ctx context.Context, hardDelete bool) (int64, error) {
+	return o.Delete(ctx, boil.GetContextDB(), hardDelete)
 }
 
 // Delete deletes a single Slot record with an executor.
 // Delete will match against the primary key column to find the record to delete.
-func (o *Slot) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+func (o *Slot) Delete(ctx context.Context, exec boil.ContextExecutor, hardDelete bool) (int64, error) {
 	if o == nil {
 		return 0, errors.New("models: no Slot provided for delete")
 	}
@@ -1026,8 +1498,26 @@ func (o *Slot) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, er
 		return 0, err
 	}
 
-	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), slotPrimaryKeyMapping)
-	sql := "DELETE FROM \"slots\" WHERE \"id\"=$1"
+	var (
+		sql  string
+		args []interface{}
+	)
+	if hardDelete {
+		args = queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), slotPrimaryKeyMapping)
+		sql = "DELETE FROM \"slots\" WHERE \"id\"=$1"
+	} else {
+		currTime := time.Now().In(boil.GetLocation())
+		o.DeletedAt = null.TimeFrom(currTime)
+		wl := []string{"deleted_at"}
+		sql = fmt.Sprintf("UPDATE \"slots\" SET %s WHERE \"id\"=$2",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+		)
+		valueMapping, err := queries.BindMapping(slotType, slotMapping, append(wl, slotPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+		args = queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), valueMapping)
+	}
 
 	if boil.IsDebug(ctx) {
 		writer := boil.DebugWriterFrom(ctx)
@@ -1051,17 +1541,22 @@ func (o *Slot) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, er
 	return rowsAff, nil
 }
 
-func (q slotQuery) DeleteAllG(ctx context.Context) (int64, error) {
-	return q.DeleteAll(ctx, boil.GetContextDB())
+func (q slotQuery) DeleteAllG(ctx context.Context, hardDelete bool) (int64, error) {
+	return q.DeleteAll(ctx, boil.GetContextDB(), hardDelete)
 }
 
 // DeleteAll deletes all matching rows.
-func (q slotQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+func (q slotQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor, hardDelete bool) (int64, error) {
 	if q.Query == nil {
 		return 0, errors.New("models: no slotQuery provided for delete all")
 	}
 
-	queries.SetDelete(q.Query)
+	if hardDelete {
+		queries.SetDelete(q.Query)
+	} else {
+		currTime := time.Now().In(boil.GetLocation())
+		queries.SetUpdate(q.Query, M{"deleted_at": currTime})
+	}
 
 	result, err := q.Query.ExecContext(ctx, exec)
 	if err != nil {
@@ -1077,12 +1572,12 @@ func (q slotQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (in
 }
 
 // DeleteAllG deletes all rows in the slice.
-func (o SlotSlice) DeleteAllG(ctx context.Context) (int64, error) {
-	return o.DeleteAll(ctx, boil.GetContextDB())
+func (o SlotSlice) DeleteAllG(ctx context.Context, hardDelete bool) (int64, error) {
+	return o.DeleteAll(ctx, boil.GetContextDB(), hardDelete)
 }
 
 // DeleteAll deletes all rows in the slice, using an executor.
-func (o SlotSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+func (o SlotSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor, hardDelete bool) (int64, error) {
 	if len(o) == 0 {
 		return 0, nil
 	}
@@ -1095,15 +1590,32 @@ func (o SlotSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (in
 		}
 	}
 
-	var args []interface{}
-	for _, obj := range o {
-		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), slotPrimaryKeyMapping)
-		args = append(args, pkeyArgs...)
+	var (
+		sql  string
+		args []interface{}
+	)
+	if hardDelete {
+		for _, obj := range o {
+			pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), slotPrimaryKeyMapping)
+			args = append(args, pkeyArgs...)
+		}
+		sql = "DELETE FROM \"slots\" WHERE " +
+			strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, slotPrimaryKeyColumns, len(o))
+	} else {
+		currTime := time.Now().In(boil.GetLocation())
+		for _, obj := range o {
+			pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), slotPrimaryKeyMapping)
+			args = append(args, pkeyArgs...)
+			obj.DeletedAt = null.TimeFrom(currTime)
+		}
+		wl := []string{"deleted_at"}
+		sql = fmt.Sprintf("UPDATE \"slots\" SET %s WHERE "+
+			strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 2, slotPrimaryKeyColumns, len(o)),
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+		)
+		args = append([]interface{}{currTime}, args...)
 	}
 
-	sql := "DELETE FROM \"slots\" WHERE " +
-		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, slotPrimaryKeyColumns, len(o))
-
 	if boil.IsDebug(ctx) {
 		writer := boil.DebugWriterFrom(ctx)
 		fmt.Fprintln(writer, sql)
@@ -1176,7 +1688,8 @@ func (o *SlotSlice) ReloadAll(ctx context.Context, exec boil.ContextExecutor) er
 	}
 
 	sql := "SELECT \"slots\".* FROM \"slots\" WHERE " +
-		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, slotPrimaryKeyColumns, len(*o))
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, slotPrimaryKeyColumns, len(*o)) +
+		"and \"deleted_at\" is null"
 
 	q := queries.Raw(sql, args...)
 
@@ -1198,7 +1711,7 @@ func SlotExistsG(ctx context.Context, iD string) (bool, error) {
 // SlotExists checks if the Slot row exists.
 func SlotExists(ctx context.Context, exec boil.ContextExecutor, iD string) (bool, error) {
 	var exists bool
-	sql := "select exists(select 1 from \"slots\" where \"id\"=$1 limit 1)"
+	sql := "select exists(select 1 from \"slots\" where \"id\"=$1 and \"deleted_at\" is null limit 1)"
 
 	if boil.IsDebug(ctx) {
 		writer := boil.DebugWriterFrom(ctx)
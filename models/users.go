@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
@@ -23,48 +24,107 @@ import (
 
 // User is an object representing the database table.
 type User struct {
-	ID    string `boil:"id" json:"id" toml:"id" yaml:"id"`
-	Login string `boil:"login" json:"login" toml:"login" yaml:"login"`
+	ID        string    `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Login     string    `boil:"login" json:"login" toml:"login" yaml:"login"`
+	Name      string    `boil:"name" json:"name" toml:"name" yaml:"name"`
+	Email     string    `boil:"email" json:"email" toml:"email" yaml:"email"`
+	Class     string    `boil:"class" json:"class" toml:"class" yaml:"class"`
+	Role      string    `boil:"role" json:"role" toml:"role" yaml:"role"`
+	Active    bool      `boil:"active" json:"active" toml:"active" yaml:"active"`
+	CreatedAt time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	UpdatedAt time.Time `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+	DeletedAt null.Time `boil:"deleted_at" json:"deleted_at,omitempty" toml:"deleted_at" yaml:"deleted_at,omitempty"`
 
 	R *userR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L userL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var UserColumns = struct {
-	ID    string
-	Login string
+	ID        string
+	Login     string
+	Name      string
+	Email     string
+	Class     string
+	Role      string
+	Active    string
+	CreatedAt string
+	UpdatedAt string
+	DeletedAt string
 }{
-	ID:    "id",
-	Login: "login",
+	ID:        "id",
+	Login:     "login",
+	Name:      "name",
+	Email:     "email",
+	Class:     "class",
+	Role:      "role",
+	Active:    "active",
+	CreatedAt: "created_at",
+	UpdatedAt: "updated_at",
+	DeletedAt: "deleted_at",
 }
 
 var UserTableColumns = struct {
-	ID    string
-	Login string
+	ID        string
+	Login     string
+	Name      string
+	Email     string
+	Class     string
+	Role      string
+	Active    string
+	CreatedAt string
+	UpdatedAt string
+	DeletedAt string
 }{
-	ID:    "users.id",
-	Login: "users.login",
+	ID:        "users.id",
+	Login:     "users.login",
+	Name:      "users.name",
+	Email:     "users.email",
+	Class:     "users.class",
+	Role:      "users.role",
+	Active:    "users.active",
+	CreatedAt: "users.created_at",
+	UpdatedAt: "users.updated_at",
+	DeletedAt: "users.deleted_at",
 }
 
 // Generated where
 
 var UserWhere = struct {
-	ID    whereHelperstring
-	Login whereHelperstring
+	ID        whereHelperstring
+	Login     whereHelperstring
+	Name      whereHelperstring
+	Email     whereHelperstring
+	Class     whereHelperstring
+	Role      whereHelperstring
+	Active    whereHelperbool
+	CreatedAt whereHelpertime_Time
+	UpdatedAt whereHelpertime_Time
+	DeletedAt whereHelpernull_Time
 }{
-	ID:    whereHelperstring{field: "\"users\".\"id\""},
-	Login: whereHelperstring{field: "\"users\".\"login\""},
+	ID:        whereHelperstring{field: "\"users\".\"id\""},
+	Login:     whereHelperstring{field: "\"users\".\"login\""},
+	Name:      whereHelperstring{field: "\"users\".\"name\""},
+	Email:     whereHelperstring{field: "\"users\".\"email\""},
+	Class:     whereHelperstring{field: "\"users\".\"class\""},
+	Role:      whereHelperstring{field: "\"users\".\"role\""},
+	Active:    whereHelperbool{field: "\"users\".\"active\""},
+	CreatedAt: whereHelpertime_Time{field: "\"users\".\"created_at\""},
+	UpdatedAt: whereHelpertime_Time{field: "\"users\".\"updated_at\""},
+	DeletedAt: whereHelpernull_Time{field: "\"users\".\"deleted_at\""},
 }
 
 // UserRels is where relationship names are stored.
 var UserRels = struct {
+	RfidLoans    string
 	TakenBySlots string
 }{
+	RfidLoans:    "RfidLoans",
 	TakenBySlots: "TakenBySlots",
 }
 
 // userR is where relationships are stored.
 type userR struct {
+	RfidLoans    LoanSlice `boil:"RfidLoans" json:"RfidLoans" toml:"RfidLoans" yaml:"RfidLoans"`
 	TakenBySlots SlotSlice `boil:"TakenBySlots" json:"TakenBySlots" toml:"TakenBySlots" yaml:"TakenBySlots"`
 }
 
@@ -73,6 +133,13 @@ func (*userR) NewStruct() *userR {
 	return &userR{}
 }
 
+func (r *userR) GetRfidLoans() LoanSlice {
+	if r == nil {
+		return nil
+	}
+	return r.RfidLoans
+}
+
 func (r *userR) GetTakenBySlots() SlotSlice {
 	if r == nil {
 		return nil
@@ -84,9 +151,9 @@ func (r *userR) GetTakenBySlots() SlotSlice {
 type userL struct{}
 
 var (
-	userAllColumns            = []string{"id", "login"}
+	userAllColumns            = []string{"id", "login", "name", "email", "class", "role", "active", "created_at", "updated_at", "deleted_at"}
 	userColumnsWithoutDefault = []string{"id"}
-	userColumnsWithDefault    = []string{"login"}
+	userColumnsWithDefault    = []string{"login", "name", "email", "class", "role", "active", "created_at", "updated_at", "deleted_at"}
 	userPrimaryKeyColumns     = []string{"id"}
 	userGeneratedColumns      = []string{}
 )
@@ -389,6 +456,20 @@ func (q userQuery) Exists(ctx context.Context, exec boil.ContextExecutor) (bool,
 	return count > 0, nil
 }
 
+// RfidLoans retrieves all the loan's Loans with an executor via rfid column.
+func (o *User) RfidLoans(mods ...qm.QueryMod) loanQuery {
+	var queryMods []qm.QueryMod
+	if len(mods) != 0 {
+		queryMods = append(queryMods, mods...)
+	}
+
+	queryMods = append(queryMods,
+		qm.Where("\"loans\".\"rfid\"=?", o.ID),
+	)
+
+	return Loans(queryMods...)
+}
+
 // TakenBySlots retrieves all the slot's Slots with an executor via taken_by column.
 func (o *User) TakenBySlots(mods ...qm.QueryMod) slotQuery {
 	var queryMods []qm.QueryMod
@@ -403,6 +484,120 @@ func (o *User) TakenBySlots(mods ...qm.QueryMod) slotQuery {
 	return Slots(queryMods...)
 }
 
+// LoadRfidLoans allows an eager lookup of values, cached into the
+// loaded structs of the objects. This is for a 1-M or N-M relationship.
+func (userL) LoadRfidLoans(ctx context.Context, e boil.ContextExecutor, singular bool, maybeUser interface{}, mods queries.Applicator) error {
+	var slice []*User
+	var object *User
+
+	if singular {
+		var ok bool
+		object, ok = maybeUser.(*User)
+		if !ok {
+			object = new(User)
+			ok = queries.SetFromEmbeddedStruct(&object, &maybeUser)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", object, maybeUser))
+			}
+		}
+	} else {
+		s, ok := maybeUser.(*[]*User)
+		if ok {
+			slice = *s
+		} else {
+			ok = queries.SetFromEmbeddedStruct(&slice, maybeUser)
+			if !ok {
+				return errors.New(fmt.Sprintf("failed to set %T from embedded struct %T", slice, maybeUser))
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 1)
+	if singular {
+		if object.R == nil {
+			object.R = &userR{}
+		}
+		args = append(args, object.ID)
+	} else {
+	Outer:
+		for _, obj := range slice {
+			if obj.R == nil {
+				obj.R = &userR{}
+			}
+
+			for _, a := range args {
+				if a == obj.ID {
+					continue Outer
+				}
+			}
+
+			args = append(args, obj.ID)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query := NewQuery(
+		qm.From(`loans`),
+		qm.WhereIn(`loans.rfid in ?`, args...),
+	)
+	if mods != nil {
+		mods.Apply(query)
+	}
+
+	results, err := query.QueryContext(ctx, e)
+	if err != nil {
+		return errors.Wrap(err, "failed to eager load loans")
+	}
+
+	var resultSlice []*Loan
+	if err = queries.Bind(results, &resultSlice); err != nil {
+		return errors.Wrap(err, "failed to bind eager loaded slice loans")
+	}
+
+	if err = results.Close(); err != nil {
+		return errors.Wrap(err, "failed to close results in eager load on loans")
+	}
+	if err = results.Err(); err != nil {
+		return errors.Wrap(err, "error occurred during iteration of eager loaded relations for loans")
+	}
+
+	if len(loanAfterSelectHooks) != 0 {
+		for _, obj := range resultSlice {
+			if err := obj.doAfterSelectHooks(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+	if singular {
+		object.R.RfidLoans = resultSlice
+		for _, foreign := range resultSlice {
+			if foreign.R == nil {
+				foreign.R = &loanR{}
+			}
+			foreign.R.RfidUser = object
+		}
+		return nil
+	}
+
+	for _, foreign := range resultSlice {
+		for _, local := range slice {
+			if local.ID == foreign.Rfid {
+				local.R.RfidLoans = append(local.R.RfidLoans, foreign)
+				if foreign.R == nil {
+					foreign.R = &loanR{}
+				}
+				foreign.R.RfidUser = local
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // LoadTakenBySlots allows an eager lookup of values, cached into the
 // loaded structs of the objects. This is for a 1-M or N-M relationship.
 func (userL) LoadTakenBySlots(ctx context.Context, e boil.ContextExecutor, singular bool, maybeUser interface{}, mods queries.Applicator) error {
@@ -461,6 +656,7 @@ func (userL) LoadTakenBySlots(ctx context.Context, e boil.ContextExecutor, singu
 	query := NewQuery(
 		qm.From(`slots`),
 		qm.WhereIn(`slots.taken_by in ?`, args...),
+		qmhelper.WhereIsNull(`slots.deleted_at`),
 	)
 	if mods != nil {
 		mods.Apply(query)
@@ -517,6 +713,68 @@ func (userL) LoadTakenBySlots(ctx context.Context, e boil.ContextExecutor, singu
 	return nil
 }
 
+// AddRfidLoansG adds the given related objects to the existing relationships
+// of the user, optionally inserting them as new records.
+// Appends related to o.R.RfidLoans.
+// Sets related.R.RfidUser appropriately.
+// Uses the global database handle.
+func (o *User) AddRfidLoansG(ctx context.Context, insert bool, related ...*Loan) error {
+	return o.AddRfidLoans(ctx, boil.GetContextDB(), insert, related...)
+}
+
+// AddRfidLoans adds the given related objects to the existing relationships
+// of the user, optionally inserting them as new records.
+// Appends related to o.R.RfidLoans.
+// Sets related.R.RfidUser appropriately.
+func (o *User) AddRfidLoans(ctx context.Context, exec boil.ContextExecutor, insert bool, related ...*Loan) error {
+	var err error
+	for _, rel := range related {
+		if insert {
+			rel.Rfid = o.ID
+			if err = rel.Insert(ctx, exec, boil.Infer()); err != nil {
+				return errors.Wrap(err, "failed to insert into foreign table")
+			}
+		} else {
+			updateQuery := fmt.Sprintf(
+				"UPDATE \"loans\" SET %s WHERE %s",
+				strmangle.SetParamNames("\"", "\"", 1, []string{"rfid"}),
+				strmangle.WhereClause("\"", "\"", 2, loanPrimaryKeyColumns),
+			)
+			values := []interface{}{o.ID, rel.ID}
+
+			if boil.IsDebug(ctx) {
+				writer := boil.DebugWriterFrom(ctx)
+				fmt.Fprintln(writer, updateQuery)
+				fmt.Fprintln(writer, values)
+			}
+			if _, err = exec.ExecContext(ctx, updateQuery, values...); err != nil {
+				return errors.Wrap(err, "failed to update foreign table")
+			}
+
+			rel.Rfid = o.ID
+		}
+	}
+
+	if o.R == nil {
+		o.R = &userR{
+			RfidLoans: related,
+		}
+	} else {
+		o.R.RfidLoans = append(o.R.RfidLoans, related...)
+	}
+
+	for _, rel := range related {
+		if rel.R == nil {
+			rel.R = &loanR{
+				RfidUser: o,
+			}
+		} else {
+			rel.R.RfidUser = o
+		}
+	}
+	return nil
+}
+
 // AddTakenBySlotsG adds the given related objects to the existing relationships
 // of the user, optionally inserting them as new records.
 // Appends related to o.R.TakenBySlots.
@@ -581,7 +839,7 @@ func (o *User) AddTakenBySlots(ctx context.Context, exec boil.ContextExecutor, i
 
 // Users retrieves all the records using an executor.
 func Users(mods ...qm.QueryMod) userQuery {
-	mods = append(mods, qm.From("\"users\""))
+	mods = append(mods, qm.From("\"users\""), qmhelper.WhereIsNull("\"users\".\"deleted_at\""))
 	q := NewQuery(mods...)
 	if len(queries.GetSelect(q)) == 0 {
 		queries.SetSelect(q, []string{"\"users\".*"})
@@ -605,7 +863,7 @@ func FindUser(ctx context.Context, exec boil.ContextExecutor, iD string, selectC
 		sel = strings.Join(strmangle.IdentQuoteSlice(dialect.LQ, dialect.RQ, selectCols), ",")
 	}
 	query := fmt.Sprintf(
-		"select %s from \"users\" where \"id\"=$1", sel,
+		"select %s from \"users\" where \"id\"=$1 and \"deleted_at\" is null", sel,
 	)
 
 	q := queries.Raw(query, iD)
@@ -638,6 +896,16 @@ func (o *User) Insert(ctx context.Context, exec boil.ContextExecutor, columns bo
 	}
 
 	var err error
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+		if o.UpdatedAt.IsZero() {
+			o.UpdatedAt = currTime
+		}
+	}
 
 	if err := o.doBeforeInsertHooks(ctx, exec); err != nil {
 		return err
@@ -719,6 +987,12 @@ func (o *User) UpdateG(ctx context.Context, columns boil.Columns) (int64, error)
 // See boil.Columns.UpdateColumnSet documentation to understand column list inference for updates.
 // Update does not automatically update the record in case of default values. Use .Reload() to refresh the records.
 func (o *User) Update(ctx context.Context, exec boil.ContextExecutor, columns boil.Columns) (int64, error) {
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		o.UpdatedAt = currTime
+	}
+
 	var err error
 	if err = o.doBeforeUpdateHooks(ctx, exec); err != nil {
 		return 0, err
@@ -864,6 +1138,14 @@ func (o *User) Upsert(ctx context.Context, exec boil.ContextExecutor, updateOnCo
 	if o == nil {
 		return errors.New("models: no users provided for upsert")
 	}
+	if !boil.TimestampsAreSkipped(ctx) {
+		currTime := time.Now().In(boil.GetLocation())
+
+		if o.CreatedAt.IsZero() {
+			o.CreatedAt = currTime
+		}
+		o.UpdatedAt = currTime
+	}
 
 	if err := o.doBeforeUpsertHooks(ctx, exec); err != nil {
 		return err
@@ -976,13 +1258,13 @@ func (o *User) Upsert(ctx context.Context, exec boil.ContextExecutor, updateOnCo
 
 // DeleteG deletes a single User record.
 // DeleteG will match against the primary key column to find the record to delete.
-func (o *User) DeleteG(ctx context.Context) (int64, error) {
-	return o.Delete(ctx, boil.GetContextDB())
+func (o *User) DeleteG(ctx context.Context, hardDelete bool) (int64, error) {
+	return o.Delete(ctx, boil.GetContextDB(), hardDelete)
 }
 
 // Delete deletes a single User record with an executor.
 // Delete will match against the primary key column to find the record to delete.
-func (o *User) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+func (o *User) Delete(ctx context.Context, exec boil.ContextExecutor, hardDelete bool) (int64, error) {
 	if o == nil {
 		return 0, errors.New("models: no User provided for delete")
 	}
@@ -991,8 +1273,26 @@ func (o *User) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, er
 		return 0, err
 	}
 
-	args := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), userPrimaryKeyMapping)
-	sql := "DELETE FROM \"users\" WHERE \"id\"=$1"
+	var (
+		sql  string
+		args []interface{}
+	)
+	if hardDelete {
+		args = queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), userPrimaryKeyMapping)
+		sql = "DELETE FROM \"users\" WHERE \"id\"=$1"
+	} else {
+		currTime := time.Now().In(boil.GetLocation())
+		o.DeletedAt = null.TimeFrom(currTime)
+		wl := []string{"deleted_at"}
+		sql = fmt.Sprintf("UPDATE \"users\" SET %s WHERE \"id\"=$2",
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+		)
+		valueMapping, err := queries.BindMapping(userType, userMapping, append(wl, userPrimaryKeyColumns...))
+		if err != nil {
+			return 0, err
+		}
+		args = queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(o)), valueMapping)
+	}
 
 	if boil.IsDebug(ctx) {
 		writer := boil.DebugWriterFrom(ctx)
@@ -1016,17 +1316,22 @@ func (o *User) Delete(ctx context.Context, exec boil.ContextExecutor) (int64, er
 	return rowsAff, nil
 }
 
-func (q userQuery) DeleteAllG(ctx context.Context) (int64, error) {
-	return q.DeleteAll(ctx, boil.GetContextDB())
+func (q userQuery) DeleteAllG(ctx context.Context, hardDelete bool) (int64, error) {
+	return q.DeleteAll(ctx, boil.GetContextDB(), hardDelete)
 }
 
 // DeleteAll deletes all matching rows.
-func (q userQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+func (q userQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor, hardDelete bool) (int64, error) {
 	if q.Query == nil {
 		return 0, errors.New("models: no userQuery provided for delete all")
 	}
 
-	queries.SetDelete(q.Query)
+	if hardDelete {
+		queries.SetDelete(q.Query)
+	} else {
+		currTime := time.Now().In(boil.GetLocation())
+		queries.SetUpdate(q.Query, M{"deleted_at": currTime})
+	}
 
 	result, err := q.Query.ExecContext(ctx, exec)
 	if err != nil {
@@ -1042,12 +1347,12 @@ func (q userQuery) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (in
 }
 
 // DeleteAllG deletes all rows in the slice.
-func (o UserSlice) DeleteAllG(ctx context.Context) (int64, error) {
-	return o.DeleteAll(ctx, boil.GetContextDB())
+func (o UserSlice) DeleteAllG(ctx context.Context, hardDelete bool) (int64, error) {
+	return o.DeleteAll(ctx, boil.GetContextDB(), hardDelete)
 }
 
 // DeleteAll deletes all rows in the slice, using an executor.
-func (o UserSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+func (o UserSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor, hardDelete bool) (int64, error) {
 	if len(o) == 0 {
 		return 0, nil
 	}
@@ -1060,15 +1365,32 @@ func (o UserSlice) DeleteAll(ctx context.Context, exec boil.ContextExecutor) (in
 		}
 	}
 
-	var args []interface{}
-	for _, obj := range o {
-		pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), userPrimaryKeyMapping)
-		args = append(args, pkeyArgs...)
+	var (
+		sql  string
+		args []interface{}
+	)
+	if hardDelete {
+		for _, obj := range o {
+			pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), userPrimaryKeyMapping)
+			args = append(args, pkeyArgs...)
+		}
+		sql = "DELETE FROM \"users\" WHERE " +
+			strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, userPrimaryKeyColumns, len(o))
+	} else {
+		currTime := time.Now().In(boil.GetLocation())
+		for _, obj := range o {
+			pkeyArgs := queries.ValuesFromMapping(reflect.Indirect(reflect.ValueOf(obj)), userPrimaryKeyMapping)
+			args = append(args, pkeyArgs...)
+			obj.DeletedAt = null.TimeFrom(currTime)
+		}
+		wl := []string{"deleted_at"}
+		sql = fmt.Sprintf("UPDATE \"users\" SET %s WHERE "+
+			strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 2, userPrimaryKeyColumns, len(o)),
+			strmangle.SetParamNames("\"", "\"", 1, wl),
+		)
+		args = append([]interface{}{currTime}, args...)
 	}
 
-	sql := "DELETE FROM \"users\" WHERE " +
-		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, userPrimaryKeyColumns, len(o))
-
 	if boil.IsDebug(ctx) {
 		writer := boil.DebugWriterFrom(ctx)
 		fmt.Fprintln(writer, sql)
@@ -1141,7 +1463,8 @@ func (o *UserSlice) ReloadAll(ctx context.Context, exec boil.ContextExecutor) er
 	}
 
 	sql := "SELECT \"users\".* FROM \"users\" WHERE " +
-		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, userPrimaryKeyColumns, len(*o))
+		strmangle.WhereClauseRepeated(string(dialect.LQ), string(dialect.RQ), 1, userPrimaryKeyColumns, len(*o)) +
+		"and \"deleted_at\" is null"
 
 	q := queries.Raw(sql, args...)
 
@@ -1163,7 +1486,7 @@ func UserExistsG(ctx context.Context, iD string) (bool, error) {
 // UserExists checks if the User row exists.
 func UserExists(ctx context.Context, exec boil.ContextExecutor, iD string) (bool, error) {
 	var exists bool
-	sql := "select exists(select 1 from \"users\" where \"id\"=$1 limit 1)"
+	sql := "select exists(select 1 from \"users\" where \"id\"=$1 and \"deleted_at\" is null limit 1)"
 
 	if boil.IsDebug(ctx) {
 		writer := boil.DebugWriterFrom(ctx)
@@ -0,0 +1,75 @@
+// Package occupancy samples the occupancy of every cabinet into the occupancy_samples table, so that the
+// utilization of the cabinets over time is graphed without scanning the events
+package occupancy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+
+	"letovo-computers-server/config"
+	"letovo-computers-server/models"
+)
+
+const countQuery = `SELECT cabinet_id, count(*) AS slots, count(*) FILTER (WHERE is_taken) AS taken
+FROM slots WHERE deleted_at IS NULL GROUP BY cabinet_id`
+
+type countRow struct {
+	CabinetID string `boil:"cabinet_id"`
+	Slots     int    `boil:"slots"`
+	Taken     int    `boil:"taken"`
+}
+
+// Run samples the occupancy every OCCUPANCY_SAMPLE_INTERVAL until ctx is done, a zero interval disabling it
+func Run(ctx context.Context) {
+	interval := config.Duration("OCCUPANCY_SAMPLE_INTERVAL", 5*time.Minute)
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := Sample(ctx, now.Truncate(interval)); err != nil {
+				log.Error().Err(err).Msg("failed to sample occupancy")
+			}
+		}
+	}
+}
+
+// Sample records the occupancy of every cabinet as of the time. The instances sharing the db sample at the same
+// times, truncated to the interval, so the samples already recorded by another one are left as they are
+func Sample(ctx context.Context, at time.Time) error {
+	var counts []countRow
+	if err := queries.Raw(countQuery).BindG(ctx, &counts); err != nil {
+		return fmt.Errorf("failed to count occupied slots: %w", err)
+	}
+
+	tx, err := boil.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	conflict := []string{models.OccupancySampleColumns.CabinetID, models.OccupancySampleColumns.SampledAt}
+	for _, count := range counts {
+		sample := models.OccupancySample{SampledAt: at, CabinetID: count.CabinetID, Slots: count.Slots, Taken: count.Taken}
+
+		if err := sample.Upsert(ctx, tx, false, conflict, boil.None(), boil.Infer()); err != nil {
+			return fmt.Errorf("failed to record occupancy of cabinet %s: %w", count.CabinetID, err)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,247 @@
+// Package outbox publishes the messages queued by the transactions storing the changes they announce, like the
+// acks of the stored messages and the corrections of the released slots. A message is queued in the transaction
+// of its change and published once it commits, so that a crash in between neither loses it nor publishes it for
+// a change rolled back. The messages are published in the order they were queued, at least once
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/config"
+	"letovo-computers-server/metrics"
+	"letovo-computers-server/models"
+	"letovo-computers-server/storage"
+)
+
+// errTimeout is recorded for the messages the broker did not take in OUTBOX_PUBLISH_TIMEOUT
+var errTimeout = errors.New("timed out publishing")
+
+// wake has the dispatcher look for the messages queued by the transactions committed since it last looked
+var wake = make(chan struct{}, 1)
+
+// Message is the message to the topic with the delivery, to be queued through the storage.OutboxRepo, or nil
+// when the topic is not configured
+func Message(topic string, delivery broker.Delivery, payload []byte, props broker.Properties) *models.OutboxMessage {
+	if topic == "" {
+		return nil
+	}
+
+	return &models.OutboxMessage{
+		Topic:           topic,
+		Qos:             int16(delivery.QoS),
+		Retained:        delivery.Retained,
+		Payload:         payload,
+		ContentType:     props.ContentType,
+		CorrelationData: props.CorrelationData,
+	}
+}
+
+// Wake has the messages queued by a committed transaction published right away, rather than once the dispatcher
+// polls for them
+func Wake() {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run publishes the queued messages as they are committed until ctx is done, polling for them every
+// OUTBOX_POLL_INTERVAL in case the transaction queuing them did not wake it, like one of another instance. The
+// messages failing OUTBOX_MAX_ATTEMPTS times are given up on, so that they do not hold up the ones after them.
+// The messages sent or given up on more than OUTBOX_RETENTION ago are pruned. The instances sharing a db take
+// turns dispatching, so that the messages are published in order by a single instance at a time
+func Run(ctx context.Context, db *sql.DB, driver storage.Driver, client broker.Broker) {
+	poll := time.NewTicker(config.Duration("OUTBOX_POLL_INTERVAL", time.Second))
+	defer poll.Stop()
+
+	pruning := time.NewTicker(time.Minute)
+	defer pruning.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+		case <-poll.C:
+		case <-pruning.C:
+			if err := prune(ctx, db); err != nil {
+				log.Error().Err(err).Msg("failed to prune outbox messages")
+			}
+			continue
+		}
+
+		// a full batch leaves more messages behind
+		batch := config.Int("OUTBOX_BATCH_SIZE", 100)
+		for {
+			published, err := dispatch(ctx, db, driver, client, batch)
+			if err != nil {
+				log.Error().Err(err).Msg("failed to dispatch outbox messages")
+			}
+			if err != nil || published < batch {
+				break
+			}
+		}
+	}
+}
+
+// dispatch publishes up to batch of the queued messages in order, returning how many it published. The instances
+// sharing a postgres db hold an advisory lock while they dispatch, the one failing to take it leaving the messages
+// to the instance holding it. The lock is held by a transaction of its own, so that no row is kept locked while the
+// broker takes the messages. A message failing to be published releases its claim along with the ones after it,
+// to be retried on the next poll
+func dispatch(ctx context.Context, db *sql.DB, driver storage.Driver, client broker.Broker, batch int) (int, error) {
+	if driver == storage.Postgres {
+		lock, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return 0, fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer func() {
+			// releases the lock, along with the connection holding it if it is lost
+			_ = lock.Rollback()
+		}()
+
+		locked, err := storage.TryLock(ctx, lock, driver, "outbox")
+		if err != nil || !locked {
+			return 0, err
+		}
+	}
+
+	messages, err := claim(ctx, db, batch)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for i, message := range messages {
+		failure := publish(client, message)
+		if failure != nil {
+			message.Attempts++
+			message.LastError = failure.Error()
+		} else {
+			message.SentAt = null.TimeFrom(time.Now())
+		}
+		message.ClaimedUntil = null.Time{}
+
+		// the messages left claimed are taken over once their claim expires
+		columns := boil.Whitelist(models.OutboxMessageColumns.Attempts, models.OutboxMessageColumns.LastError,
+			models.OutboxMessageColumns.SentAt, models.OutboxMessageColumns.ClaimedUntil)
+		if _, err := message.Update(ctx, db, columns); err != nil {
+			return published, fmt.Errorf("failed to mark outbox message %d: %w", message.ID, err)
+		}
+
+		if failure != nil && message.Attempts >= config.Int("OUTBOX_MAX_ATTEMPTS", 10) {
+			log.Error().Err(failure).Str("topic", message.Topic).Int("attempts", message.Attempts).
+				Msgf("gave up on outbox message %d", message.ID)
+			metrics.OutboxMessages.WithLabelValues("abandoned").Inc()
+			continue
+		}
+		if failure != nil {
+			log.Warn().Err(failure).Str("topic", message.Topic).Int("attempts", message.Attempts).
+				Msg("failed to publish outbox message")
+			metrics.OutboxMessages.WithLabelValues("failed").Inc()
+
+			if err := release(ctx, db, messages[i+1:]); err != nil {
+				return published, err
+			}
+			break
+		}
+
+		metrics.OutboxMessages.WithLabelValues("published").Inc()
+		published++
+	}
+
+	return published, nil
+}
+
+// claim claims up to batch of the queued messages in order for OUTBOX_CLAIM_TTL. The claims are only left behind
+// by a dispatcher which went away while publishing, like an instance which crashed, so the messages from the first
+// one still claimed on are left until its claim expires, rather than published ahead of it
+func claim(ctx context.Context, db *sql.DB, batch int) (models.OutboxMessageSlice, error) {
+	now := time.Now()
+	messages, err := models.OutboxMessages(
+		models.OutboxMessageWhere.SentAt.IsNull(),
+		models.OutboxMessageWhere.Attempts.LT(config.Int("OUTBOX_MAX_ATTEMPTS", 10)),
+		qm.OrderBy(models.OutboxMessageColumns.ID),
+		qm.Limit(batch),
+	).All(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch outbox messages: %w", err)
+	}
+
+	for i, message := range messages {
+		if message.ClaimedUntil.Valid && message.ClaimedUntil.Time.After(now) {
+			messages = messages[:i]
+			break
+		}
+	}
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	until := null.TimeFrom(now.Add(config.Duration("OUTBOX_CLAIM_TTL", time.Minute)))
+	if _, err := messages.UpdateAll(ctx, db, models.M{models.OutboxMessageColumns.ClaimedUntil: until}); err != nil {
+		return nil, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+
+	for _, message := range messages {
+		message.ClaimedUntil = until
+	}
+
+	return messages, nil
+}
+
+// release gives up the claims of the messages, so that they are published again on the next poll
+func release(ctx context.Context, db *sql.DB, messages models.OutboxMessageSlice) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	_, err := messages.UpdateAll(ctx, db, models.M{models.OutboxMessageColumns.ClaimedUntil: null.Time{}})
+	if err != nil {
+		return fmt.Errorf("failed to release outbox messages: %w", err)
+	}
+
+	return nil
+}
+
+// prune deletes the messages sent or given up on more than OUTBOX_RETENTION ago
+func prune(ctx context.Context, db *sql.DB) error {
+	cutoff := time.Now().Add(-config.Duration("OUTBOX_RETENTION", time.Hour))
+
+	_, err := models.OutboxMessages(
+		models.OutboxMessageWhere.SentAt.LT(null.TimeFrom(cutoff)),
+		qm.Or2(qm.Expr(
+			models.OutboxMessageWhere.SentAt.IsNull(),
+			models.OutboxMessageWhere.Attempts.GTE(config.Int("OUTBOX_MAX_ATTEMPTS", 10)),
+			models.OutboxMessageWhere.CreatedAt.LT(cutoff),
+		)),
+	).DeleteAll(ctx, db)
+
+	return err
+}
+
+// publish publishes the message, waiting up to OUTBOX_PUBLISH_TIMEOUT for the broker to take it
+func publish(client broker.Broker, message *models.OutboxMessage) error {
+	props := broker.Properties{ContentType: message.ContentType}
+	if len(message.CorrelationData) > 0 {
+		props.CorrelationData = message.CorrelationData
+	}
+
+	t := broker.PublishWithProperties(client, message.Topic, byte(message.Qos), message.Retained, message.Payload, props)
+
+	if !t.WaitTimeout(config.Duration("OUTBOX_PUBLISH_TIMEOUT", 10*time.Second)) {
+		return errTimeout
+	}
+
+	return t.Error()
+}
@@ -0,0 +1,93 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/broker/brokertest"
+	"letovo-computers-server/models"
+	"letovo-computers-server/storage"
+	"letovo-computers-server/storage/storagetest"
+)
+
+func TestDispatchReleasesAfterFailure(t *testing.T) {
+	db := storagetest.New(t)
+	ctx := context.Background()
+
+	for _, topic := range []string{"server/ack/1", "server/ack/2", "server/ack/3"} {
+		message := Message(topic, broker.Delivery{QoS: 1}, []byte("ok"), broker.Properties{})
+		if err := message.Insert(ctx, db, boil.Infer()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := brokertest.New()
+	client.Fail("server/ack/2", errors.New("broker went away"))
+
+	published, err := dispatch(ctx, db, storage.SQLite, client, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if published != 1 {
+		t.Errorf("published %d messages, want 1", published)
+	}
+
+	messages, err := models.OutboxMessages(qm.OrderBy(models.OutboxMessageColumns.ID)).All(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !messages[0].SentAt.Valid {
+		t.Error("the published message is not marked sent")
+	}
+	if messages[1].SentAt.Valid || messages[1].Attempts != 1 {
+		t.Errorf("the failed message is sent %v after %d attempts, want unsent after 1", messages[1].SentAt.Valid,
+			messages[1].Attempts)
+	}
+
+	// the failed message and the ones after it are released rather than left to their claim expiring
+	for _, message := range messages {
+		if message.ClaimedUntil.Valid {
+			t.Errorf("message %d to %s is still claimed", message.ID, message.Topic)
+		}
+	}
+
+	client.Fail("server/ack/2", nil)
+	if published, err := dispatch(ctx, db, storage.SQLite, client, 10); err != nil || published != 2 {
+		t.Errorf("dispatch() = %d, %v once the broker is back, want the 2 left", published, err)
+	}
+}
+
+func TestDispatchStopsAtClaimed(t *testing.T) {
+	db := storagetest.New(t)
+	ctx := context.Background()
+
+	// a dispatcher which went away claimed the first message, whose claim expires, and then the second one, queued
+	// before the third one nobody claimed
+	claims := []time.Duration{-time.Second, time.Minute, 0}
+	for i, claim := range claims {
+		message := Message("server/ack", broker.Delivery{QoS: 1}, []byte{byte(i)}, broker.Properties{})
+		if claim != 0 {
+			message.ClaimedUntil = null.TimeFrom(time.Now().Add(claim))
+		}
+		if err := message.Insert(ctx, db, boil.Infer()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := brokertest.New()
+	published, err := dispatch(ctx, db, storage.SQLite, client, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sent := client.Published("server/ack"); published != 1 || len(sent) != 1 || sent[0].Payload()[0] != 0 {
+		t.Errorf("published %d messages, want only the one before the claimed one", len(sent))
+	}
+}
@@ -0,0 +1,25 @@
+package payloads
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// IsBatch reports whether the JSON payload is an array of events rather than a single one, like the devices
+// publish to catch up after being offline
+func IsBatch(payload []byte) bool {
+	trimmed := bytes.TrimLeft(payload, " \t\r\n")
+
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// SplitBatch splits the JSON array into its events, in the order the device sent them
+func SplitBatch(payload []byte) ([]json.RawMessage, error) {
+	var events []json.RawMessage
+	if err := json.Unmarshal(payload, &events); err != nil {
+		return nil, fmt.Errorf("failed to decode batch: %w", err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,169 @@
+package payloads
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/fxamacker/cbor/v2"
+	"google.golang.org/protobuf/proto"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/config"
+	"letovo-computers-server/devicepb"
+	"letovo-computers-server/types"
+)
+
+// Encoding is how the payload of a message is encoded
+type Encoding int
+
+const (
+	// JSON is the encoding of the payloads unless the device says otherwise
+	JSON Encoding = iota
+	// Protobuf is the compact encoding of the device payloads defined in devicepb
+	Protobuf
+	// CBOR is the binary counterpart of JSON, which the Arduino SDK produces much more cheaply than JSON strings
+	CBOR
+)
+
+// The content types MQTT 5 devices mark their binary payloads with
+const (
+	ProtobufContentType = "application/x-protobuf"
+	CBORContentType     = "application/cbor"
+)
+
+// ProtobufSuffix is the topic suffix MQTT 3.1.1 devices publish their protobuf payloads under, like
+// arduino/1/stream/pb, so the subscription must cover it, like arduino/+/stream/#
+func ProtobufSuffix() string {
+	return config.String("PROTOBUF_TOPIC_SUFFIX", "/pb")
+}
+
+// CBORSuffix is the topic suffix of the CBOR payloads, like ProtobufSuffix
+func CBORSuffix() string {
+	return config.String("CBOR_TOPIC_SUFFIX", "/cbor")
+}
+
+// EncodingOf tells how the payload of the message is encoded, by its content type or its topic suffix.
+// Failing both, payloads starting with a CBOR map or array header are taken for CBOR, as neither JSON nor the device
+// protobuf messages can start with one
+func EncodingOf(msg mqtt.Message) Encoding {
+	if props, ok := broker.MessageProperties(msg); ok && props.ContentType != "" {
+		switch props.ContentType {
+		case ProtobufContentType, "application/protobuf":
+			return Protobuf
+		case CBORContentType:
+			return CBOR
+		default:
+			return JSON
+		}
+	}
+
+	switch TopicSuffix(msg.Topic()) {
+	case "":
+	case ProtobufSuffix():
+		return Protobuf
+	default:
+		return CBOR
+	}
+
+	// major types 4 and 5, arrays and maps of any length, the batches and the events
+	if payload := msg.Payload(); len(payload) > 0 && (payload[0]>>5 == 4 || payload[0]>>5 == 5) {
+		return CBOR
+	}
+
+	return JSON
+}
+
+// TopicSuffix returns the encoding suffix the topic ends with, if any
+func TopicSuffix(topic string) string {
+	for _, suffix := range []string{ProtobufSuffix(), CBORSuffix()} {
+		if suffix != "" && strings.HasSuffix(topic, suffix) {
+			return suffix
+		}
+	}
+
+	return ""
+}
+
+// TrimEncoding returns the topic without its encoding suffix, the topic JSON payloads are published to
+func TrimEncoding(topic string) string {
+	return strings.TrimSuffix(topic, TopicSuffix(topic))
+}
+
+// ToJSON decodes the binary stream payload into its JSON counterpart, so that all the encodings go through the same
+// validation and handling
+func ToJSON(encoding Encoding, payload []byte) ([]byte, error) {
+	switch encoding {
+	case Protobuf:
+		return protobufJSON(payload)
+	case CBOR:
+		var doc interface{}
+		if err := cborDecoder.Unmarshal(payload, &doc); err != nil {
+			return nil, fmt.Errorf("failed to decode CBOR payload: %w", err)
+		}
+
+		return json.Marshal(doc)
+	default:
+		return payload, nil
+	}
+}
+
+// cborDecoder decodes CBOR maps with string keys only, the ones JSON objects can represent
+var cborDecoder, _ = cbor.DecOptions{DefaultMapType: reflect.TypeOf(map[string]interface{}(nil))}.DecMode()
+
+func protobufJSON(payload []byte) ([]byte, error) {
+	message := new(devicepb.StreamMessage)
+	if err := proto.Unmarshal(payload, message); err != nil {
+		return nil, fmt.Errorf("failed to decode protobuf payload: %w", err)
+	}
+
+	var version *uint32
+	if message.SchemaVersion != 0 {
+		version = &message.SchemaVersion
+	}
+
+	return json.Marshal(struct {
+		types.MQTTMessage
+		SchemaVersion *uint32 `json:"schema_version,omitempty"`
+	}{
+		MQTTMessage: types.MQTTMessage{
+			ID:      message.Id,
+			Message: message.Message,
+			Device:  message.Device,
+			RFID:    message.Rfid,
+			Login:   message.Login,
+			Slots:   message.Slots,
+			Status:  types.Status(message.Status),
+			Seq:     message.Seq,
+			SentAt:  message.SentAt,
+			Laptop:  message.Laptop,
+		},
+		SchemaVersion: version,
+	})
+}
+
+// MarshalAck encodes the ack the way the device encoded its message, along with the content type for MQTT 5
+func MarshalAck(encoding Encoding, ack types.Ack) ([]byte, string, error) {
+	switch encoding {
+	case Protobuf:
+		payload, err := proto.Marshal(&devicepb.Ack{
+			Id:     ack.ID,
+			Rfid:   ack.RFID,
+			Status: devicepb.Status(ack.Status),
+			Ok:     ack.OK,
+			Result: ack.Result,
+		})
+
+		return payload, ProtobufContentType, err
+	case CBOR:
+		payload, err := cbor.Marshal(ack)
+
+		return payload, CBORContentType, err
+	default:
+		payload, err := json.Marshal(ack)
+
+		return payload, "application/json", err
+	}
+}
@@ -0,0 +1,207 @@
+package payloads
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+
+	"letovo-computers-server/deadletter"
+	"letovo-computers-server/metrics"
+)
+
+//go:embed schemas/*.json
+var embedded embed.FS
+
+// schemaNames are the schemas the payloads of the routes are validated against, keyed by the route setting
+var schemaNames = map[string]string{
+	"ARDUINO_STREAM_TOPIC":           "stream",
+	"ARDUINO_FIRMWARE_REQUEST_TOPIC": "firmware_request",
+	"ARDUINO_REPLY_TOPIC":            "command_reply",
+}
+
+// VersionField names the version of the schema a payload follows. Payloads of the firmware predating it
+// follow the version 1
+const VersionField = "schema_version"
+
+// schemaFile matches the file names of the schemas, like stream.v2.json
+var schemaFile = regexp.MustCompile(`^(.+)\.v(\d+)\.json$`)
+
+// ErrUnknownVersion is returned for payloads following a version of the schema the server does not know
+var ErrUnknownVersion = errors.New("unknown schema version")
+
+// Violation is a part of a payload breaking its schema
+type Violation struct {
+	// Location is the JSON pointer to the offending value, empty for the payload as a whole
+	Location string `json:"location"`
+	Reason   string `json:"reason"`
+}
+
+// Error lists every violation of the schema found in a payload
+type Error struct {
+	Schema     string
+	Version    int
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	reasons := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		location := v.Location
+		if location == "" {
+			location = "/"
+		}
+
+		reasons = append(reasons, location+": "+v.Reason)
+	}
+
+	return fmt.Sprintf("payload violates %s schema v%d: %s", e.Schema, e.Version, strings.Join(reasons, "; "))
+}
+
+// Validator validates the payloads of the routes against the versions of their JSON Schemas
+type Validator struct {
+	schemas map[string]map[int]*jsonschema.Schema
+}
+
+// Load compiles the schemas embedded in the server, or the ones in the dir if it is set, so that the schemas
+// of new firmware versions can be rolled out without a new build. A nil Validator accepts every payload
+func Load(dir string) (*Validator, error) {
+	fsys, err := fs.Sub(embedded, "schemas")
+	if err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		fsys = os.DirFS(dir)
+	}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+
+	v := &Validator{schemas: make(map[string]map[int]*jsonschema.Schema)}
+	for _, entry := range entries {
+		match := schemaFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema %s: %w", entry.Name(), err)
+		}
+
+		if err := compiler.AddResource(entry.Name(), bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("failed to load schema %s: %w", entry.Name(), err)
+		}
+
+		schema, err := compiler.Compile(entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile schema %s: %w", entry.Name(), err)
+		}
+
+		version, _ := strconv.Atoi(match[2])
+		if v.schemas[match[1]] == nil {
+			v.schemas[match[1]] = make(map[int]*jsonschema.Schema)
+		}
+		v.schemas[match[1]][version] = schema
+	}
+
+	return v, nil
+}
+
+// Validate checks the JSON payload received by the route against the version of the schema it names, returning
+// an *Error listing the violations. Payloads of routes without a schema are accepted as they are
+func (v *Validator) Validate(route string, payload []byte) error {
+	if v == nil {
+		return nil
+	}
+
+	name, ok := schemaNames[route]
+	if !ok {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return fmt.Errorf("failed to decode payload: %w", err)
+	}
+
+	version := 1
+	if object, ok := doc.(map[string]interface{}); ok {
+		if n, ok := object[VersionField].(json.Number); ok {
+			parsed, err := strconv.Atoi(n.String())
+			if err != nil {
+				return fmt.Errorf("%w %s of %s", ErrUnknownVersion, n, name)
+			}
+
+			version = parsed
+		}
+	}
+
+	schema, ok := v.schemas[name][version]
+	if !ok {
+		return fmt.Errorf("%w %d of %s", ErrUnknownVersion, version, name)
+	}
+
+	err := schema.Validate(doc)
+
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+
+	return &Error{Schema: name, Version: version, Violations: violations(verr)}
+}
+
+// violations flattens the validation error into the failures of the individual keywords
+func violations(verr *jsonschema.ValidationError) []Violation {
+	if len(verr.Causes) == 0 {
+		return []Violation{{Location: verr.InstanceLocation, Reason: verr.Message}}
+	}
+
+	var found []Violation
+	for _, cause := range verr.Causes {
+		found = append(found, violations(cause)...)
+	}
+
+	return found
+}
+
+// Middleware drops the messages of the route whose payload breaks its schema, dead-lettering them
+// instead of passing them to the handler. They are acknowledged, as a redelivery would break it all the same
+func (v *Validator) Middleware(route string, next mqtt.MessageHandler) mqtt.MessageHandler {
+	return func(client mqtt.Client, msg mqtt.Message) {
+		err := v.Validate(route, msg.Payload())
+		if err == nil {
+			next(client, msg)
+			return
+		}
+
+		log.Warn().Err(err).Str("topic", msg.Topic()).Msg("rejected message violating its schema")
+		metrics.ObserveParseFailure(route)
+
+		if err := deadletter.Send(context.Background(), client, msg, deadletter.StageValidate, err); err != nil {
+			log.Error().Err(err).Str("topic", msg.Topic()).Msg("failed to dead-letter message")
+		}
+
+		msg.Ack()
+	}
+}
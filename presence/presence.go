@@ -0,0 +1,263 @@
+// Package presence tracks whether the devices are connected, from their birth messages, heartbeats and wills, and
+// keeps every connection of theirs as a session in device_sessions, which their uptime is computed from
+package presence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/config"
+	"letovo-computers-server/metrics"
+	"letovo-computers-server/models"
+)
+
+// the causes of the sessions starting and ending, a birth also ending the session left open by a device reconnecting
+// before its will was sent
+const (
+	CauseBirth     = "birth"
+	CauseHeartbeat = "heartbeat"
+	CauseWill      = "will"
+	CauseTimeout   = "timeout"
+)
+
+// Online records that the device connected, which it announces with a birth message, along with the version
+// of its firmware if it reports it. The firmware numbers its messages anew once it boots, so the last sequence
+// number is reset
+func Online(ctx context.Context, device, firmware string, at time.Time) error {
+	tx, err := boil.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = upsert(ctx, tx, &models.Device{
+		ID:              device,
+		Online:          true,
+		LastSeen:        null.TimeFrom(at),
+		ConnectedAt:     null.TimeFrom(at),
+		FirmwareVersion: firmware,
+	}, firmware,
+		models.DeviceColumns.Online, models.DeviceColumns.LastSeen, models.DeviceColumns.ConnectedAt,
+		models.DeviceColumns.LastSeq,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := end(ctx, tx, device, at, CauseBirth); err != nil {
+		return err
+	}
+
+	if err := start(ctx, tx, device, at, CauseBirth); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Heartbeat records that the device is still connected. A heartbeat from a device believed offline brings it back
+// online, as its birth message may have been lost while the server was down
+func Heartbeat(ctx context.Context, device, firmware string, at time.Time) error {
+	err := upsert(ctx, boil.GetContextDB(), &models.Device{
+		ID:              device,
+		Online:          true,
+		LastSeen:        null.TimeFrom(at),
+		FirmwareVersion: firmware,
+	}, firmware, models.DeviceColumns.Online, models.DeviceColumns.LastSeen)
+	if err != nil {
+		return err
+	}
+
+	return start(ctx, boil.GetContextDB(), device, at, CauseHeartbeat)
+}
+
+// Offline records that the device disconnected, which the broker announces with its will. It runs on exec,
+// so that the disconnection can be logged to the events table in the same transaction
+func Offline(ctx context.Context, exec boil.ContextExecutor, device string, at time.Time) error {
+	err := upsert(ctx, exec, &models.Device{
+		ID:             device,
+		DisconnectedAt: null.TimeFrom(at),
+	}, "", models.DeviceColumns.Online, models.DeviceColumns.DisconnectedAt)
+	if err != nil {
+		return err
+	}
+
+	return end(ctx, exec, device, at, CauseWill)
+}
+
+// start starts a session of the device at the time, unless it has one open already
+func start(ctx context.Context, exec boil.ContextExecutor, device string, at time.Time, cause string) error {
+	_, err := queries.Raw(`INSERT INTO device_sessions (device, connected_at, connected_by) VALUES ($1, $2, $3)
+ON CONFLICT (device) WHERE disconnected_at IS NULL DO NOTHING`, device, at, cause).ExecContext(ctx, exec)
+	if err != nil {
+		return fmt.Errorf("failed to start session of %s: %w", device, err)
+	}
+
+	return nil
+}
+
+// end ends the open session of the device at the time, if any
+func end(ctx context.Context, exec boil.ContextExecutor, device string, at time.Time, cause string) error {
+	_, err := models.DeviceSessions(
+		models.DeviceSessionWhere.Device.EQ(device),
+		models.DeviceSessionWhere.DisconnectedAt.IsNull(),
+	).UpdateAll(ctx, exec, models.M{
+		models.DeviceSessionColumns.DisconnectedAt: at,
+		models.DeviceSessionColumns.DisconnectedBy: cause,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to end session of %s: %w", device, err)
+	}
+
+	return nil
+}
+
+// upsert inserts the device or updates the columns of the known one, keeping its firmware version unless reported
+func upsert(ctx context.Context, exec boil.ContextExecutor, device *models.Device, firmware string, columns ...string) error {
+	if firmware != "" {
+		columns = append(columns, models.DeviceColumns.FirmwareVersion)
+	}
+
+	err := device.Upsert(ctx, exec, true, []string{models.DeviceColumns.ID}, boil.Whitelist(columns...), boil.Infer())
+	if err != nil {
+		return fmt.Errorf("failed to update presence of %s: %w", device.ID, err)
+	}
+
+	return nil
+}
+
+// Report is the payload of a presence message in its JSON form
+type Report struct {
+	Device   string `json:"device"`
+	Firmware string `json:"firmware"`
+}
+
+// Parse returns the report of a presence message on the topic named by the setting. The payload is either
+// the name of the device or a JSON Report, devices publishing to a wildcard topic being named after the cabinet
+// in their topic when it is empty
+func Parse(setting string, msg mqtt.Message) Report {
+	payload := strings.TrimSpace(string(msg.Payload()))
+
+	var report Report
+	if strings.HasPrefix(payload, "{") {
+		if err := json.Unmarshal([]byte(payload), &report); err != nil {
+			log.Warn().Err(err).Str("topic", msg.Topic()).Msg("failed to unmarshal presence message")
+			metrics.ObserveParseFailure(setting)
+		}
+	} else {
+		report.Device = payload
+	}
+
+	if report.Device == "" {
+		report.Device = broker.CabinetID(config.String(setting, ""), msg.Topic())
+	}
+
+	return report
+}
+
+// HandleBirth is the callback of the ARDUINO_BIRTH_TOPIC, marking the devices online as they connect
+func HandleBirth(ctx context.Context) mqtt.MessageHandler {
+	return func(_ mqtt.Client, resp mqtt.Message) {
+		report := Parse("ARDUINO_BIRTH_TOPIC", resp)
+		if report.Device == "" {
+			log.Warn().Str("topic", resp.Topic()).Msg("received birth message of an unnamed device")
+			return
+		}
+
+		log.Info().Str("firmware", report.Firmware).Msgf("arduino %s is online", report.Device)
+
+		if err := Online(ctx, report.Device, report.Firmware, time.Now()); err != nil {
+			log.Error().Err(err).Msg("failed to record birth")
+		}
+	}
+}
+
+// HandleHeartbeat is the callback of the ARDUINO_HEARTBEAT_TOPIC, keeping the last time the devices were seen
+func HandleHeartbeat(ctx context.Context) mqtt.MessageHandler {
+	return func(_ mqtt.Client, resp mqtt.Message) {
+		report := Parse("ARDUINO_HEARTBEAT_TOPIC", resp)
+		if report.Device == "" {
+			log.Warn().Str("topic", resp.Topic()).Msg("received heartbeat of an unnamed device")
+			return
+		}
+
+		if err := Heartbeat(ctx, report.Device, report.Firmware, time.Now()); err != nil {
+			log.Error().Err(err).Msg("failed to record heartbeat")
+		}
+	}
+}
+
+// Monitor marks the online devices whose last heartbeat is older than HEARTBEAT_TIMEOUT offline, checking every
+// HEARTBEAT_CHECK_INTERVAL until ctx is done, ending their sessions as of the time they were last seen. The stale
+// callback is called for every device marked offline, with the time it was last seen. Devices without a heartbeat
+// yet are left alone, as they may not send any
+func Monitor(ctx context.Context, stale func(device string, lastSeen time.Time)) {
+	ticker := time.NewTicker(config.Duration("HEARTBEAT_CHECK_INTERVAL", 30*time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			timeout := config.Duration("HEARTBEAT_TIMEOUT", 0)
+			if timeout <= 0 {
+				continue
+			}
+
+			if err := expire(ctx, time.Now().Add(-timeout), stale); err != nil {
+				log.Error().Err(err).Msg("failed to check heartbeats")
+			}
+		}
+	}
+}
+
+// expire marks the online devices last seen before the cutoff offline
+func expire(ctx context.Context, cutoff time.Time, stale func(device string, lastSeen time.Time)) error {
+	devices, err := models.Devices(
+		models.DeviceWhere.Online.EQ(true),
+		models.DeviceWhere.LastSeen.LT(null.TimeFrom(cutoff)),
+	).AllG(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch stale devices: %w", err)
+	}
+
+	for _, device := range devices {
+		// a heartbeat may have arrived meanwhile, or another instance may have got there first
+		n, err := models.Devices(
+			models.DeviceWhere.ID.EQ(device.ID),
+			models.DeviceWhere.Online.EQ(true),
+			models.DeviceWhere.LastSeen.LT(null.TimeFrom(cutoff)),
+		).UpdateAllG(ctx, models.M{
+			models.DeviceColumns.Online:         false,
+			models.DeviceColumns.DisconnectedAt: time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to mark %s offline: %w", device.ID, err)
+		}
+
+		if n == 0 {
+			continue
+		}
+
+		if err := end(ctx, boil.GetContextDB(), device.ID, device.LastSeen.Time, CauseTimeout); err != nil {
+			return err
+		}
+
+		stale(device.ID, device.LastSeen.Time)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package provisioning
+
+import (
+	"bytes"
+	"context"
+
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"github.com/rs/zerolog/log"
+)
+
+// Hook has the embedded broker let in the devices connecting with their issued credentials. What they may
+// publish and subscribe to is still up to the auth ledger, which matches the usernames, the device IDs
+type Hook struct {
+	mochi.HookBase
+}
+
+// ID returns the ID of the hook
+func (h *Hook) ID() string {
+	return "device-credentials"
+}
+
+// Provides indicates which hook methods the hook provides
+func (h *Hook) Provides(b byte) bool {
+	return bytes.Contains([]byte{mochi.OnConnectAuthenticate}, []byte{b})
+}
+
+// OnConnectAuthenticate lets the client in if it connects with valid credentials, leaving the others to the ledger
+func (h *Hook) OnConnectAuthenticate(cl *mochi.Client, pk packets.Packet) bool {
+	if !Enabled() || len(pk.Connect.Username) == 0 {
+		return false
+	}
+
+	ok, err := Authenticate(context.Background(), string(pk.Connect.Username), string(pk.Connect.Password))
+	if err != nil {
+		log.Error().Err(err).Str("client", cl.ID).Msg("failed to authenticate device")
+	}
+
+	return ok
+}
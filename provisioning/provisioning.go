@@ -0,0 +1,334 @@
+// Package provisioning issues the devices their own MQTT credentials and HMAC secrets. A new device enrolls
+// by publishing a one-time token to the DEVICE_ENROLL_TOPIC, and is sent its credentials in return
+package provisioning
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"golang.org/x/crypto/bcrypt"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/config"
+	"letovo-computers-server/metrics"
+	"letovo-computers-server/models"
+)
+
+var (
+	// ErrInvalidToken is returned for enrollment tokens which are unknown, used, expired or bound to another device
+	ErrInvalidToken = errors.New("invalid enrollment token")
+	// ErrNotEnrolled is returned when rotating the credentials of a device which was never issued any
+	ErrNotEnrolled = errors.New("device is not enrolled")
+)
+
+// Request is published by a new device to the DEVICE_ENROLL_TOPIC, naming the device the token was created for
+type Request struct {
+	Device string `json:"device"`
+	Token  string `json:"token"`
+}
+
+// Credentials are issued to the device, published to DEVICE_CREDENTIALS_TOPIC/<device> or to the response topic
+// of the request. The device connects with the username and password and signs its payloads with the secret.
+// Failed enrollments carry the error only
+type Credentials struct {
+	Device   string `json:"device"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Secret   string `json:"secret,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Enabled reports whether the devices may enroll, that is the DEVICE_ENROLL_TOPIC is set
+func Enabled() bool {
+	return config.String("DEVICE_ENROLL_TOPIC", "") != ""
+}
+
+// NewToken creates the one-time token the device enrolls with, valid for ENROLLMENT_TOKEN_TTL. Only its hash
+// is stored, so the token is to be handed over to the device right away
+func NewToken(ctx context.Context, device string) (string, time.Time, error) {
+	token, err := random(32)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	row := models.EnrollmentToken{
+		TokenHash: hash(token),
+		Device:    device,
+		ExpiresAt: time.Now().Add(config.Duration("ENROLLMENT_TOKEN_TTL", 24*time.Hour)),
+	}
+	if err := row.InsertG(ctx, boil.Infer()); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to store enrollment token: %w", err)
+	}
+
+	return token, row.ExpiresAt, nil
+}
+
+// Enroll spends the token, issuing the device its credentials. Credentials issued to the device before, like ones
+// lost to a factory reset, expire after the grace period of a rotation
+func Enroll(ctx context.Context, device, token string) (Credentials, error) {
+	tx, err := boil.BeginTx(ctx, nil)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	credentials, err := enroll(ctx, tx, device, token)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			log.Error().Err(err).Msg("failed to rollback transaction")
+		}
+
+		return Credentials{}, err
+	}
+
+	return credentials, tx.Commit()
+}
+
+func enroll(ctx context.Context, tx boil.ContextExecutor, device, token string) (Credentials, error) {
+	row, err := models.EnrollmentTokens(
+		models.EnrollmentTokenWhere.TokenHash.EQ(hash(token)),
+		qm.For("UPDATE"),
+	).One(ctx, tx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Credentials{}, ErrInvalidToken
+	}
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to fetch enrollment token: %w", err)
+	}
+
+	if row.UsedAt.Valid || time.Now().After(row.ExpiresAt) || row.Device != device {
+		return Credentials{}, ErrInvalidToken
+	}
+
+	row.UsedAt = null.TimeFrom(time.Now())
+	if _, err := row.Update(ctx, tx, boil.Whitelist(models.EnrollmentTokenColumns.UsedAt)); err != nil {
+		return Credentials{}, fmt.Errorf("failed to spend enrollment token: %w", err)
+	}
+
+	return issue(ctx, tx, device)
+}
+
+// Rotate issues the enrolled device new credentials. The current ones stay valid for DEVICE_CREDENTIALS_GRACE,
+// giving the device the time to receive the new ones and reconnect with them
+func Rotate(ctx context.Context, device string) (Credentials, error) {
+	tx, err := boil.BeginTx(ctx, nil)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	credentials, err := rotate(ctx, tx, device)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			log.Error().Err(err).Msg("failed to rollback transaction")
+		}
+
+		return Credentials{}, err
+	}
+
+	return credentials, tx.Commit()
+}
+
+func rotate(ctx context.Context, tx boil.ContextExecutor, device string) (Credentials, error) {
+	enrolled, err := models.DeviceCredentials(models.DeviceCredentialWhere.Device.EQ(device)).Exists(ctx, tx)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to fetch credentials of %s: %w", device, err)
+	}
+	if !enrolled {
+		return Credentials{}, fmt.Errorf("%w: %s", ErrNotEnrolled, device)
+	}
+
+	return issue(ctx, tx, device)
+}
+
+// issue stores new credentials of the device, setting the current ones to expire after the grace period
+func issue(ctx context.Context, tx boil.ContextExecutor, device string) (Credentials, error) {
+	expiresAt := time.Now().Add(config.Duration("DEVICE_CREDENTIALS_GRACE", 24*time.Hour))
+
+	_, err := models.DeviceCredentials(
+		models.DeviceCredentialWhere.Device.EQ(device),
+		qm.Where("("+models.DeviceCredentialColumns.ExpiresAt+" IS NULL OR "+models.DeviceCredentialColumns.ExpiresAt+" > ?)", expiresAt),
+	).UpdateAll(ctx, tx, models.M{models.DeviceCredentialColumns.ExpiresAt: expiresAt})
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to expire credentials of %s: %w", device, err)
+	}
+
+	password, err := random(24)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	secret, err := random(32)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	// the secret is stored as it is, as verifying the signatures requires it
+	row := models.DeviceCredential{
+		Device:       device,
+		PasswordHash: string(passwordHash),
+		Secret:       secret,
+	}
+	if err := row.Insert(ctx, tx, boil.Infer()); err != nil {
+		return Credentials{}, fmt.Errorf("failed to store credentials of %s: %w", device, err)
+	}
+
+	return Credentials{Device: device, Username: device, Password: password, Secret: secret}, nil
+}
+
+// valid returns the credentials of the device which have not expired, newest first
+func valid(ctx context.Context, device string) (models.DeviceCredentialSlice, error) {
+	return models.DeviceCredentials(
+		models.DeviceCredentialWhere.Device.EQ(device),
+		qm.Where("("+models.DeviceCredentialColumns.ExpiresAt+" IS NULL OR "+models.DeviceCredentialColumns.ExpiresAt+" > ?)", time.Now()),
+		qm.OrderBy(models.DeviceCredentialColumns.ID+" DESC"),
+	).AllG(ctx)
+}
+
+// List returns the credentials issued to the device without their secrets, newest first
+func List(ctx context.Context, device string) (models.DeviceCredentialSlice, error) {
+	return models.DeviceCredentials(
+		qm.Select(
+			models.DeviceCredentialColumns.ID,
+			models.DeviceCredentialColumns.Device,
+			models.DeviceCredentialColumns.CreatedAt,
+			models.DeviceCredentialColumns.ExpiresAt,
+		),
+		models.DeviceCredentialWhere.Device.EQ(device),
+		qm.OrderBy(models.DeviceCredentialColumns.ID+" DESC"),
+	).AllG(ctx)
+}
+
+// Secrets returns the secrets of the valid credentials of the device, any of which may sign its payloads
+// while a rotation is under way
+func Secrets(ctx context.Context, device string) ([][]byte, error) {
+	credentials, err := valid(ctx, device)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch credentials of %s: %w", device, err)
+	}
+
+	secrets := make([][]byte, 0, len(credentials))
+	for _, c := range credentials {
+		secrets = append(secrets, []byte(c.Secret))
+	}
+
+	return secrets, nil
+}
+
+// Authenticate reports whether the password matches one of the valid credentials of the device the username names
+func Authenticate(ctx context.Context, username, password string) (bool, error) {
+	credentials, err := valid(ctx, username)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch credentials of %s: %w", username, err)
+	}
+
+	for _, c := range credentials {
+		if bcrypt.CompareHashAndPassword([]byte(c.PasswordHash), []byte(password)) == nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// HandleEnrollment returns the callback enrolling the devices publishing their token to the DEVICE_ENROLL_TOPIC
+func HandleEnrollment(ctx context.Context) mqtt.MessageHandler {
+	return func(client mqtt.Client, resp mqtt.Message) {
+		request := Request{}
+
+		err := json.Unmarshal(resp.Payload(), &request)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to unmarshal enrollment request")
+			metrics.ObserveParseFailure("DEVICE_ENROLL_TOPIC")
+			return
+		}
+
+		if request.Device == "" {
+			request.Device = broker.CabinetID(config.String("DEVICE_ENROLL_TOPIC", ""), resp.Topic())
+		}
+		if request.Device == "" {
+			log.Warn().Str("topic", resp.Topic()).Msg("received enrollment request of an unnamed device")
+			return
+		}
+
+		credentials, err := Enroll(ctx, request.Device, request.Token)
+		if err != nil {
+			log.Warn().Err(err).Str("device", request.Device).Msg("failed to enroll device")
+			credentials = Credentials{Device: request.Device, Error: err.Error()}
+			if !errors.Is(err, ErrInvalidToken) {
+				credentials.Error = "failed to issue credentials"
+			}
+		} else {
+			log.Info().Str("device", request.Device).Msgf("enrolled %s", request.Device)
+		}
+
+		props, _ := broker.MessageProperties(resp)
+		Deliver(client, credentials, props)
+	}
+}
+
+// Deliver publishes the credentials to the response topic of the request the props belong to, falling back
+// to DEVICE_CREDENTIALS_TOPIC/<device>. The broker is to restrict the topic to the device, as it carries its secrets
+func Deliver(client broker.Broker, credentials Credentials, props broker.Properties) {
+	topic := props.ResponseTopic
+	if topic == "" {
+		topic = config.String("DEVICE_CREDENTIALS_TOPIC", "")
+		if topic == "" {
+			log.Warn().Str("device", credentials.Device).Msg("DEVICE_CREDENTIALS_TOPIC is not set, dropping credentials")
+			return
+		}
+
+		topic += "/" + credentials.Device
+	}
+
+	payload, err := json.Marshal(credentials)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to marshal credentials")
+		return
+	}
+
+	delivery := broker.DeliveryFor("DEVICE_CREDENTIALS_TOPIC")
+	t := broker.PublishWithProperties(client, topic, delivery.QoS, delivery.Retained, payload, broker.Properties{
+		CorrelationData: props.CorrelationData,
+		ContentType:     "application/json",
+	})
+	go func() {
+		<-t.Done()
+		if t.Error() != nil {
+			log.Error().Err(t.Error()).Str("topic", topic).Msg("failed to publish credentials")
+		}
+	}()
+}
+
+// random returns n random bytes, base64 encoded for the URLs and the topics
+func random(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hash is the SHA-256 of the token, the tokens being random enough not to need a slow hash
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
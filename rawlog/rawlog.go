@@ -0,0 +1,133 @@
+// Package rawlog keeps every message received on the stream as it was received, along with its QoS and the outcome
+// of processing it, so that the bugs of the firmware can be diagnosed after the fact. The raw_messages table is
+// a ring buffer of the RAW_MESSAGES_PER_DEVICE latest messages of every device
+package rawlog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/strmangle"
+
+	"letovo-computers-server/config"
+	"letovo-computers-server/metrics"
+	"letovo-computers-server/models"
+)
+
+// OutcomeOK is the outcome of the messages processed successfully, the failed ones having the stage they failed at
+const OutcomeOK = "ok"
+
+// Log buffers the received messages and writes them in batches, so that the handlers are not held up by it
+type Log struct {
+	rows chan *models.RawMessage
+}
+
+// New creates the log buffering up to RAW_MESSAGES_BUFFER messages
+func New() *Log {
+	return &Log{rows: make(chan *models.RawMessage, config.Int("RAW_MESSAGES_BUFFER", 1024))}
+}
+
+// Enabled reports whether the messages are kept, RAW_MESSAGES_PER_DEVICE being positive
+func Enabled() bool {
+	return config.Int("RAW_MESSAGES_PER_DEVICE", 100) > 0
+}
+
+// Record queues the message received from the device, failed at the stage with the error unless the stage is
+// empty. The messages are dropped rather than waited for while the buffer is full
+func (l *Log) Record(device string, msg mqtt.Message, stage string, failure error) {
+	if !Enabled() {
+		return
+	}
+
+	row := &models.RawMessage{
+		ReceivedAt: time.Now(),
+		Device:     device,
+		Topic:      msg.Topic(),
+		Qos:        int16(msg.Qos()),
+		Payload:    msg.Payload(),
+		Outcome:    OutcomeOK,
+	}
+	// empty payloads are stored as such rather than as null
+	if row.Payload == nil {
+		row.Payload = []byte{}
+	}
+	if failure != nil {
+		row.Outcome, row.Error = stage, failure.Error()
+	}
+
+	select {
+	case l.rows <- row:
+	default:
+		metrics.RawMessagesDropped.Inc()
+	}
+}
+
+// Run writes the queued messages every RAW_MESSAGES_FLUSH_INTERVAL, or once RAW_MESSAGES_BATCH_SIZE of them are
+// queued, until ctx is done
+func (l *Log) Run(ctx context.Context) {
+	ticker := time.NewTicker(config.Duration("RAW_MESSAGES_FLUSH_INTERVAL", time.Second))
+	defer ticker.Stop()
+
+	var batch models.RawMessageSlice
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case row := <-l.rows:
+			batch = append(batch, row)
+			if len(batch) < config.Int("RAW_MESSAGES_BATCH_SIZE", 100) {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+
+		if err := write(ctx, batch); err != nil {
+			log.Error().Err(err).Int("messages", len(batch)).Msg("failed to log raw messages")
+		}
+		batch = nil
+	}
+}
+
+// write inserts the batch and prunes the messages of its devices past the latest RAW_MESSAGES_PER_DEVICE
+func write(ctx context.Context, batch models.RawMessageSlice) error {
+	tx, err := boil.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	devices := make(map[string]bool)
+	for _, row := range batch {
+		if err := row.Insert(ctx, tx, boil.Infer()); err != nil {
+			return fmt.Errorf("failed to insert raw message: %w", err)
+		}
+		devices[row.Device] = true
+	}
+
+	args := make([]interface{}, 0, len(devices)+1)
+	args = append(args, config.Int("RAW_MESSAGES_PER_DEVICE", 100))
+	for device := range devices {
+		args = append(args, device)
+	}
+
+	_, err = queries.Raw(fmt.Sprintf(`DELETE FROM raw_messages WHERE id IN (
+	SELECT id FROM (
+		SELECT id, row_number() OVER (PARTITION BY device ORDER BY id DESC) AS n FROM raw_messages WHERE device IN (%s)
+	) ranked WHERE n > $1
+)`, strmangle.Placeholders(true, len(devices), 2, 1)), args...).ExecContext(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("failed to prune raw messages: %w", err)
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,54 @@
+package recent
+
+import (
+	"sync"
+
+	"letovo-computers-server/types"
+)
+
+// Buffer is a fixed-size thread-safe ring buffer keeping the most recent events
+type Buffer struct {
+	mu     sync.Mutex
+	events []types.Event
+	next   int
+	full   bool
+}
+
+// New creates a Buffer holding up to size events
+func New(size int) *Buffer {
+	if size < 1 {
+		size = 1
+	}
+
+	return &Buffer{events: make([]types.Event, size)}
+}
+
+// Add records the event, overwriting the oldest one if the buffer is full
+func (b *Buffer) Add(event types.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.events[b.next] = event
+	b.next = (b.next + 1) % len(b.events)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// List returns the recorded events, newest first
+func (b *Buffer) List() []types.Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n := b.next
+	if b.full {
+		n = len(b.events)
+	}
+
+	events := make([]types.Event, 0, n)
+	for i := 1; i <= n; i++ {
+		events = append(events, b.events[(b.next-i+len(b.events))%len(b.events)])
+	}
+
+	return events
+}
@@ -0,0 +1,81 @@
+package replay
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+
+	"letovo-computers-server/config"
+	"letovo-computers-server/models"
+)
+
+var (
+	// ErrDuplicate is returned for messages whose sequence number was already processed
+	ErrDuplicate = errors.New("duplicate message")
+	// ErrOutOfWindow is returned for messages sent too long before or after they were received
+	ErrOutOfWindow = errors.New("message sent outside the replay window")
+)
+
+// recordQuery raises the sequence number of the device, leaving it be if it is already higher
+const recordQuery = `INSERT INTO devices (id, last_seq) VALUES ($1, $2)
+ON CONFLICT (id) DO UPDATE SET last_seq = excluded.last_seq WHERE devices.last_seq < excluded.last_seq`
+
+// Check rejects the message of the device if its sequence number is not above the last one processed, or if it was
+// sent, by the device's clock in Unix seconds, further than REPLAY_WINDOW from now. Messages without a sequence
+// number or a timestamp skip the respective check, as does every message when the window is zero. The sequence
+// number is read without a lock, dropping the duplicates early, and Record settles the ones racing each other
+func Check(ctx context.Context, device string, seq uint64, sentAt int64) error {
+	if window := config.Duration("REPLAY_WINDOW", 0); window > 0 && sentAt != 0 {
+		skew := time.Since(time.Unix(sentAt, 0))
+		if skew > window || skew < -window {
+			return fmt.Errorf("%w of %s, off by %s", ErrOutOfWindow, window, skew.Round(time.Second))
+		}
+	}
+
+	if seq == 0 || device == "" {
+		return nil
+	}
+
+	d, err := models.FindDeviceG(ctx, device, models.DeviceColumns.LastSeq)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch sequence number of %s: %w", device, err)
+	}
+
+	if seq <= uint64(d.LastSeq) {
+		return fmt.Errorf("%w %d of %s, last processed %d", ErrDuplicate, seq, device, d.LastSeq)
+	}
+
+	return nil
+}
+
+// Record raises the sequence number of the device to seq within the transaction storing the message, failing with
+// ErrDuplicate if it is already as high. The row of the device stays locked until the transaction ends, so that
+// the message redelivered to another instance meanwhile waits for it and is rejected once it commits, while a
+// message failing to be stored rolls its sequence number back and is accepted again when the device retries it
+func Record(ctx context.Context, exec boil.ContextExecutor, device string, seq uint64) error {
+	if seq == 0 || device == "" {
+		return nil
+	}
+
+	res, err := exec.ExecContext(ctx, recordQuery, device, int64(seq))
+	if err != nil {
+		return fmt.Errorf("failed to record sequence number of %s: %w", device, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to record sequence number of %s: %w", device, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w %d of %s, recorded concurrently", ErrDuplicate, seq, device)
+	}
+
+	return nil
+}
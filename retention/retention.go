@@ -0,0 +1,260 @@
+// Package retention prunes the rows older than their retention period, so that the db does not grow unbounded.
+// The pruned rows are archived beforehand to RETENTION_ARCHIVE_DIR, if set, as gzipped JSON lines
+package retention
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+
+	"letovo-computers-server/config"
+	"letovo-computers-server/metrics"
+	"letovo-computers-server/models"
+	"letovo-computers-server/storage"
+)
+
+// table is pruned of the rows whose column is older than the retention period in the setting
+type table struct {
+	name    string
+	column  string
+	setting string
+	def     time.Duration
+	// where narrows down the rows to prune, like to the finished commands
+	where string
+}
+
+// the commands are only pruned once finished, as the pending ones are still expired by the dispatcher, and the
+// sessions of the devices once ended. The webhook deliveries are not logged to the db, so there is nothing to prune
+// of them
+var tables = []table{
+	{name: models.TableNames.Events, column: models.EventColumns.ReceivedAt, setting: "EVENTS_RETENTION", def: 365 * 24 * time.Hour},
+	{name: models.TableNames.FailedMessages, column: models.FailedMessageColumns.ReceivedAt, setting: "FAILED_MESSAGES_RETENTION", def: 90 * 24 * time.Hour},
+	{
+		name: models.TableNames.Commands, column: models.CommandColumns.CreatedAt, setting: "COMMANDS_RETENTION", def: 90 * 24 * time.Hour,
+		where: models.CommandColumns.Status + " NOT IN ('pending', 'delivered')",
+	},
+	{
+		name: models.TableNames.DeviceSessions, column: models.DeviceSessionColumns.ConnectedAt,
+		setting: "DEVICE_SESSIONS_RETENTION", def: 365 * 24 * time.Hour,
+		where: models.DeviceSessionColumns.DisconnectedAt + " IS NOT NULL",
+	},
+	{
+		name: models.TableNames.OccupancySamples, column: models.OccupancySampleColumns.SampledAt,
+		setting: "OCCUPANCY_SAMPLES_RETENTION", def: 2 * 365 * 24 * time.Hour,
+	},
+}
+
+// Run prunes the tables of the db of the driver every RETENTION_INTERVAL until ctx is done
+func Run(ctx context.Context, driver storage.Driver) {
+	ticker := time.NewTicker(config.Duration("RETENTION_INTERVAL", time.Hour))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, t := range tables {
+				retention := config.Duration(t.setting, t.def)
+				if retention <= 0 {
+					continue
+				}
+
+				n, err := prune(ctx, driver, t, time.Now().Add(-retention))
+				if err != nil {
+					log.Error().Err(err).Str("table", t.name).Msg("failed to prune table")
+					continue
+				}
+
+				if n > 0 {
+					log.Info().Str("table", t.name).Int("rows", n).Msgf("Pruned %d rows of %s", n, t.name)
+				}
+			}
+		}
+	}
+}
+
+// prune deletes the rows of the table older than the cutoff, RETENTION_BATCH_SIZE rows per transaction so that
+// the handlers are not held up, and returns how many it deleted. Every batch is archived before it is committed,
+// and instances pruning at the same time skip the table another one is working on
+func prune(ctx context.Context, driver storage.Driver, t table, cutoff time.Time) (int, error) {
+	out, err := openArchive(t.name)
+	if err != nil {
+		return 0, err
+	}
+	if out != nil {
+		defer out.Close()
+	}
+
+	where := fmt.Sprintf("%s < $1", t.column)
+	if t.where != "" {
+		where += " AND " + t.where
+	}
+
+	query := fmt.Sprintf(`WITH pruned AS (
+	DELETE FROM %[1]s WHERE ctid IN (SELECT ctid FROM %[1]s WHERE %[2]s LIMIT $2) RETURNING *
+) SELECT row_to_json(pruned)::TEXT FROM pruned`, t.name, where)
+
+	if driver == storage.SQLite {
+		query, err = sqliteQuery(ctx, t.name, where)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	total := 0
+	for {
+		n, err := pruneBatch(ctx, driver, t.name, query, cutoff, out)
+		total += n
+		if err != nil || n < config.Int("RETENTION_BATCH_SIZE", 1000) {
+			return total, err
+		}
+	}
+}
+
+// sqliteQuery builds the pruning query of the table for sqlite, which neither deletes in a CTE nor converts
+// whole rows to JSON, so the pruned rows are returned as objects of their columns. The blobs are hex encoded
+// the way postgres encodes bytea
+func sqliteQuery(ctx context.Context, name, where string) (string, error) {
+	rows, err := boil.GetContextDB().QueryContext(ctx, "SELECT name, type FROM pragma_table_info($1)", name)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch columns of %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	var fields []string
+	for rows.Next() {
+		var column, typ string
+		if err := rows.Scan(&column, &typ); err != nil {
+			return "", fmt.Errorf("failed to scan column of %s: %w", name, err)
+		}
+
+		value := fmt.Sprintf(`"%s"`, column)
+		if strings.EqualFold(typ, "BLOB") {
+			value = fmt.Sprintf(`'\x' || lower(hex("%s"))`, column)
+		}
+
+		fields = append(fields, fmt.Sprintf(`'%s', %s`, column, value))
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("failed to fetch columns of %s: %w", name, err)
+	}
+
+	return fmt.Sprintf(`DELETE FROM %[1]s WHERE rowid IN (SELECT rowid FROM %[1]s WHERE %[2]s LIMIT $2)
+RETURNING json_object(%[3]s)`, name, where, strings.Join(fields, ", ")), nil
+}
+
+func pruneBatch(ctx context.Context, driver storage.Driver, name, query string, cutoff time.Time, out *archive) (int, error) {
+	tx, err := boil.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	locked, err := storage.TryLock(ctx, tx, driver, "retention:"+name)
+	if err != nil || !locked {
+		return 0, err
+	}
+
+	rows, err := tx.QueryContext(ctx, query, cutoff, config.Int("RETENTION_BATCH_SIZE", 1000))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune %s: %w", name, err)
+	}
+
+	n, err := out.write(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit pruning %s: %w", name, err)
+	}
+
+	metrics.RowsPruned.WithLabelValues(name).Add(float64(n))
+
+	return n, nil
+}
+
+// archive appends the pruned rows of a table to the gzipped file of the day
+type archive struct {
+	file *os.File
+}
+
+// openArchive opens the archive of the table, nil when RETENTION_ARCHIVE_DIR is not set
+func openArchive(name string) (*archive, error) {
+	dir := config.String("RETENTION_ARCHIVE_DIR", "")
+	if dir == "" {
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create archive dir: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.jsonl.gz", name, time.Now().UTC().Format("2006-01-02")))
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive %s: %w", path, err)
+	}
+
+	return &archive{file: file}, nil
+}
+
+// write archives the rows as a gzip member of its own, synced to disk before the rows are deleted for good.
+// Without an archive the rows are only counted
+func (a *archive) write(rows *sql.Rows) (int, error) {
+	defer rows.Close()
+
+	var zw *gzip.Writer
+	if a != nil {
+		zw = gzip.NewWriter(a.file)
+	}
+
+	n := 0
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return 0, fmt.Errorf("failed to scan pruned row: %w", err)
+		}
+		n++
+
+		if zw == nil {
+			continue
+		}
+		if _, err := zw.Write(append([]byte(row), '\n')); err != nil {
+			return 0, fmt.Errorf("failed to archive pruned row: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to prune rows: %w", err)
+	}
+
+	if zw == nil || n == 0 {
+		return n, nil
+	}
+
+	if err := zw.Close(); err != nil {
+		return 0, fmt.Errorf("failed to archive pruned rows: %w", err)
+	}
+	if err := a.file.Sync(); err != nil {
+		return 0, fmt.Errorf("failed to sync archive: %w", err)
+	}
+
+	return n, nil
+}
+
+// Close closes the archive file
+func (a *archive) Close() error {
+	return a.file.Close()
+}
@@ -0,0 +1,173 @@
+package retention
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/models"
+	"letovo-computers-server/storage"
+	"letovo-computers-server/storage/storagetest"
+)
+
+// archived reads the ID of every row archived for the table
+func archived(t *testing.T, dir, name string) []string {
+	t.Helper()
+
+	matches, err := filepath.Glob(filepath.Join(dir, name+"-*.jsonl.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, path := range matches {
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+
+		// every batch is a gzip member of its own, which the reader reads on through
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		scanner := bufio.NewScanner(zr)
+		for scanner.Scan() {
+			var row map[string]interface{}
+			if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+				t.Fatalf("archived row %q is not JSON: %v", scanner.Text(), err)
+			}
+
+			switch id := row["id"].(type) {
+			case string:
+				ids = append(ids, id)
+			case float64:
+				ids = append(ids, strconv.FormatFloat(id, 'f', -1, 64))
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
+
+func TestPrune(t *testing.T) {
+	storagetest.New(t)
+	dir := t.TempDir()
+	t.Setenv("RETENTION_ARCHIVE_DIR", dir)
+	// every row is a batch of its own
+	t.Setenv("RETENTION_BATCH_SIZE", "1")
+
+	ctx := context.Background()
+	now := time.Now()
+	old, recent := now.Add(-48*time.Hour), now.Add(-time.Hour)
+
+	for _, receivedAt := range []time.Time{old, old, recent} {
+		if err := (&models.Event{ReceivedAt: receivedAt, Slot: "A1"}).InsertG(ctx, boil.Infer()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commands := []*models.Command{
+		{ID: "old-acknowledged", CMD: "unlock", Status: "acknowledged", CreatedAt: old},
+		{ID: "old-pending", CMD: "unlock", Status: "pending", CreatedAt: old},
+		{ID: "old-delivered", CMD: "unlock", Status: "delivered", CreatedAt: old},
+		{ID: "recent-failed", CMD: "unlock", Status: "failed", CreatedAt: recent},
+	}
+	for _, command := range commands {
+		command.ExpiresAt = command.CreatedAt.Add(time.Minute)
+		if err := command.InsertG(ctx, boil.Infer()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sessions := []*models.DeviceSession{
+		{Device: "cabinet-1", ConnectedAt: old, ConnectedBy: "broker", DisconnectedAt: null.TimeFrom(old.Add(time.Hour))},
+		{Device: "cabinet-2", ConnectedAt: old, ConnectedBy: "broker"},
+		{Device: "cabinet-3", ConnectedAt: recent, ConnectedBy: "broker", DisconnectedAt: null.TimeFrom(now)},
+	}
+	for _, session := range sessions {
+		if err := session.InsertG(ctx, boil.Infer()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cutoff := now.Add(-24 * time.Hour)
+	pruned := make(map[string]int)
+	for _, table := range tables {
+		n, err := prune(ctx, storage.SQLite, table, cutoff)
+		if err != nil {
+			t.Fatalf("prune(%s) error = %v", table.name, err)
+		}
+		pruned[table.name] = n
+	}
+
+	want := map[string]int{
+		models.TableNames.Events:         2,
+		models.TableNames.Commands:       1,
+		models.TableNames.DeviceSessions: 1,
+	}
+	for name, n := range want {
+		if pruned[name] != n {
+			t.Errorf("pruned %d rows of %s, want %d", pruned[name], name, n)
+		}
+	}
+
+	events, err := models.Events().AllG(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].ID != 3 {
+		t.Errorf("kept %d events, want only the recent one", len(events))
+	}
+
+	kept, err := models.Commands(qm.OrderBy(models.CommandColumns.ID)).AllG(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keptIDs []string
+	for _, command := range kept {
+		keptIDs = append(keptIDs, command.ID)
+	}
+	if got := strings.Join(keptIDs, ","); got != "old-delivered,old-pending,recent-failed" {
+		t.Errorf("kept commands %s, want the pending, delivered and recent ones", got)
+	}
+
+	open, err := models.DeviceSessions(qm.OrderBy(models.DeviceSessionColumns.ID)).AllG(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 2 || open[0].Device != "cabinet-2" || open[1].Device != "cabinet-3" {
+		t.Errorf("kept %d sessions, want the open and the recent ones", len(open))
+	}
+
+	// the deleted rows, and only them, are archived
+	archives := map[string]string{
+		models.TableNames.Events:         "1,2",
+		models.TableNames.Commands:       "old-acknowledged",
+		models.TableNames.DeviceSessions: "1",
+	}
+	for name, ids := range archives {
+		if got := strings.Join(archived(t, dir, name), ","); got != ids {
+			t.Errorf("archived %s rows %s, want %s", name, got, ids)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"letovo-computers-server/aliases"
+	"letovo-computers-server/auth"
+	"letovo-computers-server/broker"
+	"letovo-computers-server/cabinetpb"
+	"letovo-computers-server/events"
+	"letovo-computers-server/models"
+	"letovo-computers-server/state"
+)
+
+// Options are the services backing the gRPC service
+type Options struct {
+	Client  broker.Broker
+	Hub     *events.Hub
+	Aliases *aliases.Aliases
+}
+
+type server struct {
+	cabinetpb.UnimplementedCabinetServer
+
+	opts Options
+}
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+
+	// streamBuffer is how many events a slow StreamEvents client may fall behind before being dropped
+	streamBuffer = 64
+)
+
+// New creates the gRPC server exposing the cabinet service
+func New(opts Options) *grpc.Server {
+	srv := grpc.NewServer()
+	cabinetpb.RegisterCabinetServer(srv, &server{opts: opts})
+
+	return srv
+}
+
+func (s *server) slot(slot *models.Slot) *cabinetpb.Slot {
+	return &cabinetpb.Slot{
+		Id:        slot.ID,
+		IsTaken:   slot.IsTaken,
+		TakenBy:   slot.TakenBy,
+		Alias:     s.opts.Aliases.Alias(slot.ID),
+		CabinetId: slot.CabinetID,
+		Row:       int32(slot.Row),
+		Column:    int32(slot.Column),
+		Label:     slot.Label,
+		Notes:     slot.Notes,
+	}
+}
+
+func (s *server) ListSlots(ctx context.Context, req *cabinetpb.ListSlotsRequest) (*cabinetpb.ListSlotsResponse, error) {
+	if err := authorize(ctx, auth.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	var filters []qm.QueryMod
+	if req.Taken != nil {
+		filters = append(filters, models.SlotWhere.IsTaken.EQ(req.GetTaken()))
+	}
+
+	if req.TakenBy != "" {
+		filters = append(filters, models.SlotWhere.TakenBy.EQ(req.TakenBy))
+	}
+
+	if req.Cabinet != "" {
+		filters = append(filters, state.InCabinet(req.Cabinet))
+	}
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if limit < 0 || limit > maxLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", maxLimit)
+	}
+	if req.Offset < 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset must not be negative")
+	}
+
+	total, err := models.Slots(filters...).CountG(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to count slots")
+		return nil, status.Error(codes.Internal, "failed to fetch slots")
+	}
+
+	page := []qm.QueryMod{qm.OrderBy(models.SlotColumns.ID), qm.Limit(limit), qm.Offset(int(req.Offset))}
+
+	slots, err := models.Slots(append(filters, page...)...).AllG(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch slots")
+		return nil, status.Error(codes.Internal, "failed to fetch slots")
+	}
+
+	response := &cabinetpb.ListSlotsResponse{Slots: make([]*cabinetpb.Slot, 0, len(slots)), Total: total}
+	for _, slot := range slots {
+		response.Slots = append(response.Slots, s.slot(slot))
+	}
+
+	return response, nil
+}
+
+func (s *server) GetUser(ctx context.Context, req *cabinetpb.GetUserRequest) (*cabinetpb.User, error) {
+	if err := authorize(ctx, auth.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	user, err := models.FindUserG(ctx, req.Rfid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch user")
+		return nil, status.Error(codes.Internal, "failed to fetch user")
+	}
+
+	slots, err := user.TakenBySlots(models.SlotWhere.IsTaken.EQ(true)).AllG(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch slots taken by user")
+		return nil, status.Error(codes.Internal, "failed to fetch user")
+	}
+
+	response := &cabinetpb.User{
+		Id:        user.ID,
+		Login:     user.Login,
+		Name:      user.Name,
+		Email:     user.Email,
+		Slots:     make([]*cabinetpb.Slot, 0, len(slots)),
+		Class:     user.Class,
+		Role:      user.Role,
+		Active:    user.Active,
+		CreatedAt: timestamppb.New(user.CreatedAt),
+		UpdatedAt: timestamppb.New(user.UpdatedAt),
+	}
+	for _, slot := range slots {
+		response.Slots = append(response.Slots, s.slot(slot))
+	}
+
+	return response, nil
+}
+
+func (s *server) ReleaseSlot(ctx context.Context, req *cabinetpb.ReleaseSlotRequest) (*cabinetpb.Slot, error) {
+	if err := authorize(ctx, auth.RoleOperator); err != nil {
+		return nil, err
+	}
+
+	slotID := s.opts.Aliases.ID(req.Id)
+
+	err := state.ReleaseSlot(ctx, slotID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "slot not found")
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to release slot")
+		return nil, status.Error(codes.Internal, "failed to release slot")
+	}
+
+	log.Info().Str("slot", slotID).Msgf("force-released %s", slotID)
+
+	state.AnnounceRelease(ctx, s.opts.Client, slotID)
+
+	slot, err := models.FindSlotG(ctx, slotID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch slot")
+		return nil, status.Error(codes.Internal, "failed to fetch slot")
+	}
+
+	return s.slot(slot), nil
+}
+
+func (s *server) StreamEvents(_ *cabinetpb.StreamEventsRequest, stream cabinetpb.Cabinet_StreamEventsServer) error {
+	if err := authorize(stream.Context(), auth.RoleViewer); err != nil {
+		return err
+	}
+
+	events, cancel := s.opts.Hub.Subscribe(streamBuffer)
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "fell behind the event stream")
+			}
+
+			err := stream.Send(&cabinetpb.Event{
+				Timestamp: timestamppb.New(event.Timestamp),
+				Rfid:      event.RFID,
+				Slot:      event.Slot,
+				Status:    int32(event.Status),
+				Result:    event.Result,
+				Device:    event.Device,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// authorize checks that the call bears a token granting at least the role. The viewer methods stay public
+// while no JWT_ALG is configured, as the HTTP endpoints do
+func authorize(ctx context.Context, role string) error {
+	if role == auth.RoleViewer && !auth.JWTEnabled() {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var token string
+	if values := md.Get("authorization"); len(values) > 0 && strings.HasPrefix(values[0], "Bearer ") {
+		token = strings.TrimPrefix(values[0], "Bearer ")
+	}
+
+	identity, err := auth.Authenticate(ctx, token)
+	if err != nil && !errors.Is(err, auth.ErrUnauthorized) {
+		log.Error().Err(err).Msg("failed to authenticate")
+		return status.Error(codes.Internal, "failed to authenticate")
+	}
+	if err != nil {
+		log.Debug().Err(err).Msg("rejected token")
+		return status.Error(codes.Unauthenticated, "unauthorized")
+	}
+
+	if !identity.Allowed(role) {
+		return status.Error(codes.PermissionDenied, "forbidden")
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"letovo-computers-server/models"
+)
+
+// expected maps the tables the handlers use to the generated structs listing their columns
+var expected = map[string]interface{}{
+	models.TableNames.AuditLog:          models.AuditLogColumns,
+	models.TableNames.Commands:          models.CommandColumns,
+	models.TableNames.DeviceCredentials: models.DeviceCredentialColumns,
+	models.TableNames.DeviceSessions:    models.DeviceSessionColumns,
+	models.TableNames.Devices:           models.DeviceColumns,
+	models.TableNames.EnrollmentTokens:  models.EnrollmentTokenColumns,
+	models.TableNames.Events:            models.EventColumns,
+	models.TableNames.Laptops:           models.LaptopColumns,
+	models.TableNames.Loans:             models.LoanColumns,
+	models.TableNames.RawMessages:       models.RawMessageColumns,
+	models.TableNames.OccupancySamples:  models.OccupancySampleColumns,
+	models.TableNames.OutboxMessages:    models.OutboxMessageColumns,
+	models.TableNames.FailedMessages:    models.FailedMessageColumns,
+	models.TableNames.FirmwareReleases:  models.FirmwareReleaseColumns,
+	models.TableNames.Roles:             models.RoleColumns,
+	models.TableNames.Slots:             models.SlotColumns,
+	models.TableNames.Users:             models.UserColumns,
+	models.TableNames.Webhooks:          models.WebhookColumns,
+}
+
+// columnsQuery lists the tables and their columns, sqlite lacking the information schema
+var columnsQuery = map[string]string{
+	"postgres": "SELECT table_name, column_name FROM information_schema.columns WHERE table_schema = current_schema()",
+	"sqlite":   "SELECT m.name, p.name FROM sqlite_master m JOIN pragma_table_info(m.name) p WHERE m.type = 'table'",
+}
+
+// Verify checks that every table and column the models expect exists in the db of the driver, so that a binary
+// built against a newer schema fails fast at startup instead of with confusing errors at runtime
+func Verify(ctx context.Context, db *sql.DB, driver string) error {
+	query, ok := columnsQuery[driver]
+	if !ok {
+		return fmt.Errorf("unknown db driver %q", driver)
+	}
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to query information schema: %w", err)
+	}
+	defer rows.Close()
+
+	actual := make(map[string]map[string]bool)
+	for rows.Next() {
+		var table, column string
+
+		err = rows.Scan(&table, &column)
+		if err != nil {
+			return fmt.Errorf("failed to scan information schema: %w", err)
+		}
+
+		if actual[table] == nil {
+			actual[table] = make(map[string]bool)
+		}
+		actual[table][column] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to read information schema: %w", err)
+	}
+
+	var missing []string
+	for table, columns := range expected {
+		if actual[table] == nil {
+			missing = append(missing, fmt.Sprintf("table %s", table))
+			continue
+		}
+
+		for _, column := range columnNames(columns) {
+			if !actual[table][column] {
+				missing = append(missing, fmt.Sprintf("column %s.%s", table, column))
+			}
+		}
+	}
+
+	if len(missing) != 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("db schema does not match the models, missing %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// columnNames lists the values of the generated <Model>Columns struct
+func columnNames(columns interface{}) []string {
+	v := reflect.ValueOf(columns)
+
+	names := make([]string, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		names = append(names, v.Field(i).String())
+	}
+
+	return names
+}
@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"letovo-computers-server/models"
+)
+
+// mockDB answers the columns query of the driver with every column the models expect, but the skipped one
+func mockDB(t *testing.T, driver, skipTable, skipColumn string) *sql.DB {
+	t.Helper()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	rows := sqlmock.NewRows([]string{"table_name", "column_name"})
+	for table, columns := range expected {
+		for _, column := range columnNames(columns) {
+			if table != skipTable || column != skipColumn {
+				rows.AddRow(table, column)
+			}
+		}
+	}
+	mock.ExpectQuery(columnsQuery[driver]).WillReturnRows(rows)
+
+	return db
+}
+
+func TestVerify(t *testing.T) {
+	for _, driver := range []string{"postgres", "sqlite"} {
+		t.Run(driver, func(t *testing.T) {
+			db := mockDB(t, driver, "", "")
+
+			if err := Verify(context.Background(), db, driver); err != nil {
+				t.Errorf("Verify() error = %v, want the schema matching", err)
+			}
+		})
+	}
+}
+
+func TestVerifyMissingColumn(t *testing.T) {
+	db := mockDB(t, "postgres", models.TableNames.Slots, models.SlotColumns.TakenBy)
+
+	err := Verify(context.Background(), db, "postgres")
+	want := "db schema does not match the models, missing column slots.taken_by"
+	if err == nil || err.Error() != want {
+		t.Errorf("Verify() error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,76 @@
+package scopes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"letovo-computers-server/types"
+)
+
+// Scopes restricts the slots every device may report, so that e.g. a reader in one room cannot report slots of another
+type Scopes struct {
+	prefixes map[string][]string
+}
+
+// Load reads the JSON object mapping device IDs to the slot ID prefixes they are permitted to report.
+// An empty path yields Scopes allowing everything
+func Load(path string) (*Scopes, error) {
+	if path == "" {
+		return &Scopes{}, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read device scopes: %w", err)
+	}
+
+	prefixes := make(map[string][]string)
+
+	err = json.Unmarshal(b, &prefixes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal device scopes: %w", err)
+	}
+
+	for device := range prefixes {
+		for i, prefix := range prefixes[device] {
+			prefixes[device][i] = types.CanonicalizeSlotID(prefix)
+		}
+	}
+
+	return &Scopes{prefixes: prefixes}, nil
+}
+
+// Allowed reports whether the device may report the slot. Once scopes are configured, unknown devices may report nothing
+func (s *Scopes) Allowed(device, slotID string) bool {
+	if s.prefixes == nil {
+		return true
+	}
+
+	for _, prefix := range s.prefixes[device] {
+		if strings.HasPrefix(slotID, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Device returns the device whose scope covers the slot with the longest prefix, or "" if none does
+func (s *Scopes) Device(slotID string) string {
+	var device, longest string
+	for d, prefixes := range s.prefixes {
+		for _, prefix := range prefixes {
+			if !strings.HasPrefix(slotID, prefix) {
+				continue
+			}
+
+			if device == "" || len(prefix) > len(longest) || (len(prefix) == len(longest) && d < device) {
+				device, longest = d, prefix
+			}
+		}
+	}
+
+	return device
+}
@@ -0,0 +1,49 @@
+package scopes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAllowed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scopes.json")
+	if err := os.WriteFile(path, []byte(`{"room-a": ["a-"], "room-b": ["B"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		device  string
+		slotID  string
+		allowed bool
+	}{
+		{name: "in scope", device: "room-a", slotID: "A1", allowed: true},
+		{name: "out of scope", device: "room-a", slotID: "B1"},
+		{name: "unknown device", device: "room-c", slotID: "A1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allowed := s.Allowed(tt.device, tt.slotID); allowed != tt.allowed {
+				t.Errorf("Allowed(%q, %q) = %t, want %t", tt.device, tt.slotID, allowed, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestAllowedUnconfigured(t *testing.T) {
+	s, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !s.Allowed("room-c", "A1") {
+		t.Error("Allowed() = false without scopes, want everything allowed")
+	}
+}
@@ -0,0 +1,144 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var (
+	// ErrUnsigned is returned for payloads without a signature
+	ErrUnsigned = errors.New("payload is not signed")
+	// ErrUnknownDevice is returned for payloads of devices without a secret
+	ErrUnknownDevice = errors.New("device has no secret")
+	// ErrForged is returned for payloads whose signature does not match
+	ErrForged = errors.New("payload signature does not match")
+)
+
+// Secrets holds the keys the devices sign their payloads with. A signed payload is the message followed by a newline
+// and the hex encoded HMAC-SHA256 of the message
+type Secrets struct {
+	keys   map[string][]byte
+	lookup Lookup
+}
+
+// Lookup returns the secrets of a device missing from the file, any of which may sign its payloads
+type Lookup func(device string) ([][]byte, error)
+
+// Load reads the JSON object mapping device IDs to their secrets. An empty path yields Secrets verifying nothing
+func Load(path string) (*Secrets, error) {
+	if path == "" {
+		return &Secrets{}, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read device secrets: %w", err)
+	}
+
+	secrets := make(map[string]string)
+
+	err = json.Unmarshal(b, &secrets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal device secrets: %w", err)
+	}
+
+	keys := make(map[string][]byte, len(secrets))
+	for device, secret := range secrets {
+		keys[device] = []byte(secret)
+	}
+
+	return &Secrets{keys: keys}, nil
+}
+
+// WithLookup has the secrets verify the payloads of the devices missing from the file with the ones lookup returns,
+// like the secrets issued to the enrolled devices. Payloads must then be signed even without a file
+func (s *Secrets) WithLookup(lookup Lookup) *Secrets {
+	return &Secrets{keys: s.keys, lookup: lookup}
+}
+
+// Enabled reports whether the payloads must be signed
+func (s *Secrets) Enabled() bool {
+	return s.keys != nil || s.lookup != nil
+}
+
+// Verify checks the signature of the payload, returning the message it signs. The device is named by the device
+// field of a JSON message, falling back to the given device, e.g. the one named by the topic. When disabled,
+// the payload is returned as it is
+func (s *Secrets) Verify(device string, payload []byte) ([]byte, error) {
+	if !s.Enabled() {
+		return payload, nil
+	}
+
+	payload = bytes.TrimRight(payload, " \r\n\t")
+
+	i := bytes.LastIndexByte(payload, '\n')
+	if i < 0 {
+		return nil, ErrUnsigned
+	}
+
+	message, signature := payload[:i], payload[i+1:]
+
+	if len(signature) != hex.EncodedLen(sha256.Size) {
+		return nil, ErrUnsigned
+	}
+
+	sum := make([]byte, sha256.Size)
+	if _, err := hex.Decode(sum, signature); err != nil {
+		return nil, ErrUnsigned
+	}
+
+	var named struct {
+		Device string `json:"device"`
+	}
+	if json.Unmarshal(message, &named) == nil && named.Device != "" {
+		device = named.Device
+	}
+
+	keys, err := s.keysOf(device)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownDevice, device)
+	}
+
+	for _, key := range keys {
+		if hmac.Equal(Sign(key, message), sum) {
+			return message, nil
+		}
+	}
+
+	return nil, ErrForged
+}
+
+// keysOf returns the secrets of the device, the one in the file taking precedence over the looked up ones
+func (s *Secrets) keysOf(device string) ([][]byte, error) {
+	if key, ok := s.keys[device]; ok {
+		return [][]byte{key}, nil
+	}
+
+	if s.lookup == nil {
+		return nil, nil
+	}
+
+	keys, err := s.lookup(device)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up secrets of %q: %w", device, err)
+	}
+
+	return keys, nil
+}
+
+// Sign returns the HMAC-SHA256 of the message
+func Sign(key, message []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(message)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,322 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/config"
+	"letovo-computers-server/models"
+	"letovo-computers-server/outbox"
+	"letovo-computers-server/storage"
+	"letovo-computers-server/types"
+)
+
+// NobodyRFID is the placeholder user that released slots are assigned to
+const NobodyRFID = "null"
+
+// Enabled reports whether the SERVER_STATE_TOPIC is configured
+func Enabled() bool {
+	return config.String("SERVER_STATE_TOPIC", "") != ""
+}
+
+// CabinetsEnabled reports whether the SERVER_CABINET_STATE_TOPIC is configured
+func CabinetsEnabled() bool {
+	return config.String("SERVER_CABINET_STATE_TOPIC", "") != ""
+}
+
+// CabinetTopic returns the topic the snapshot of the cabinet is retained at, the SERVER_CABINET_STATE_TOPIC
+// with its wildcard, like cabinets/+/state, filled with the cabinet ID
+func CabinetTopic(cabinetID string) string {
+	return broker.Fill(config.String("SERVER_CABINET_STATE_TOPIC", ""), cabinetID)
+}
+
+// InCabinet filters the slots by the cabinet they are set up in. The slots provisioned without a cabinet_id are
+// still told apart by the prefix of their IDs, as the cabinets were before
+func InCabinet(cabinet string) qm.QueryMod {
+	return qm.Where(
+		fmt.Sprintf("(%[1]s = ? OR (%[1]s = '' AND %[2]s LIKE ?))", models.SlotColumns.CabinetID, models.SlotColumns.ID),
+		strings.TrimSpace(cabinet), types.CanonicalizeSlotID(cabinet)+"%",
+	)
+}
+
+// CabinetSnapshot is retained at the topic of the cabinet, so that its devices can reconcile their slot LEDs
+// and locks with the server after a reboot
+type CabinetSnapshot struct {
+	CabinetID string           `json:"cabinet_id"`
+	Slots     models.SlotSlice `json:"slots"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// SlotTopic returns the topic the state of the slot is retained at
+func SlotTopic(slotID string) string {
+	return config.String("SERVER_STATE_TOPIC", "") + "/" + types.CanonicalizeSlotID(slotID)
+}
+
+// PublishSnapshot publishes the current slot assignments from the db to the SERVER_STATE_TOPIC as a retained message
+func PublishSnapshot(ctx context.Context, client broker.Broker) error {
+	slots, err := models.Slots().AllG(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch slots: %w", err)
+	}
+
+	return publishSnapshot(client, slots)
+}
+
+// Queue queues the retained state of the slots and the snapshot of all of them in the outbox, if enabled, so that
+// they are published once the transaction changing the slots commits, and in the order it did
+func Queue(ctx context.Context, repos storage.Repos, slotIDs []string) error {
+	if !Enabled() || len(slotIDs) == 0 {
+		return nil
+	}
+
+	slots, err := repos.Slots.All(ctx)
+	if err != nil {
+		return err
+	}
+
+	changed := make(map[string]bool, len(slotIDs))
+	for _, slotID := range slotIDs {
+		changed[slotID] = true
+	}
+
+	delivery := broker.DeliveryFor("SERVER_STATE_TOPIC")
+	messages := make([]*models.OutboxMessage, 0, len(slotIDs)+1)
+	for _, slot := range slots {
+		if !changed[slot.ID] {
+			continue
+		}
+
+		payload, err := json.Marshal(slot)
+		if err != nil {
+			return fmt.Errorf("failed to marshal slot %s: %w", slot.ID, err)
+		}
+
+		messages = append(messages, outbox.Message(SlotTopic(slot.ID), delivery, payload, broker.Properties{}))
+	}
+
+	snapshot, err := json.Marshal(slots)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	messages = append(messages,
+		outbox.Message(config.String("SERVER_STATE_TOPIC", ""), delivery, snapshot, broker.Properties{}),
+	)
+
+	return repos.Outbox.Enqueue(ctx, messages...)
+}
+
+// Republish restores the retained snapshot, the retained state of every slot and the snapshot of every cabinet
+// if enabled, in case the broker has lost them
+func Republish(ctx context.Context, client broker.Broker) error {
+	slots, err := models.Slots(qm.OrderBy(models.SlotColumns.ID)).AllG(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch slots: %w", err)
+	}
+
+	if CabinetsEnabled() {
+		cabinets := make(map[string]models.SlotSlice)
+		for _, slot := range slots {
+			if slot.CabinetID != "" {
+				cabinets[slot.CabinetID] = append(cabinets[slot.CabinetID], slot)
+			}
+		}
+
+		for cabinetID, cabinetSlots := range cabinets {
+			if err := publishCabinet(client, cabinetID, cabinetSlots); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !Enabled() {
+		return nil
+	}
+
+	err = publishSnapshot(client, slots)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, slot := range slots {
+		payload, err := json.Marshal(slot)
+		if err != nil {
+			return fmt.Errorf("failed to marshal slot %s: %w", slot.ID, err)
+		}
+
+		broker.Publish(&wg, client, SlotTopic(slot.ID), broker.DeliveryFor("SERVER_STATE_TOPIC"), string(payload))
+	}
+	wg.Wait()
+
+	return nil
+}
+
+func publishSnapshot(client broker.Broker, slots models.SlotSlice) error {
+	payload, err := json.Marshal(slots)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	broker.Publish(&wg, client, config.String("SERVER_STATE_TOPIC", ""), broker.DeliveryFor("SERVER_STATE_TOPIC"), string(payload))
+
+	return nil
+}
+
+// PublishCabinets refreshes the snapshots of the cabinets the slots belong to, if enabled, the decommissioned slots
+// included
+func PublishCabinets(ctx context.Context, client broker.Broker, slotIDs []string) error {
+	if !CabinetsEnabled() || len(slotIDs) == 0 {
+		return nil
+	}
+
+	changed, err := models.Slots(
+		qm.Select(models.SlotColumns.CabinetID),
+		qm.Distinct(models.SlotColumns.CabinetID),
+		models.SlotWhere.ID.IN(slotIDs),
+		qm.WithDeleted(),
+	).AllG(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch cabinets of slots: %w", err)
+	}
+
+	cabinetIDs := make([]string, 0, len(changed))
+	for _, slot := range changed {
+		cabinetIDs = append(cabinetIDs, slot.CabinetID)
+	}
+
+	return RefreshCabinets(ctx, client, cabinetIDs...)
+}
+
+// RefreshCabinets refreshes the snapshots of the cabinets, if enabled, skipping the empty cabinet IDs
+func RefreshCabinets(ctx context.Context, client broker.Broker, cabinetIDs ...string) error {
+	if !CabinetsEnabled() {
+		return nil
+	}
+
+	for _, cabinetID := range cabinetIDs {
+		if cabinetID == "" {
+			continue
+		}
+
+		slots, err := models.Slots(
+			models.SlotWhere.CabinetID.EQ(cabinetID),
+			qm.OrderBy(models.SlotColumns.ID),
+		).AllG(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to fetch slots of cabinet %s: %w", cabinetID, err)
+		}
+
+		if err := publishCabinet(client, cabinetID, slots); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func publishCabinet(client broker.Broker, cabinetID string, slots models.SlotSlice) error {
+	payload, err := json.Marshal(CabinetSnapshot{CabinetID: cabinetID, Slots: slots, UpdatedAt: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot of cabinet %s: %w", cabinetID, err)
+	}
+
+	var wg sync.WaitGroup
+	broker.Publish(&wg, client, CabinetTopic(cabinetID), broker.DeliveryFor("SERVER_CABINET_STATE_TOPIC"), string(payload))
+
+	return nil
+}
+
+// ReleaseSlot marks the slot as not taken by anybody, closing its open loan, and queues its state along with the
+// correction letting the devices resync with it in the outbox. It fails with storage.ErrStaleSlot when the slot changes
+// while being released
+func ReleaseSlot(ctx context.Context, slotID string) error {
+	correction, err := json.Marshal(types.MQTTMessage{
+		Message: "slot released by admin",
+		RFID:    NobodyRFID,
+		Slots:   slotID,
+		Status:  types.Placed,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal correction: %w", err)
+	}
+
+	tx, err := boil.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	slot, err := models.FindSlot(ctx, tx, slotID)
+	if err != nil {
+		return fmt.Errorf("failed to find slot %s: %w", slotID, err)
+	}
+
+	slot.IsTaken = false
+	slot.TakenBy = NobodyRFID
+
+	repos := storage.NewRepos(tx)
+
+	if err := repos.Slots.Update(ctx, slot, models.SlotColumns.TakenBy, models.SlotColumns.IsTaken); err != nil {
+		return fmt.Errorf("failed to release slot %s: %w", slotID, err)
+	}
+
+	if err := repos.Loans.Close(ctx, time.Now(), slotID); err != nil {
+		return err
+	}
+
+	if err := Queue(ctx, repos, []string{slotID}); err != nil {
+		return err
+	}
+
+	message := outbox.Message(config.String("SERVER_STREAM_TOPIC", ""), broker.DeliveryFor("SERVER_STREAM_TOPIC"),
+		correction, broker.Properties{},
+	)
+	if err := repos.Outbox.Enqueue(ctx, message); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	outbox.Wake()
+
+	return nil
+}
+
+// AnnounceRelease refreshes the retained snapshots of the cabinet of the force-released slot, the devices resyncing
+// with the correction ReleaseSlot queued
+func AnnounceRelease(ctx context.Context, client broker.Broker, slotID string) {
+	if err := PublishCabinets(ctx, client, []string{slotID}); err != nil {
+		log.Error().Err(err).Msg("failed to publish cabinet snapshot")
+	}
+}
+
+// AnnounceDeletion refreshes the retained snapshots the decommissioned slot is left out of, and clears its retained
+// state, so that the devices forget about it
+func AnnounceDeletion(ctx context.Context, client broker.Broker, slotID string) {
+	if Enabled() {
+		if err := PublishSnapshot(ctx, client); err != nil {
+			log.Error().Err(err).Msg("failed to publish snapshot")
+		}
+
+		var wg sync.WaitGroup
+		broker.Publish(&wg, client, SlotTopic(slotID), broker.DeliveryFor("SERVER_STATE_TOPIC"), "")
+	}
+
+	if err := PublishCabinets(ctx, client, []string{slotID}); err != nil {
+		log.Error().Err(err).Msg("failed to publish cabinet snapshot")
+	}
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/strmangle"
+)
+
+// insertAll inserts the rows, pointers to the structs of the table, in a single statement, and returns how many
+// it inserted or updated. On a conflict over the conflict columns the update columns of the existing row are
+// updated where the guard holds, or the row is skipped without any. The rows of an upsert must not conflict with
+// one another, as a statement cannot update a row twice
+func insertAll(ctx context.Context, exec boil.ContextExecutor, table string, rows []interface{},
+	columns, conflict, update []string, guard string,
+) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	typ := reflect.TypeOf(rows[0]).Elem()
+
+	mapping, err := queries.BindMapping(typ, queries.MakeStructMapping(typ), columns)
+	if err != nil {
+		return 0, fmt.Errorf("failed to map columns of %s: %w", table, err)
+	}
+
+	var query strings.Builder
+	fmt.Fprintf(&query, "INSERT INTO %s (%s) VALUES ", strmangle.IdentQuote('"', '"', table),
+		strings.Join(strmangle.IdentQuoteSlice('"', '"', columns), ", "),
+	)
+
+	args := make([]interface{}, 0, len(rows)*len(columns))
+	for i, row := range rows {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		fmt.Fprintf(&query, "(%s)", strmangle.Placeholders(true, len(columns), len(args)+1, 1))
+
+		args = append(args, queries.ValuesFromMapping(reflect.ValueOf(row).Elem(), mapping)...)
+	}
+
+	if len(conflict) > 0 {
+		fmt.Fprintf(&query, " ON CONFLICT (%s)", strings.Join(strmangle.IdentQuoteSlice('"', '"', conflict), ", "))
+
+		if len(update) == 0 {
+			query.WriteString(" DO NOTHING")
+		} else {
+			set := make([]string, 0, len(update))
+			for _, column := range strmangle.IdentQuoteSlice('"', '"', update) {
+				set = append(set, fmt.Sprintf("%s = EXCLUDED.%s", column, column))
+			}
+
+			fmt.Fprintf(&query, " DO UPDATE SET %s", strings.Join(set, ", "))
+
+			if guard != "" {
+				fmt.Fprintf(&query, " WHERE %s", guard)
+			}
+		}
+	}
+
+	result, err := exec.ExecContext(ctx, query.String(), args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert into %s: %w", table, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows inserted into %s: %w", table, err)
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/broker"
+	"letovo-computers-server/config"
+	"letovo-computers-server/metrics"
+)
+
+// Breaker pauses the handlers storing messages while the db is down, rather than having every message fail
+// and be dead-lettered until someone restarts the server. It opens once DB_BREAKER_FAILURES pings in a row fail,
+// or right away when a transaction loses its connection, and closes once a ping succeeds again
+type Breaker struct {
+	db *sql.DB
+
+	mu sync.Mutex
+	// up is closed while the db is up, and replaced by an open channel while it is down
+	up   chan struct{}
+	down bool
+}
+
+func newBreaker(db *sql.DB) *Breaker {
+	up := make(chan struct{})
+	close(up)
+
+	metrics.DBUp.Set(1)
+
+	return &Breaker{db: db, up: up}
+}
+
+// Up reports whether the db is believed to be up
+func (b *Breaker) Up() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return !b.down
+}
+
+// Trip opens the breaker, the db having failed with the error
+func (b *Breaker) Trip(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.down {
+		return
+	}
+
+	log.Error().Err(err).Msg("db is down, pausing the message handlers until it is back")
+	metrics.DBUp.Set(0)
+
+	b.down = true
+	b.up = make(chan struct{})
+}
+
+func (b *Breaker) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.down {
+		return
+	}
+
+	log.Info().Msg("db is back, resuming the message handlers")
+	metrics.DBUp.Set(1)
+
+	b.down = false
+	close(b.up)
+}
+
+// Wait blocks while the breaker is open, until the db is back or ctx is done
+func (b *Breaker) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	up := b.up
+	b.mu.Unlock()
+
+	select {
+	case <-up:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run pings the db every DB_HEALTH_INTERVAL, or every DB_BREAKER_PROBE_INTERVAL while it is down, until ctx is done
+func (b *Breaker) Run(ctx context.Context) {
+	failures := 0
+
+	for {
+		interval := config.Duration("DB_HEALTH_INTERVAL", 5*time.Second)
+		if !b.Up() {
+			interval = config.Duration("DB_BREAKER_PROBE_INTERVAL", time.Second)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, config.Duration("DB_PING_TIMEOUT", 2*time.Second))
+		err := b.db.PingContext(pingCtx)
+		cancel()
+
+		if err == nil {
+			failures = 0
+			b.reset()
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		failures++
+		log.Warn().Err(err).Int("failures", failures).Msg("failed to ping db")
+
+		if failures >= config.Int("DB_BREAKER_FAILURES", 2) {
+			b.Trip(err)
+		}
+	}
+}
+
+// Middleware holds the messages of the route while the breaker is open. Running on the workers, it lets their
+// queues fill up and then blocks the client, which stops taking messages from the broker until the db is back.
+// Messages still held once ctx is done are left unacknowledged, for the broker to redeliver them
+func (b *Breaker) Middleware(ctx context.Context) broker.Middleware {
+	return func(route string, next mqtt.MessageHandler) mqtt.MessageHandler {
+		return func(client mqtt.Client, msg mqtt.Message) {
+			if !b.Up() {
+				log.Debug().Str("route", route).Str("topic", msg.Topic()).Msg("holding message until the db is back")
+			}
+
+			if err := b.Wait(ctx); err != nil {
+				log.Warn().Str("route", route).Str("topic", msg.Topic()).Msg("abandoned message held while the db was down")
+				return
+			}
+
+			next(client, msg)
+		}
+	}
+}
+
+// connectionLost reports whether the error is the db connection failing rather than the query
+func connectionLost(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// the connection exceptions, and the server shutting down
+		return strings.HasPrefix(pgErr.Code, "08") || pgErr.Code == "57P01" || pgErr.Code == "57P02" || pgErr.Code == "57P03"
+	}
+
+	// pgx tells the errors failing a query before anything was sent, like failing to connect, as safe to retry
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) || pgconn.SafeToRetry(err) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.ErrUnexpectedEOF)
+}
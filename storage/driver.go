@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	// the pure Go driver keeps the builds free of cgo
+	_ "modernc.org/sqlite"
+
+	"letovo-computers-server/config"
+)
+
+// Driver is the db backend the store runs on, selected by DB_DRIVER
+type Driver string
+
+const (
+	// Postgres is the backend of the deployments, connected to with the PG* settings
+	Postgres Driver = "postgres"
+	// SQLite keeps the db in the SQLITE_PATH file, for development and the single-cabinet kiosks
+	SQLite Driver = "sqlite"
+)
+
+// ErrUnknownDriver is returned by Open for DB_DRIVER other than postgres and sqlite
+var ErrUnknownDriver = errors.New("unknown db driver")
+
+// Open opens the db of the DB_DRIVER backend, postgres unless set. Postgres is connected to through pgx, which
+// prepares the statements once per connection and caches up to DB_STATEMENT_CACHE_SIZE of them
+func Open() (*sql.DB, Driver, error) {
+	driver := Driver(config.String("DB_DRIVER", string(Postgres)))
+
+	switch driver {
+	case Postgres:
+		cfg, err := pgx.ParseConfig(PostgresDSN())
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse postgres config: %w", err)
+		}
+
+		cfg.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
+		cfg.StatementCacheCapacity = config.Int("DB_STATEMENT_CACHE_SIZE", 512)
+
+		return stdlib.OpenDB(*cfg), driver, nil
+	case SQLite:
+		// the times are written in a format sqlite can compare and the driver parses back
+		query := url.Values{
+			"_pragma":      {"foreign_keys(1)", "journal_mode(WAL)", "busy_timeout(5000)"},
+			"_time_format": {"sqlite"},
+		}
+
+		db, err := sql.Open(string(driver), "file:"+config.String("SQLITE_PATH", "letovo.db")+"?"+query.Encode())
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to open sqlite db: %w", err)
+		}
+
+		return db, driver, nil
+	default:
+		return nil, "", fmt.Errorf("%w %q, expected %s or %s", ErrUnknownDriver, driver, Postgres, SQLite)
+	}
+}
+
+// PostgresDSN returns the connection string of the postgres db, naming the connections after ApplicationName
+func PostgresDSN() string {
+	return fmt.Sprintf(
+		"postgres://%s:%s@%s:%s/%s?sslmode=%s&application_name=%s",
+		os.Getenv("PGUSER"), os.Getenv("PGPASSWORD"), os.Getenv("PGHOST"),
+		os.Getenv("PGPORT"), os.Getenv("PGDATABASE"), os.Getenv("PGSSLMODE"), url.QueryEscape(ApplicationName()),
+	)
+}
+
+// ApplicationPrefix starts the application names of the connections of every instance
+const ApplicationPrefix = "letovo-computers-server/"
+
+// ApplicationName names the connections of the instance, so that the changes it makes are told apart from the
+// ones of the other instances and of the tools editing the db by hand
+func ApplicationName() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return ApplicationPrefix + host
+}
+
+// TryLock takes the advisory lock of the key for the rest of the transaction, reporting whether no other instance
+// holds it. Only postgres is shared by several instances, a sqlite db being a file served by the one instance
+// opening it, so the lock is always taken there
+func TryLock(ctx context.Context, tx boil.ContextExecutor, driver Driver, key string) (bool, error) {
+	if driver != Postgres {
+		return true, nil
+	}
+
+	var locked bool
+	err := tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock(hashtext($1))", key).Scan(&locked)
+	if err != nil {
+		return false, fmt.Errorf("failed to take advisory lock %s: %w", key, err)
+	}
+
+	return locked, nil
+}
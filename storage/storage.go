@@ -0,0 +1,593 @@
+// Package storage persists the slots, users, events and device presence the message handlers change, behind
+// interfaces the handlers can be tested against with mocks. The repositories run on the executor they are given,
+// the db or a transaction, rather than on the global db of sqlboiler. The db is Postgres or, for development and
+// the kiosks, SQLite, selected by DB_DRIVER
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog/log"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"letovo-computers-server/config"
+	"letovo-computers-server/metrics"
+	"letovo-computers-server/models"
+	"letovo-computers-server/presence"
+	"letovo-computers-server/replay"
+	"letovo-computers-server/types"
+)
+
+// ErrStaleSlot is returned by the updates of the slots changed since their version was read
+var ErrStaleSlot = errors.New("slot changed concurrently")
+
+// ErrDeletedSlot is returned by the upserts of the slots decommissioned, which the devices are not to report anymore
+var ErrDeletedSlot = errors.New("slot is decommissioned")
+
+// ErrLoanLimit is returned by the loans taken by a user already holding the MAX_LOANS_PER_USER laptops
+var ErrLoanLimit = errors.New("too many laptops taken")
+
+// seatIndex is the unique index of the seats of the open loans, violated by the loans opened at the same time
+const seatIndex = "loans_open_seat_idx"
+
+// SlotRepo stores the slots, every update bumping their version. An update read against an older version than
+// the one stored fails with ErrStaleSlot, so that concurrent updates of a slot cannot interleave
+type SlotRepo interface {
+	// Upsert inserts the slot or updates the columns of the existing one
+	Upsert(ctx context.Context, slot *models.Slot, columns ...string) error
+	// UpsertAll upserts the slots like Upsert does, all of them in a single statement
+	UpsertAll(ctx context.Context, slots models.SlotSlice, columns ...string) error
+	// Update updates the columns of the slot as it was read, at its version
+	Update(ctx context.Context, slot *models.Slot, columns ...string) error
+	// All returns the slots in the order of their IDs, leaving out the decommissioned ones
+	All(ctx context.Context) (models.SlotSlice, error)
+}
+
+// UserRepo stores the users
+type UserRepo interface {
+	// Upsert inserts the user or updates the columns of the existing one, leaving it as it is without any
+	Upsert(ctx context.Context, user *models.User, columns ...string) error
+}
+
+// EventRepo logs the events
+type EventRepo interface {
+	Insert(ctx context.Context, event *models.Event) error
+	// InsertAll logs the events in a single statement, leaving their IDs unset
+	InsertAll(ctx context.Context, events models.EventSlice) error
+}
+
+// DeviceRepo records the presence of the devices
+type DeviceRepo interface {
+	// Offline records that the device disconnected at the time
+	Offline(ctx context.Context, device string, at time.Time) error
+	// Sequence records the sequence number of the message stored along with it, failing with replay.ErrDuplicate
+	// for the ones already recorded, see replay.Record
+	Sequence(ctx context.Context, device string, seq uint64) error
+}
+
+// LaptopRepo tracks the laptops stored in the slots
+type LaptopRepo interface {
+	// HomedAt maps the slots to the asset tags of the laptops stored in them, leaving out the slots without one
+	HomedAt(ctx context.Context, slotIDs ...string) (map[string]string, error)
+	// Track marks the laptops as in use once taken and as available once returned, leaving the laptops under
+	// repair and the retired ones as they are
+	Track(ctx context.Context, taken bool, assetTags ...string) error
+}
+
+// LoanRepo keeps the loans of the laptops checked out of the slots
+type LoanRepo interface {
+	// Open checks the slots out, first closing the loans of the slots left open, like when their return was missed.
+	// It fails with ErrLoanLimit for the users left holding more than MAX_LOANS_PER_USER laptops, a single one unless
+	// set, a zero limit disabling it. Every slot of a message counts towards the limit, so a message reporting more
+	// slots than it allows is rejected as a whole, and the devices reporting several slots taken at once need the
+	// limit raised to match
+	Open(ctx context.Context, loans ...*models.Loan) error
+	// Close closes the open loans of the slots, if any, as returned at the time
+	Close(ctx context.Context, at time.Time, slotIDs ...string) error
+}
+
+// OutboxRepo queues the messages to publish once the transaction queuing them commits, see package outbox
+type OutboxRepo interface {
+	// Enqueue queues the messages in a single statement, skipping the nil ones
+	Enqueue(ctx context.Context, messages ...*models.OutboxMessage) error
+}
+
+// Repos are the repositories running on the same executor
+type Repos struct {
+	Slots   SlotRepo
+	Users   UserRepo
+	Events  EventRepo
+	Devices DeviceRepo
+	Laptops LaptopRepo
+	Loans   LoanRepo
+	Outbox  OutboxRepo
+}
+
+// NewRepos creates the sqlboiler repositories running on the executor
+func NewRepos(exec boil.ContextExecutor) Repos {
+	return Repos{
+		Slots:   slotRepo{exec: exec},
+		Users:   userRepo{exec: exec},
+		Events:  eventRepo{exec: exec},
+		Devices: deviceRepo{exec: exec},
+		Laptops: laptopRepo{exec: exec},
+		Loans:   loanRepo{exec: exec},
+		Outbox:  outboxRepo{exec: exec},
+	}
+}
+
+// Store hands out the repositories, either running on the db directly or inside a transaction
+type Store interface {
+	// Repos returns the repositories running on the db, each call on its own
+	Repos() Repos
+	// InTx runs fn with the repositories running inside a transaction, committed unless fn fails
+	InTx(ctx context.Context, fn func(repos Repos) error) error
+}
+
+// SQLStore is the Store backed by the db
+type SQLStore struct {
+	db      *sql.DB
+	breaker *Breaker
+}
+
+// New creates the store backed by the db, along with the breaker watching it
+func New(db *sql.DB) *SQLStore {
+	return &SQLStore{db: db, breaker: newBreaker(db)}
+}
+
+// Breaker returns the breaker tripped by the transactions losing their connection to the db
+func (s *SQLStore) Breaker() *Breaker {
+	return s.breaker
+}
+
+// Repos returns the repositories running on the db
+func (s *SQLStore) Repos() Repos {
+	return NewRepos(s.db)
+}
+
+// InTx runs fn inside a transaction, committing it unless fn fails, so that a message is stored either as a whole
+// or not at all. Transactions failing with a transient error, like a serialization failure, a deadlock, a stale
+// slot, a locked sqlite db or a dropped connection, are run again from scratch up to DB_TX_ATTEMPTS times, backing
+// off from DB_TX_BACKOFF doubling each time. A lost connection trips the breaker, and the retries wait for the db
+// to be back
+func (s *SQLStore) InTx(ctx context.Context, fn func(repos Repos) error) error {
+	attempts := config.Int("DB_TX_ATTEMPTS", 3)
+	backoff := config.Duration("DB_TX_BACKOFF", 50*time.Millisecond)
+
+	for attempt := 1; ; attempt++ {
+		err := s.runTx(ctx, fn)
+		if err == nil || !transient(err) {
+			return err
+		}
+
+		if connectionLost(err) {
+			s.breaker.Trip(err)
+		}
+
+		if attempt >= attempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		log.Warn().Err(err).Int("attempt", attempt).Dur("backoff", backoff).Msg("transaction failed, retrying")
+		metrics.TxRetries.Inc()
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		// with the db down the retry would only fail as well, so it waits for the db to be back
+		if s.breaker.Wait(ctx) != nil {
+			return err
+		}
+
+		backoff *= 2
+	}
+}
+
+func (s *SQLStore) runTx(ctx context.Context, fn func(repos Repos) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(NewRepos(tx)); err != nil {
+		if err := tx.Rollback(); err != nil {
+			log.Error().Err(err).Msg("failed to rollback transaction")
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// transient reports whether the transaction failed for a reason a retry may not run into again
+func transient(err error) bool {
+	if errors.Is(err, ErrStaleSlot) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// serialization_failure and deadlock_detected
+		if pgErr.Code == "40001" || pgErr.Code == "40P01" {
+			return true
+		}
+
+		// unique_violation of a seat taken by a loan opened at the same time, which the retry numbers past
+		if pgErr.Code == "23505" && pgErr.ConstraintName == seatIndex {
+			return true
+		}
+
+		// a statement prepared before a migration changed its table, which pgx drops from its cache on failing
+		if pgErr.Code == "0A000" && strings.Contains(pgErr.Message, "cached plan must not change result type") {
+			return true
+		}
+	}
+
+	var liteErr *sqlite.Error
+	if errors.As(err, &liteErr) {
+		// another connection holding the lock past the busy timeout
+		code := liteErr.Code() & 0xff
+		return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+	}
+
+	return connectionLost(err)
+}
+
+type slotRepo struct {
+	exec boil.ContextExecutor
+}
+
+func (r slotRepo) Upsert(ctx context.Context, slot *models.Slot, columns ...string) error {
+	return r.UpsertAll(ctx, models.SlotSlice{slot}, columns...)
+}
+
+// UpsertAll upserts the slots in the order of their IDs, so that the transactions upserting the same slots
+// lock them in the same order rather than deadlocking. The versions of the slots are read first, and the slots
+// changed by another transaction in the meantime are left as they are, failing the upsert. The decommissioned slots
+// fail it with ErrDeletedSlot
+func (r slotRepo) UpsertAll(ctx context.Context, slots models.SlotSlice, columns ...string) error {
+	sorted := make(models.SlotSlice, len(slots))
+	copy(sorted, slots)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	ids := make([]string, len(sorted))
+	for i, slot := range sorted {
+		ids[i] = slot.ID
+	}
+
+	stored, err := models.Slots(
+		qm.Select(models.SlotColumns.ID, models.SlotColumns.Version, models.SlotColumns.DeletedAt),
+		models.SlotWhere.ID.IN(ids),
+		qm.WithDeleted(),
+	).All(ctx, r.exec)
+	if err != nil {
+		return fmt.Errorf("failed to fetch versions of slots: %w", err)
+	}
+
+	versions := make(map[string]int64, len(stored))
+	for _, slot := range stored {
+		if slot.DeletedAt.Valid {
+			return fmt.Errorf("%w: %s", ErrDeletedSlot, slot.ID)
+		}
+		versions[slot.ID] = slot.Version
+	}
+
+	now := time.Now()
+	rows := make([]interface{}, len(sorted))
+	for i, slot := range sorted {
+		slot.Version = versions[slot.ID] + 1
+		slot.UpdatedAt = now
+		rows[i] = slot
+	}
+
+	columns = append(columns, models.SlotColumns.Version, models.SlotColumns.UpdatedAt)
+	guard := fmt.Sprintf("%s.%s = EXCLUDED.%s - 1", models.TableNames.Slots, models.SlotColumns.Version, models.SlotColumns.Version)
+
+	upserted, err := insertAll(ctx, r.exec, models.TableNames.Slots, rows,
+		append([]string{models.SlotColumns.ID}, columns...), []string{models.SlotColumns.ID}, columns, guard,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert %d slots: %w", len(slots), err)
+	}
+
+	if upserted < int64(len(rows)) {
+		metrics.StaleSlots.Add(float64(int64(len(rows)) - upserted))
+		return fmt.Errorf("%w: %d of %d slots", ErrStaleSlot, int64(len(rows))-upserted, len(rows))
+	}
+
+	return nil
+}
+
+func (r slotRepo) All(ctx context.Context) (models.SlotSlice, error) {
+	slots, err := models.Slots(qm.OrderBy(models.SlotColumns.ID)).All(ctx, r.exec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch slots: %w", err)
+	}
+
+	return slots, nil
+}
+
+func (r slotRepo) Update(ctx context.Context, slot *models.Slot, columns ...string) error {
+	set := models.M{
+		models.SlotColumns.Version:   slot.Version + 1,
+		models.SlotColumns.UpdatedAt: time.Now(),
+	}
+
+	values := reflect.ValueOf(slot).Elem()
+	mapping, err := queries.BindMapping(values.Type(), queries.MakeStructMapping(values.Type()), columns)
+	if err != nil {
+		return fmt.Errorf("failed to map columns of slot %s: %w", slot.ID, err)
+	}
+	for i, value := range queries.ValuesFromMapping(values, mapping) {
+		set[columns[i]] = value
+	}
+
+	updated, err := models.Slots(
+		models.SlotWhere.ID.EQ(slot.ID),
+		models.SlotWhere.Version.EQ(slot.Version),
+	).UpdateAll(ctx, r.exec, set)
+	if err != nil {
+		return fmt.Errorf("failed to update slot %s: %w", slot.ID, err)
+	}
+
+	if updated == 0 {
+		metrics.StaleSlots.Inc()
+		return fmt.Errorf("%w: %s", ErrStaleSlot, slot.ID)
+	}
+
+	slot.Version++
+	slot.UpdatedAt = set[models.SlotColumns.UpdatedAt].(time.Time)
+
+	return nil
+}
+
+type userRepo struct {
+	exec boil.ContextExecutor
+}
+
+func (r userRepo) Upsert(ctx context.Context, user *models.User, columns ...string) error {
+	if len(columns) > 0 {
+		columns = append(columns, models.UserColumns.UpdatedAt)
+	}
+
+	err := user.Upsert(ctx, r.exec, len(columns) > 0, []string{models.UserColumns.ID},
+		boil.Whitelist(columns...), boil.Infer(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert user %s: %w", user.ID, err)
+	}
+
+	return nil
+}
+
+type eventRepo struct {
+	exec boil.ContextExecutor
+}
+
+func (r eventRepo) Insert(ctx context.Context, event *models.Event) error {
+	if err := event.Insert(ctx, r.exec, boil.Infer()); err != nil {
+		return fmt.Errorf("failed to log event: %w", err)
+	}
+
+	return nil
+}
+
+var eventColumns = []string{
+	models.EventColumns.ReceivedAt, models.EventColumns.Device, models.EventColumns.Rfid, models.EventColumns.Slot,
+	models.EventColumns.Status, models.EventColumns.Result, models.EventColumns.Payload, models.EventColumns.Laptop,
+}
+
+func (r eventRepo) InsertAll(ctx context.Context, events models.EventSlice) error {
+	rows := make([]interface{}, len(events))
+	for i, event := range events {
+		rows[i] = event
+	}
+
+	if _, err := insertAll(ctx, r.exec, models.TableNames.Events, rows, eventColumns, nil, nil, ""); err != nil {
+		return fmt.Errorf("failed to log %d events: %w", len(events), err)
+	}
+
+	return nil
+}
+
+type deviceRepo struct {
+	exec boil.ContextExecutor
+}
+
+func (r deviceRepo) Offline(ctx context.Context, device string, at time.Time) error {
+	return presence.Offline(ctx, r.exec, device, at)
+}
+
+func (r deviceRepo) Sequence(ctx context.Context, device string, seq uint64) error {
+	return replay.Record(ctx, r.exec, device, seq)
+}
+
+type laptopRepo struct {
+	exec boil.ContextExecutor
+}
+
+func (r laptopRepo) HomedAt(ctx context.Context, slotIDs ...string) (map[string]string, error) {
+	if len(slotIDs) == 0 {
+		return nil, nil
+	}
+
+	laptops, err := models.Laptops(
+		qm.Select(models.LaptopColumns.AssetTag, models.LaptopColumns.HomeSlot),
+		models.LaptopWhere.HomeSlot.IN(slotIDs),
+	).All(ctx, r.exec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch laptops of slots: %w", err)
+	}
+
+	homed := make(map[string]string, len(laptops))
+	for _, laptop := range laptops {
+		homed[laptop.HomeSlot.String] = laptop.AssetTag
+	}
+
+	return homed, nil
+}
+
+func (r laptopRepo) Track(ctx context.Context, taken bool, assetTags ...string) error {
+	if len(assetTags) == 0 {
+		return nil
+	}
+
+	status := types.LaptopAvailable
+	if taken {
+		status = types.LaptopInUse
+	}
+
+	_, err := models.Laptops(
+		models.LaptopWhere.AssetTag.IN(assetTags),
+		models.LaptopWhere.Status.IN([]string{types.LaptopAvailable, types.LaptopInUse}),
+	).UpdateAll(ctx, r.exec, models.M{
+		models.LaptopColumns.Status:    status,
+		models.LaptopColumns.UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to track laptops: %w", err)
+	}
+
+	return nil
+}
+
+type loanRepo struct {
+	exec boil.ContextExecutor
+}
+
+var loanColumns = []string{
+	models.LoanColumns.Rfid, models.LoanColumns.Slot, models.LoanColumns.Laptop,
+	models.LoanColumns.TakenAt, models.LoanColumns.DueAt, models.LoanColumns.Seat,
+}
+
+func (r loanRepo) Open(ctx context.Context, loans ...*models.Loan) error {
+	if len(loans) == 0 {
+		return nil
+	}
+
+	slotIDs := make([]string, len(loans))
+	rows := make([]interface{}, len(loans))
+	for i, loan := range loans {
+		slotIDs[i] = loan.Slot
+		rows[i] = loan
+	}
+
+	if err := r.Close(ctx, loans[0].TakenAt, slotIDs...); err != nil {
+		return err
+	}
+
+	if err := r.seat(ctx, loans); err != nil {
+		return err
+	}
+
+	if _, err := insertAll(ctx, r.exec, models.TableNames.Loans, rows, loanColumns, nil, nil, ""); err != nil {
+		return fmt.Errorf("failed to open %d loans: %w", len(loans), err)
+	}
+
+	return nil
+}
+
+// seat numbers the loans with the lowest seats their users have free, failing with ErrLoanLimit once past the
+// MAX_LOANS_PER_USER
+func (r loanRepo) seat(ctx context.Context, loans []*models.Loan) error {
+	rfids := make([]string, 0, len(loans))
+	for _, loan := range loans {
+		rfids = append(rfids, loan.Rfid)
+	}
+
+	held, err := models.Loans(
+		qm.Select(models.LoanColumns.Rfid, models.LoanColumns.Seat),
+		models.LoanWhere.Rfid.IN(rfids),
+		models.LoanWhere.ReturnedAt.IsNull(),
+	).All(ctx, r.exec)
+	if err != nil {
+		return fmt.Errorf("failed to fetch open loans: %w", err)
+	}
+
+	taken := make(map[string]map[int16]bool)
+	for _, loan := range append(held, loans...) {
+		if taken[loan.Rfid] == nil {
+			taken[loan.Rfid] = make(map[int16]bool)
+		}
+
+		if loan.Seat == 0 {
+			loan.Seat = 1
+			for taken[loan.Rfid][loan.Seat] {
+				loan.Seat++
+			}
+		}
+		taken[loan.Rfid][loan.Seat] = true
+
+		if limit := config.Int("MAX_LOANS_PER_USER", 1); limit > 0 && int(loan.Seat) > limit {
+			return fmt.Errorf("%w: %s would hold %d, max %d", ErrLoanLimit, loan.Rfid, len(taken[loan.Rfid]), limit)
+		}
+	}
+
+	return nil
+}
+
+func (r loanRepo) Close(ctx context.Context, at time.Time, slotIDs ...string) error {
+	if len(slotIDs) == 0 {
+		return nil
+	}
+
+	_, err := models.Loans(
+		models.LoanWhere.Slot.IN(slotIDs),
+		models.LoanWhere.ReturnedAt.IsNull(),
+	).UpdateAll(ctx, r.exec, models.M{models.LoanColumns.ReturnedAt: at})
+	if err != nil {
+		return fmt.Errorf("failed to close loans of slots: %w", err)
+	}
+
+	return nil
+}
+
+type outboxRepo struct {
+	exec boil.ContextExecutor
+}
+
+var outboxColumns = []string{
+	models.OutboxMessageColumns.Topic, models.OutboxMessageColumns.Qos, models.OutboxMessageColumns.Retained,
+	models.OutboxMessageColumns.Payload, models.OutboxMessageColumns.ContentType,
+	models.OutboxMessageColumns.CorrelationData,
+}
+
+func (r outboxRepo) Enqueue(ctx context.Context, messages ...*models.OutboxMessage) error {
+	rows := make([]interface{}, 0, len(messages))
+	for _, message := range messages {
+		if message == nil {
+			continue
+		}
+
+		// the columns are not null, an empty payload or correlation being stored as such
+		if message.Payload == nil {
+			message.Payload = []byte{}
+		}
+		if message.CorrelationData == nil {
+			message.CorrelationData = []byte{}
+		}
+		rows = append(rows, message)
+	}
+
+	if _, err := insertAll(ctx, r.exec, models.TableNames.OutboxMessages, rows, outboxColumns, nil, nil, ""); err != nil {
+		return fmt.Errorf("failed to queue %d messages: %w", len(rows), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+
+	"letovo-computers-server/models"
+	"letovo-computers-server/replay"
+	"letovo-computers-server/storage"
+	"letovo-computers-server/storage/storagetest"
+)
+
+// the user and the slot of the tests running on postgres as well, unlikely to clash with the real ones
+const (
+	testRFID = "storagetest"
+	testSlot = "ZT1"
+)
+
+// drivers runs the test on a fresh sqlite db and, if configured, on postgres, see storagetest.Postgres
+func drivers(t *testing.T, test func(t *testing.T, db *sql.DB)) {
+	t.Run(string(storage.SQLite), func(t *testing.T) { test(t, storagetest.New(t)) })
+	t.Run(string(storage.Postgres), func(t *testing.T) { test(t, storagetest.Postgres(t)) })
+}
+
+// seed inserts the user and the slots taken by nobody yet, purging them once the test ends
+func seed(t *testing.T, db *sql.DB, rfid string, slotIDs ...string) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	purge := func() {
+		_, _ = models.Loans(models.LoanWhere.Rfid.EQ(rfid)).DeleteAll(ctx, db)
+		_, _ = models.Laptops(models.LaptopWhere.HomeSlot.IN(slotIDs)).DeleteAll(ctx, db)
+		_, _ = models.Slots(models.SlotWhere.ID.IN(slotIDs), qm.WithDeleted()).DeleteAll(ctx, db, true)
+		_, _ = models.Users(models.UserWhere.ID.EQ(rfid), qm.WithDeleted()).DeleteAll(ctx, db, true)
+	}
+	purge()
+	t.Cleanup(purge)
+
+	user := &models.User{ID: rfid}
+	if err := user.Upsert(ctx, db, false, []string{models.UserColumns.ID}, boil.None(), boil.Infer()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, slotID := range slotIDs {
+		if err := (&models.Slot{ID: slotID, TakenBy: rfid}).Insert(ctx, db, boil.Infer()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoanLimit(t *testing.T) {
+	now := time.Now()
+	loan := func(slotID string) *models.Loan {
+		return &models.Loan{Rfid: "0001", Slot: slotID, TakenAt: now, DueAt: now.Add(time.Hour)}
+	}
+
+	tests := []struct {
+		name  string
+		limit string
+		err   error
+	}{
+		{name: "one by default", err: storage.ErrLoanLimit},
+		{name: "unlimited", limit: "0"},
+		{name: "within the limit", limit: "2"},
+		{name: "past the limit", limit: "1", err: storage.ErrLoanLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := storagetest.New(t)
+			t.Setenv("MAX_LOANS_PER_USER", tt.limit)
+			seed(t, db, "0001", "A1", "A2")
+
+			// a single message taking both slots
+			err := storage.NewRepos(db).Loans.Open(context.Background(), loan("A1"), loan("A2"))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Open() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestLoanSeats(t *testing.T) {
+	db := storagetest.New(t)
+	t.Setenv("MAX_LOANS_PER_USER", "2")
+	seed(t, db, "0001", "A1", "A2", "A3")
+
+	ctx := context.Background()
+	repos := storage.NewRepos(db)
+	now := time.Now()
+	loan := func(slotID string) *models.Loan {
+		return &models.Loan{Rfid: "0001", Slot: slotID, TakenAt: now, DueAt: now.Add(time.Hour)}
+	}
+
+	if err := repos.Loans.Open(ctx, loan("A1"), loan("A2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Loans.Open(ctx, loan("A3")); !errors.Is(err, storage.ErrLoanLimit) {
+		t.Fatalf("Open() past the limit error = %v, want %v", err, storage.ErrLoanLimit)
+	}
+
+	// the seat freed by the return is the one taken next
+	if err := repos.Loans.Close(ctx, now, "A1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repos.Loans.Open(ctx, loan("A3")); err != nil {
+		t.Fatal(err)
+	}
+
+	open, err := models.Loans(models.LoanWhere.Slot.EQ("A3"), models.LoanWhere.ReturnedAt.IsNull()).OneG(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if open.Seat != 1 {
+		t.Errorf("seat = %d, want 1", open.Seat)
+	}
+}
+
+func TestUpsertSlotTwice(t *testing.T) {
+	drivers(t, func(t *testing.T, db *sql.DB) {
+		seed(t, db, testRFID, testSlot)
+
+		ctx := context.Background()
+		slots := storage.NewRepos(db).Slots
+
+		for version := int64(1); version <= 2; version++ {
+			slot := &models.Slot{ID: testSlot, TakenBy: testRFID, IsTaken: version == 1}
+
+			if err := slots.UpsertAll(ctx, models.SlotSlice{slot}, models.SlotColumns.TakenBy, models.SlotColumns.IsTaken); err != nil {
+				t.Fatalf("UpsertAll() #%d error = %v", version, err)
+			}
+
+			stored, err := models.FindSlot(ctx, db, testSlot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.ID != testSlot {
+				t.Errorf("stored ID = %q, want %q unpadded", stored.ID, testSlot)
+			}
+			if stored.IsTaken != slot.IsTaken || stored.Version <= 0 || slot.Version != stored.Version {
+				t.Errorf("slot after upsert #%d = taken %t at version %d, want taken %t at version %d",
+					version, stored.IsTaken, stored.Version, slot.IsTaken, slot.Version)
+			}
+		}
+	})
+}
+
+func TestHomedAt(t *testing.T) {
+	drivers(t, func(t *testing.T, db *sql.DB) {
+		seed(t, db, testRFID, testSlot)
+
+		ctx := context.Background()
+		laptop := &models.Laptop{AssetTag: "storagetest-1", HomeSlot: null.StringFrom(testSlot), Status: "available"}
+		if err := laptop.Insert(ctx, db, boil.Infer()); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _, _ = laptop.Delete(ctx, db) })
+
+		homed, err := storage.NewRepos(db).Laptops.HomedAt(ctx, testSlot, "ZT2")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(homed) != 1 || homed[testSlot] != laptop.AssetTag {
+			t.Errorf("HomedAt() = %q, want %s homed at %s alone", homed, laptop.AssetTag, testSlot)
+		}
+	})
+}
+
+func TestSequence(t *testing.T) {
+	drivers(t, func(t *testing.T, db *sql.DB) {
+		ctx := context.Background()
+		purge := func() { _, _ = models.Devices(models.DeviceWhere.ID.EQ(testRFID)).DeleteAll(ctx, db) }
+		purge()
+		t.Cleanup(purge)
+
+		store := storage.New(db)
+		failed := errors.New("failed to store")
+
+		// the sequence number of a message failing to be stored is rolled back along with it
+		err := store.InTx(ctx, func(repos storage.Repos) error {
+			if err := repos.Devices.Sequence(ctx, testRFID, 5); err != nil {
+				return err
+			}
+
+			return failed
+		})
+		if !errors.Is(err, failed) {
+			t.Fatalf("InTx() error = %v, want %v", err, failed)
+		}
+
+		tests := []struct {
+			seq uint64
+			err error
+		}{
+			{seq: 5},
+			{seq: 5, err: replay.ErrDuplicate},
+			{seq: 4, err: replay.ErrDuplicate},
+			{seq: 6},
+		}
+
+		for _, tt := range tests {
+			err := store.InTx(ctx, func(repos storage.Repos) error {
+				return repos.Devices.Sequence(ctx, testRFID, tt.seq)
+			})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Sequence(%d) error = %v, want %v", tt.seq, err, tt.err)
+			}
+		}
+	})
+}
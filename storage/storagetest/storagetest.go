@@ -0,0 +1,58 @@
+// Package storagetest provides migrated dbs for running the stores and the handlers against in the tests
+package storagetest
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/volatiletech/sqlboiler/v4/boil"
+
+	"letovo-computers-server/migrations"
+	"letovo-computers-server/storage"
+)
+
+// New migrates a fresh sqlite db for the test and makes it the global db of sqlboiler
+func New(t testing.TB) *sql.DB {
+	t.Helper()
+
+	t.Setenv("DB_DRIVER", string(storage.SQLite))
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "test.db"))
+
+	return migrate(t)
+}
+
+// Postgres migrates the postgres db configured by the PG* variables and makes it the global db of sqlboiler,
+// skipping the test without one. The db is shared, so the test is to purge the rows it creates
+func Postgres(t testing.TB) *sql.DB {
+	t.Helper()
+
+	if os.Getenv("PGHOST") == "" {
+		t.Skip("PGHOST is not set")
+	}
+	t.Setenv("DB_DRIVER", string(storage.Postgres))
+
+	return migrate(t)
+}
+
+func migrate(t testing.TB) *sql.DB {
+	t.Helper()
+
+	db, driver, err := storage.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	if err := migrations.Select(string(driver)); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrations.Up(db); err != nil {
+		t.Fatal(err)
+	}
+
+	boil.SetDB(db)
+
+	return db
+}
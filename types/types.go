@@ -1,5 +1,14 @@
 package types
 
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"letovo-computers-server/config"
+)
+
 type Status int
 
 const (
@@ -25,8 +34,207 @@ func (s Status) String() string {
 }
 
 type MQTTMessage struct {
+	// ID is set by the firmware to match the Ack of the message
+	ID      string `json:"id,omitempty"`
 	Message string `json:"message"`
+	Device  string `json:"device"`
 	RFID    string `json:"RFID"`
+	Login   string `json:"login"`
 	Slots   string `json:"slots"`
 	Status  Status `json:"status"`
+	// Seq is raised by the firmware with every message, starting over after its birth message,
+	// so that a redelivered message is told apart from a new one reporting the same state
+	Seq uint64 `json:"seq,omitempty"`
+	// SentAt is when the firmware sent the message, in Unix seconds
+	SentAt int64 `json:"sent_at,omitempty"`
+	// Laptop is the asset tag of the laptop taken or returned, if the device reads them. Otherwise the events
+	// record the laptop stored in the slot
+	Laptop string `json:"laptop,omitempty"`
+	// Payload is the message as it was received, kept along with its events
+	Payload []byte `json:"-"`
+}
+
+// Event is the outcome of processing a message, reported per slot
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	Device    string    `json:"device,omitempty"`
+	RFID      string    `json:"RFID"`
+	Slot      string    `json:"slot,omitempty"`
+	Status    Status    `json:"status"`
+	Result    string    `json:"result"`
+}
+
+// ResultOK is the result of the events processed successfully
+const ResultOK = "ok"
+
+// ResultChanged is the result of the events standing for the slots changed in the db rather than by a message
+const ResultChanged = "changed"
+
+// Ack tells the device whether its message was stored, so that it can retry the message otherwise
+type Ack struct {
+	ID     string `json:"id,omitempty"`
+	RFID   string `json:"RFID"`
+	Status Status `json:"status"`
+	OK     bool   `json:"ok"`
+	Result string `json:"result"`
+}
+
+// Hello announces the server once it has started
+type Hello struct {
+	ServerID  string    `json:"server_id"`
+	Version   string    `json:"version"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// ValidationError describes why a message was rejected
+type ValidationError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+const (
+	maxRFIDLength   = 20
+	maxSlotIDLength = 5
+)
+
+// ValidateRFID checks the RFID fits the db schema
+func ValidateRFID(rfid string) error {
+	switch {
+	case rfid == "":
+		return &ValidationError{Field: "RFID", Reason: "must not be empty"}
+	case len(rfid) > maxRFIDLength:
+		return &ValidationError{Field: "RFID", Reason: fmt.Sprintf("must be at most %d characters long", maxRFIDLength)}
+	case strings.IndexFunc(rfid, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) != -1:
+		return &ValidationError{Field: "RFID", Reason: "must only contain letters and digits"}
+	}
+
+	return nil
+}
+
+// the roles of the users at the school, as the users table allows them
+const (
+	UserStudent = "student"
+	UserTeacher = "teacher"
+	UserStaff   = "staff"
+)
+
+// ValidateUserRole checks the role of the user is one the db schema allows
+func ValidateUserRole(role string) error {
+	switch role {
+	case UserStudent, UserTeacher, UserStaff:
+		return nil
+	}
+
+	return &ValidationError{
+		Field:  "role",
+		Reason: fmt.Sprintf("must be one of %s, %s and %s", UserStudent, UserTeacher, UserStaff),
+	}
+}
+
+// the statuses of the laptops, as the laptops table allows them
+const (
+	LaptopAvailable = "available"
+	LaptopInUse     = "in_use"
+	LaptopRepair    = "repair"
+	LaptopRetired   = "retired"
+)
+
+// ValidateLaptopStatus checks the status of the laptop is one the db schema allows
+func ValidateLaptopStatus(status string) error {
+	switch status {
+	case LaptopAvailable, LaptopInUse, LaptopRepair, LaptopRetired:
+		return nil
+	}
+
+	return &ValidationError{
+		Field:  "status",
+		Reason: fmt.Sprintf("must be one of %s, %s, %s and %s", LaptopAvailable, LaptopInUse, LaptopRepair, LaptopRetired),
+	}
+}
+
+// ValidateSlotID checks the canonical slot ID fits the db schema
+func ValidateSlotID(slotID string) error {
+	switch {
+	case slotID == "":
+		return &ValidationError{Field: "slots", Reason: "must not be empty"}
+	case len(slotID) > maxSlotIDLength:
+		return &ValidationError{Field: "slots", Reason: fmt.Sprintf("%q must be at most %d characters long", slotID, maxSlotIDLength)}
+	}
+
+	return nil
+}
+
+// Validate checks the message fits the db schema, returning a *ValidationError naming the offending field
+func (m *MQTTMessage) Validate() error {
+	switch m.Status {
+	case Placed, Taken, Scanned:
+	case Disconnected:
+		return nil
+	default:
+		return &ValidationError{Field: "status", Reason: fmt.Sprintf("unknown status %d", m.Status)}
+	}
+
+	if err := ValidateRFID(m.RFID); err != nil {
+		return err
+	}
+
+	if m.Status == Scanned {
+		return nil
+	}
+
+	slotIDs := m.SlotIDs()
+	if len(slotIDs) == 0 {
+		return &ValidationError{Field: "slots", Reason: "must not be empty"}
+	}
+
+	for _, slotID := range slotIDs {
+		if err := ValidateSlotID(slotID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SlotIDs splits the semicolon separated list of slots into canonical slot IDs, skipping empty entries
+func (m *MQTTMessage) SlotIDs() []string {
+	var slotIDs []string
+	for _, slotID := range strings.Split(m.Slots, ";") {
+		slotID = CanonicalizeSlotID(slotID)
+		if slotID == "" {
+			continue
+		}
+
+		slotIDs = append(slotIDs, slotID)
+	}
+
+	return slotIDs
+}
+
+// CanonicalizeSlotID maps the ways a slot ID gets spelled, e.g. "a1", " A1 " and "A-1", onto a single canonical ID.
+// The characters listed in SLOT_ID_SEPARATORS, "-_." unless set, are stripped, so setting it empty keeps them all.
+// Unless SLOT_ID_PRESERVE_CASE is set, letters are upper-cased
+func CanonicalizeSlotID(slotID string) string {
+	separators, ok := config.Lookup("SLOT_ID_SEPARATORS")
+	if !ok {
+		separators = "-_."
+	}
+
+	slotID = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || strings.ContainsRune(separators, r) {
+			return -1
+		}
+
+		return r
+	}, slotID)
+
+	if !config.Bool("SLOT_ID_PRESERVE_CASE", false) {
+		slotID = strings.ToUpper(slotID)
+	}
+
+	return slotID
 }
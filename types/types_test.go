@@ -0,0 +1,56 @@
+package types
+
+import "testing"
+
+func TestCanonicalizeSlotID(t *testing.T) {
+	tests := []struct {
+		slotID string
+		want   string
+	}{
+		{slotID: "A1", want: "A1"},
+		{slotID: "a1", want: "A1"},
+		{slotID: " A1 ", want: "A1"},
+		{slotID: "A-1", want: "A1"},
+		{slotID: "a_1", want: "A1"},
+		{slotID: "A.1", want: "A1"},
+		{slotID: "\tb - 12\n", want: "B12"},
+		{slotID: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := CanonicalizeSlotID(tt.slotID); got != tt.want {
+			t.Errorf("CanonicalizeSlotID(%q) = %q, want %q", tt.slotID, got, tt.want)
+		}
+	}
+}
+
+func TestCanonicalizeSlotIDSettings(t *testing.T) {
+	tests := []struct {
+		name         string
+		separators   *string
+		preserveCase string
+		slotID       string
+		want         string
+	}{
+		{name: "custom separators", separators: ptr("/"), slotID: "a/1-2", want: "A1-2"},
+		{name: "stripping turned off", separators: ptr(""), slotID: " a-1 ", want: "A-1"},
+		{name: "case preserved", preserveCase: "true", slotID: "a-1", want: "a1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.separators != nil {
+				t.Setenv("SLOT_ID_SEPARATORS", *tt.separators)
+			}
+			t.Setenv("SLOT_ID_PRESERVE_CASE", tt.preserveCase)
+
+			if got := CanonicalizeSlotID(tt.slotID); got != tt.want {
+				t.Errorf("CanonicalizeSlotID(%q) = %q, want %q", tt.slotID, got, tt.want)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
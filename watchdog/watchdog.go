@@ -0,0 +1,54 @@
+package watchdog
+
+import (
+	"time"
+)
+
+// Timer is the part of time.Timer the Watchdog drives
+type Timer interface {
+	Reset(d time.Duration) bool
+	Stop() bool
+}
+
+// Clock schedules the expiration, letting the tests fire it without waiting
+type Clock interface {
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+type realClock struct{}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// Watchdog is a dead-man's switch calling the expire callback once
+// if Feed has not been called for the whole window
+type Watchdog struct {
+	window time.Duration
+	timer  Timer
+}
+
+// New arms a Watchdog expiring after the window
+func New(window time.Duration, expire func(window time.Duration)) *Watchdog {
+	return NewWithClock(window, realClock{}, expire)
+}
+
+// NewWithClock arms a Watchdog expiring after the window as measured by the clock
+func NewWithClock(window time.Duration, clock Clock, expire func(window time.Duration)) *Watchdog {
+	return &Watchdog{
+		window: window,
+		timer: clock.AfterFunc(window, func() {
+			expire(window)
+		}),
+	}
+}
+
+// Feed postpones the expiration by a whole window, re-arming the switch if it has already fired
+func (w *Watchdog) Feed() {
+	w.timer.Reset(w.window)
+}
+
+// Stop disarms the switch
+func (w *Watchdog) Stop() {
+	w.timer.Stop()
+}
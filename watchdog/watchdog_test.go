@@ -0,0 +1,94 @@
+package watchdog
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock runs a single timer firing only when advanced past its deadline
+type fakeClock struct {
+	now      time.Duration
+	deadline time.Duration
+	armed    bool
+	f        func()
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.f = f
+	c.deadline = c.now + d
+	c.armed = true
+
+	return c
+}
+
+func (c *fakeClock) Reset(d time.Duration) bool {
+	wasArmed := c.armed
+	c.deadline = c.now + d
+	c.armed = true
+
+	return wasArmed
+}
+
+func (c *fakeClock) Stop() bool {
+	wasArmed := c.armed
+	c.armed = false
+
+	return wasArmed
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now += d
+	if c.armed && c.now >= c.deadline {
+		c.armed = false
+		c.f()
+	}
+}
+
+func TestWatchdog(t *testing.T) {
+	const window = time.Minute
+
+	clock := &fakeClock{}
+	alerts := 0
+	w := NewWithClock(window, clock, func(got time.Duration) {
+		if got != window {
+			t.Errorf("expired after %v, want %v", got, window)
+		}
+		alerts++
+	})
+
+	clock.Advance(window - time.Second)
+	if alerts != 0 {
+		t.Fatalf("alerts = %d before the window passed, want 0", alerts)
+	}
+
+	// a message resets the window
+	w.Feed()
+	clock.Advance(window - time.Second)
+	if alerts != 0 {
+		t.Fatalf("alerts = %d after a feed, want 0", alerts)
+	}
+
+	clock.Advance(time.Second)
+	if alerts != 1 {
+		t.Fatalf("alerts = %d after a silent window, want 1", alerts)
+	}
+
+	// the switch fires once until fed again
+	clock.Advance(window)
+	if alerts != 1 {
+		t.Fatalf("alerts = %d after another silent window, want 1", alerts)
+	}
+
+	w.Feed()
+	clock.Advance(window)
+	if alerts != 2 {
+		t.Fatalf("alerts = %d after re-arming, want 2", alerts)
+	}
+
+	w.Feed()
+	w.Stop()
+	clock.Advance(window)
+	if alerts != 2 {
+		t.Errorf("alerts = %d after stopping, want 2", alerts)
+	}
+}
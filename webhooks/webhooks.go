@@ -0,0 +1,189 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"letovo-computers-server/config"
+	"letovo-computers-server/models"
+	"letovo-computers-server/types"
+)
+
+// SignatureHeader carries the hex-encoded HMAC-SHA256 of the body, keyed with the secret of the webhook
+const SignatureHeader = "X-Signature-256"
+
+// eventNames are the names the webhooks filter the events by
+var eventNames = map[types.Status]string{
+	types.Placed:       "placed",
+	types.Taken:        "taken",
+	types.Scanned:      "scanned",
+	types.Disconnected: "disconnected",
+}
+
+// ValidEvent reports whether the name is one of the events the webhooks can be filtered by
+func ValidEvent(name string) bool {
+	for _, n := range eventNames {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Payload is the body posted to the webhooks
+type Payload struct {
+	Type  string      `json:"type"`
+	Event types.Event `json:"event"`
+}
+
+// Dispatcher delivers the events to the matching webhooks in the background
+type Dispatcher struct {
+	client *http.Client
+	queue  chan types.Event
+}
+
+// NewDispatcher creates a Dispatcher queueing up to size events
+func NewDispatcher(size int) *Dispatcher {
+	return &Dispatcher{
+		client: &http.Client{Timeout: config.Duration("WEBHOOK_TIMEOUT", 10*time.Second)},
+		queue:  make(chan types.Event, size),
+	}
+}
+
+// Dispatch queues the event for delivery, dropping it if the queue is full, so that slow webhooks
+// never hold up processing the messages
+func (d *Dispatcher) Dispatch(event types.Event) {
+	select {
+	case d.queue <- event:
+	default:
+		log.Warn().Str("RFID", event.RFID).Str("slot", event.Slot).Msg("webhook queue is full, dropped event")
+	}
+}
+
+// Run delivers the queued events with the given number of workers until ctx is done
+func (d *Dispatcher) Run(ctx context.Context, workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event := <-d.queue:
+					d.deliver(ctx, event)
+				}
+			}
+		}()
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, event types.Event) {
+	name := eventNames[event.Status]
+
+	hooks, err := models.Webhooks().AllG(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to fetch webhooks")
+		return
+	}
+
+	body, err := json.Marshal(Payload{Type: name, Event: event})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to marshal webhook payload")
+		return
+	}
+
+	for _, hook := range hooks {
+		if !matches(hook, name) {
+			continue
+		}
+
+		if err := d.post(ctx, hook, body); err != nil {
+			log.Error().Err(err).Int("webhook", hook.ID).Msgf("failed to deliver %s event to %s", name, hook.URL)
+		}
+	}
+}
+
+// matches reports whether the webhook is filtered by the event, all events matching an empty filter
+func matches(hook *models.Webhook, name string) bool {
+	if len(hook.Events) == 0 {
+		return true
+	}
+
+	for _, e := range hook.Events {
+		if e == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// post delivers the body, retrying up to WEBHOOK_ATTEMPTS times with the WEBHOOK_BACKOFF doubling after each failure
+func (d *Dispatcher) post(ctx context.Context, hook *models.Webhook, body []byte) error {
+	attempts := config.Int("WEBHOOK_ATTEMPTS", 5)
+	backoff := config.Duration("WEBHOOK_BACKOFF", time.Second)
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = d.postOnce(ctx, hook, body)
+		if err == nil || attempt == attempts {
+			break
+		}
+
+		log.Warn().Err(err).Int("webhook", hook.ID).Int("attempt", attempt).Msgf("retrying webhook in %s", backoff)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	return err
+}
+
+func (d *Dispatcher) postOnce(ctx context.Context, hook *models.Webhook, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if hook.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(hook.Secret, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+
+	return nil
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of the body keyed with the secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}